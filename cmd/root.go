@@ -2,16 +2,41 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/bartmika/mothership-server/internal/session"
 )
 
 var (
-	ipAddress   string
-	port        int
-	databaseUrl string
-	hmacSecret  string
+	ipAddress     string
+	port          int
+	databaseUrl   string
+	hmacSecret    string
+	sessionStore  string
+	redisAddress  string
+	redisPassword string
+	redisDB       int
+	redisTLS      bool
+	notifierType  string
+	notifierFile  string
+	smtpHost      string
+	smtpPort      int
+	smtpUsername  string
+	smtpPassword  string
+	smtpFrom      string
+	rootEmail     string
+	rootPassword  string
+	httpAddress   string
+	influxTCP     string
+	influxUDP     string
+
+	retentionInterval time.Duration
+
+	requireEmailVerification bool
 )
 
 var rootCmd = &cobra.Command{
@@ -29,3 +54,34 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// Returns the value of the environment variable or the fallback if it is not
+// set.
+func getEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// Exits if the `session_store` flag is not supported.
+func validateSessionStore() {
+	if sessionStore != "redis" && sessionStore != "memory" {
+		log.Fatalf("Unsupported session store: %v", sessionStore)
+	}
+}
+
+// Returns the session store picked by the `session_store` flag.
+func newSessionManager() session.SessionManager {
+	switch sessionStore {
+	case "memory":
+		return session.NewMemory()
+	default:
+		return session.NewRedis(&session.RedisOptions{
+			Address:  redisAddress,
+			Password: redisPassword,
+			DB:       redisDB,
+			TLS:      redisTLS,
+		})
+	}
+}
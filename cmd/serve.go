@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"log"
 	"os"
 	"os/signal"
 	"syscall"
-	// "time"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/bartmika/mothership-server/internal/controllers"
+	"github.com/bartmika/mothership-server/internal/notifier"
 	// "github.com/bartmika/mothership-server/utils"
 )
 
@@ -16,8 +19,27 @@ func init() {
 	// The following are optional and will have defaults placed when missing.
 	serveCmd.Flags().StringVarP(&ipAddress, "ip", "i", "localhost", "The ip address to bind this server to")
 	serveCmd.Flags().IntVarP(&port, "port", "p", 50051, "The port to run this server on")
+	serveCmd.Flags().StringVar(&httpAddress, "http_address", "", "The address to serve the Prometheus compatible HTTP API on, for example localhost:9090 (disabled if empty)")
+	serveCmd.Flags().StringVar(&influxTCP, "influx_tcp_address", "", "The address to accept InfluxDB line protocol on over TCP, for example localhost:8094 (disabled if empty)")
+	serveCmd.Flags().StringVar(&influxUDP, "influx_udp_address", "", "The address to accept InfluxDB line protocol on over UDP, for example localhost:8094 (disabled if empty)")
 	serveCmd.Flags().StringVarP(&databaseUrl, "database_url", "d", os.Getenv("MOTHERSHIP_SERVER_DATABASE_URL"), "The database URL to run this server on")
 	serveCmd.Flags().StringVarP(&hmacSecret, "hmac_secret", "s", os.Getenv("MOTHERSHIP_SERVER_HMAC_SECRET"), "The secret key to use in this server")
+	serveCmd.Flags().StringVar(&sessionStore, "session_store", "redis", "Where to keep the sessions, either redis or memory")
+	serveCmd.Flags().StringVar(&redisAddress, "redis_address", getEnv("MOTHERSHIP_SERVER_REDIS_ADDRESS", "localhost:6379"), "The address of the Redis server, or a comma separated list of Redis cluster nodes")
+	serveCmd.Flags().StringVar(&redisPassword, "redis_password", os.Getenv("MOTHERSHIP_SERVER_REDIS_PASSWORD"), "The password of the Redis server")
+	serveCmd.Flags().IntVar(&redisDB, "redis_db", 0, "The Redis database to use")
+	serveCmd.Flags().BoolVar(&redisTLS, "redis_tls", false, "Connect to the Redis server using TLS")
+	serveCmd.Flags().StringVar(&notifierType, "notifier", "log", "How to deliver emails to users, either log, file or smtp")
+	serveCmd.Flags().StringVar(&notifierFile, "notifier_file", "notifications.log", "The file to write emails to when using the file notifier")
+	serveCmd.Flags().StringVar(&smtpHost, "smtp_host", os.Getenv("MOTHERSHIP_SERVER_SMTP_HOST"), "The host of the SMTP server")
+	serveCmd.Flags().IntVar(&smtpPort, "smtp_port", 587, "The port of the SMTP server")
+	serveCmd.Flags().StringVar(&smtpUsername, "smtp_username", os.Getenv("MOTHERSHIP_SERVER_SMTP_USERNAME"), "The username to authenticate with the SMTP server")
+	serveCmd.Flags().StringVar(&smtpPassword, "smtp_password", os.Getenv("MOTHERSHIP_SERVER_SMTP_PASSWORD"), "The password to authenticate with the SMTP server")
+	serveCmd.Flags().StringVar(&smtpFrom, "smtp_from", os.Getenv("MOTHERSHIP_SERVER_SMTP_FROM"), "The address emails are sent from")
+	serveCmd.Flags().StringVar(&rootEmail, "root_email", os.Getenv("MOTHERSHIP_SERVER_ROOT_EMAIL"), "The email of the root user to create on startup if they do not exist")
+	serveCmd.Flags().StringVar(&rootPassword, "root_password", os.Getenv("MOTHERSHIP_SERVER_ROOT_PASSWORD"), "The password of the root user to create on startup")
+	serveCmd.Flags().DurationVar(&retentionInterval, "retention_interval", time.Hour, "How often the data points older than their retention are removed")
+	serveCmd.Flags().BoolVar(&requireEmailVerification, "require_email_verification", false, "Refuse to login users who have not verified their email")
 
 	// Make this sub-command part of our application.
 	rootCmd.AddCommand(serveCmd)
@@ -26,8 +48,35 @@ func init() {
 func doServe() {
 	// Convert the user inputted integer value to be a `time.Duration` type.
 
+	// Setup our session store.
+	manager := newSessionManager()
+
+	// Setup how we deliver emails.
+	var n notifier.Notifier
+	switch notifierType {
+	case "file":
+		n = notifier.NewFile(notifierFile)
+	case "smtp":
+		n = notifier.NewSMTP(&notifier.SMTPOptions{
+			Host:     smtpHost,
+			Port:     smtpPort,
+			Username: smtpUsername,
+			Password: smtpPassword,
+			From:     smtpFrom,
+		})
+	default:
+		n = notifier.NewLog()
+	}
+
 	// Setup our server.
-	server := controllers.New(ipAddress, port, databaseUrl, hmacSecret)
+	server := controllers.New(ipAddress, port, databaseUrl, hmacSecret, manager, n, requireEmailVerification, retentionInterval, httpAddress, influxTCP, influxUDP)
+
+	// Create the first root user of the installation.
+	if rootEmail != "" {
+		if err := server.BootstrapRootUser(context.Background(), rootEmail, rootPassword); err != nil {
+			log.Fatalf("Failed creating root user: %v", err)
+		}
+	}
 
 	// DEVELOPERS CODE:
 	// The following code will create an anonymous goroutine which will have a
@@ -54,7 +103,19 @@ var serveCmd = &cobra.Command{
 	Long:  `Run the gRPC server to allow other services to access this application`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Defensive code. ...
-		// Do nothing for now...
+		validateSessionStore()
+		if notifierType != "log" && notifierType != "file" && notifierType != "smtp" {
+			log.Fatalf("Unsupported notifier: %v", notifierType)
+		}
+		if notifierType == "smtp" && (smtpHost == "" || smtpFrom == "") {
+			log.Fatal("The smtp notifier requires the smtp_host and smtp_from flags")
+		}
+		if rootEmail != "" && rootPassword == "" {
+			log.Fatal("The root_email flag requires the root_password flag")
+		}
+		if retentionInterval <= 0 {
+			log.Fatal("The retention_interval flag must be greater than zero")
+		}
 
 		// Execute our command with our validated inputs.
 		doServe()
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bartmika/mothership-server/internal/controllers"
+	"github.com/bartmika/mothership-server/internal/notifier"
+	"github.com/bartmika/mothership-server/internal/session"
+)
+
+var (
+	tenantId    uint64
+	skipConfirm bool
+	precision   string
+)
+
+func init() {
+	tenantDeleteCmd.Flags().Uint64Var(&tenantId, "tenant_id", 0, "The id of the tenant to delete")
+	tenantDeleteCmd.MarkFlagRequired("tenant_id")
+	tenantDeleteCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Delete without asking for confirmation")
+	tenantDeleteCmd.Flags().StringVarP(&databaseUrl, "database_url", "d", os.Getenv("MOTHERSHIP_SERVER_DATABASE_URL"), "The database URL of the server")
+	tenantDeleteCmd.Flags().StringVar(&sessionStore, "session_store", "redis", "Where the sessions are kept, either redis or memory")
+	tenantDeleteCmd.Flags().StringVar(&redisAddress, "redis_address", getEnv("MOTHERSHIP_SERVER_REDIS_ADDRESS", "localhost:6379"), "The address of the Redis server, or a comma separated list of Redis cluster nodes")
+	tenantDeleteCmd.Flags().StringVar(&redisPassword, "redis_password", os.Getenv("MOTHERSHIP_SERVER_REDIS_PASSWORD"), "The password of the Redis server")
+	tenantDeleteCmd.Flags().IntVar(&redisDB, "redis_db", 0, "The Redis database to use")
+	tenantDeleteCmd.Flags().BoolVar(&redisTLS, "redis_tls", false, "Connect to the Redis server using TLS")
+
+	tenantMigratePrecisionCmd.Flags().Uint64Var(&tenantId, "tenant_id", 0, "The id of the tenant to migrate")
+	tenantMigratePrecisionCmd.MarkFlagRequired("tenant_id")
+	tenantMigratePrecisionCmd.Flags().StringVar(&precision, "precision", "", "The new timestamp precision, either s, ms, us or ns")
+	tenantMigratePrecisionCmd.MarkFlagRequired("precision")
+	tenantMigratePrecisionCmd.Flags().StringVarP(&databaseUrl, "database_url", "d", os.Getenv("MOTHERSHIP_SERVER_DATABASE_URL"), "The database URL of the server")
+
+	tenantCmd.AddCommand(tenantDeleteCmd)
+	tenantCmd.AddCommand(tenantMigratePrecisionCmd)
+
+	// Make this sub-command part of our application.
+	rootCmd.AddCommand(tenantCmd)
+}
+
+var tenantCmd = &cobra.Command{
+	Use:   "tenant",
+	Short: "Manage the tenants",
+	Long:  `Manage the tenants of this server from the command line.`,
+}
+
+func doTenantDelete() {
+	// Setup our controller without running the gRPC server.
+	server := controllers.New(ipAddress, port, databaseUrl, os.Getenv("MOTHERSHIP_SERVER_HMAC_SECRET"), newSessionManager(), notifier.NewLog(), false, retentionInterval, "", "", "")
+	defer server.Close()
+
+	// The running server would write the tenant's data back to disk.
+	if err := server.LockDataDir(); err != nil {
+		log.Fatalf("Failed deleting tenant #%v: %v", tenantId, err)
+	}
+
+	err := server.DeleteTenantById(context.Background(), tenantId, "command line")
+	if err != nil {
+		log.Fatalf("Failed deleting tenant #%v: %v", tenantId, err)
+	}
+	fmt.Printf("Tenant #%v has been deleted.\n", tenantId)
+}
+
+var tenantDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a tenant and all of its data",
+	Long: `Permanently delete a tenant along with its users, API keys, sessions and
+time-series data. The server must be stopped while deleting, a running
+server can delete a tenant with the DeleteTenant RPC instead. Run this from
+the directory the server keeps its tsdb directory in.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Defensive code. ...
+		validateSessionStore()
+
+		// Deleting cannot be undone so make sure the operator meant it.
+		if !skipConfirm {
+			fmt.Printf("This will permanently delete tenant #%v and all of its data.\n", tenantId)
+			fmt.Print("Type the tenant id to confirm: ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if strings.TrimSpace(answer) != strconv.FormatUint(tenantId, 10) {
+				log.Fatal("Confirmation did not match, nothing was deleted")
+			}
+		}
+
+		// Execute our command with our validated inputs.
+		doTenantDelete()
+	},
+}
+
+func doTenantMigratePrecision() {
+	// Setup our controller without running the gRPC server. Nobody is logged
+	// in by this command so the sessions do not need to be shared.
+	server := controllers.New(ipAddress, port, databaseUrl, os.Getenv("MOTHERSHIP_SERVER_HMAC_SECRET"), session.NewMemory(), notifier.NewLog(), false, retentionInterval, "", "", "")
+	defer server.Close()
+
+	if err := server.LockDataDir(); err != nil {
+		log.Fatalf("Failed migrating tenant #%v: %v", tenantId, err)
+	}
+
+	count, backupPath, err := server.MigrateTenantPrecision(context.Background(), tenantId, precision)
+	if err != nil {
+		log.Fatalf("Failed migrating tenant #%v: %v", tenantId, err)
+	}
+	fmt.Printf("Tenant #%v now uses the %q timestamp precision, %v data points were migrated.\n", tenantId, precision, count)
+	fmt.Printf("The old data was kept in %v, remove it once you have checked the migration.\n", backupPath)
+}
+
+var tenantMigratePrecisionCmd = &cobra.Command{
+	Use:   "migrate-precision",
+	Short: "Change the timestamp precision of a tenant with data",
+	Long: `Convert the time-series data of a tenant into another timestamp precision
+and save the precision as the tenant's storage setting. The server must be
+stopped while migrating. Run this from the directory the server keeps its
+tsdb directory in.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Execute our command with our validated inputs.
+		doTenantMigratePrecision()
+	},
+}
@@ -3,15 +3,16 @@ module github.com/bartmika/mothership-server
 go 1.16
 
 require (
-	github.com/dgrijalva/jwt-go v3.2.0+incompatible // indirect
-	github.com/go-redis/redis/v8 v8.11.3 // indirect
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/go-redis/redis/v8 v8.11.3
 	github.com/golang/protobuf v1.5.2
+	github.com/golang/snappy v0.0.4
 	github.com/google/uuid v1.3.0
 	github.com/jackc/pgx/v4 v4.13.0
-	github.com/nakabonne/tstorage v0.2.1 // indirect
+	github.com/nakabonne/tstorage v0.2.1
 	github.com/spf13/cobra v1.2.1
 	go.opentelemetry.io/otel v0.20.0 // indirect
-	golang.org/x/crypto v0.0.0-20210813211128-0a44fdfbc16e // indirect
+	golang.org/x/crypto v0.0.0-20210813211128-0a44fdfbc16e
 	google.golang.org/grpc v1.40.0
 	google.golang.org/protobuf v1.27.1
 )
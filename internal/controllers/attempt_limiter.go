@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+// attemptLimiter keeps track of failed attempts per key (ex: user id) and
+// refuses further attempts once too many failed within the window. This stops
+// anybody from brute forcing short codes such as one-time passwords.
+type attemptLimiter struct {
+	mu       sync.Mutex
+	max      int
+	window   time.Duration
+	attempts map[uint64]*failedAttempts
+}
+
+type failedAttempts struct {
+	count     int
+	firstTime time.Time
+}
+
+func newAttemptLimiter(max int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{
+		max:      max,
+		window:   window,
+		attempts: make(map[uint64]*failedAttempts),
+	}
+}
+
+// Allow returns false if the key failed too many times within the window.
+func (l *attemptLimiter) Allow(key uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.attempts[key]
+	if !ok {
+		return true
+	}
+	if time.Since(a.firstTime) > l.window {
+		delete(l.attempts, key)
+		return true
+	}
+	return a.count < l.max
+}
+
+// Fail records a failed attempt for the key.
+func (l *attemptLimiter) Fail(key uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.attempts[key]
+	if !ok || time.Since(a.firstTime) > l.window {
+		a = &failedAttempts{firstTime: time.Now()}
+		l.attempts[key] = a
+	}
+	a.count++
+}
+
+// Reset forgets the failed attempts of the key.
+func (l *attemptLimiter) Reset(key uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.attempts, key)
+}
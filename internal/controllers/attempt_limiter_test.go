@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAttemptLimiter(t *testing.T) {
+	l := newAttemptLimiter(3, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow(1) {
+			t.Fatalf("Allow after %v failed attempts = false, want true", i)
+		}
+		l.Fail(1)
+	}
+	if l.Allow(1) {
+		t.Errorf("Allow after 3 failed attempts = true, want false")
+	}
+
+	// Every key has its own attempts.
+	if !l.Allow(2) {
+		t.Errorf("Allow of another key = false, want true")
+	}
+
+	l.Reset(1)
+	if !l.Allow(1) {
+		t.Errorf("Allow after Reset = false, want true")
+	}
+}
+
+func TestAttemptLimiterWindow(t *testing.T) {
+	l := newAttemptLimiter(2, 50*time.Millisecond)
+
+	l.Fail(1)
+	l.Fail(1)
+	if l.Allow(1) {
+		t.Fatalf("Allow after 2 failed attempts = true, want false")
+	}
+
+	// The attempts are forgotten once the window has passed since the first.
+	time.Sleep(100 * time.Millisecond)
+	if !l.Allow(1) {
+		t.Errorf("Allow after the window = false, want true")
+	}
+	l.Fail(1)
+	if !l.Allow(1) {
+		t.Errorf("Allow after a failed attempt in a new window = false, want true")
+	}
+}
@@ -2,38 +2,70 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/nakabonne/tstorage"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/notifier"
 	"github.com/bartmika/mothership-server/internal/repositories"
 	"github.com/bartmika/mothership-server/internal/session"
+	"github.com/bartmika/mothership-server/internal/tsdb"
 	pb "github.com/bartmika/mothership-server/proto"
 )
 
 type Controller struct {
-	ipAddress   string
-	port        int
-	databaseUrl string
-	hmacSecret  string
-	dbpool      *pgxpool.Pool
-	manager     *session.SessionManager
-	grpcServer  *grpc.Server
-	tenantRepo  models.TenantRepository
-	userRepo    models.UserRepository
-	storageMap  map[uint64]tstorage.Storage
+	ipAddress           string
+	port                int
+	databaseUrl         string
+	hmacSecret          string
+	dbpool              *pgxpool.Pool
+	manager             session.SessionManager
+	notifier            notifier.Notifier
+	grpcServer          *grpc.Server
+	httpAddress         string
+	httpServer          *http.Server
+	lineTCPAddress      string
+	lineUDPAddress      string
+	lineServer          *lineProtocolServer
+	tenantRepo          models.TenantRepository
+	deletionRepo        models.TenantDeletionRepository
+	storageConfigRepo   models.TenantStorageConfigRepository
+	metricRetentionRepo models.MetricRetentionPolicyRepository
+	userRepo            models.UserRepository
+	apiKeyRepo          models.APIKeyRepository
+	invitationRepo      models.InvitationRepository
+	storageMap          map[uint64]*tenantStorage
+	storageMu           sync.RWMutex
+	dataLock            *tsdb.DirLock
+	totpLimiter         *attemptLimiter
+	tenantStates        *tenantStateCache
+	ingestion           *ingestionStats
+	retention           *retentionStats
+	retentionInterval   time.Duration
+	done                chan struct{}
+
+	// If true then users cannot login until they verified their email.
+	requireEmailVerification bool
+
 	pb.MothershipServer
+	pb.MothershipAdminServer
 }
 
-func New(ipAddress string, port int, databaseUrl string, hmacSecret string) *Controller {
+func New(ipAddress string, port int, databaseUrl string, hmacSecret string, manager session.SessionManager, notifier notifier.Notifier, requireEmailVerification bool, retentionInterval time.Duration, httpAddress string, lineTCPAddress string, lineUDPAddress string) *Controller {
 	dbpool, err := pgxpool.Connect(context.Background(), databaseUrl)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unable to connect to database: %v\n", err)
@@ -41,18 +73,40 @@ func New(ipAddress string, port int, databaseUrl string, hmacSecret string) *Con
 	}
 
 	tenantRepo := repositories.NewTenantRepo(dbpool)
+	deletionRepo := repositories.NewTenantDeletionRepo(dbpool)
+	storageConfigRepo := repositories.NewTenantStorageConfigRepo(dbpool)
+	metricRetentionRepo := repositories.NewMetricRetentionPolicyRepo(dbpool)
 	userRepo := repositories.NewUserRepo(dbpool)
+	apiKeyRepo := repositories.NewAPIKeyRepo(dbpool)
+	invitationRepo := repositories.NewInvitationRepo(dbpool)
 
 	return &Controller{
-		ipAddress:   ipAddress,
-		port:        port,
-		databaseUrl: databaseUrl,
-		hmacSecret:  hmacSecret,
-		dbpool:      dbpool,
-		tenantRepo:  tenantRepo,
-		userRepo:    userRepo,
-		manager:     session.New(),
-		grpcServer:  nil,
+		ipAddress:           ipAddress,
+		port:                port,
+		databaseUrl:         databaseUrl,
+		hmacSecret:          hmacSecret,
+		dbpool:              dbpool,
+		tenantRepo:          tenantRepo,
+		deletionRepo:        deletionRepo,
+		storageConfigRepo:   storageConfigRepo,
+		metricRetentionRepo: metricRetentionRepo,
+		userRepo:            userRepo,
+		apiKeyRepo:          apiKeyRepo,
+		invitationRepo:      invitationRepo,
+		manager:             manager,
+		notifier:            notifier,
+		grpcServer:          nil,
+		httpAddress:         httpAddress,
+		lineTCPAddress:      lineTCPAddress,
+		lineUDPAddress:      lineUDPAddress,
+		totpLimiter:         newAttemptLimiter(5, time.Minute*15),
+		tenantStates:        newTenantStateCache(tenantStateCacheTTL),
+		ingestion:           newIngestionStats(),
+		retention:           newRetentionStats(),
+		retentionInterval:   retentionInterval,
+		done:                make(chan struct{}),
+
+		requireEmailVerification: requireEmailVerification,
 	}
 }
 
@@ -69,6 +123,7 @@ func (s *Controller) RunMainRuntimeLoop() {
 	// Initialize our gRPC server using our TCP server.
 	grpcServer := grpc.NewServer(
 		withServerUnaryInterceptor(s),
+		withServerStreamInterceptor(s),
 	)
 
 	// Save reference to our application state.
@@ -77,26 +132,56 @@ func (s *Controller) RunMainRuntimeLoop() {
 	// For debugging purposes only.
 	log.Printf("Server is running on port %v", s.port)
 
-	//TODO: WRITE NICE EXPLANATION.
-	storageMap := make(map[uint64]tstorage.Storage)
+	// Make sure no sub-command changes the time-series data while we have it
+	// open.
+	if err := s.LockDataDir(); err != nil {
+		log.Fatalf("failed to lock the %v directory: %v", dataDir, err)
+	}
+
+	// Every tenant gets their own time-series storage which is opened with
+	// the tenant's storage settings.
+	storageMap := make(map[uint64]*tenantStorage)
 	tenantIds, err := s.tenantRepo.ListAllIds(context.Background())
+	if err != nil {
+		log.Fatalf("failed to list tenants: %v", err)
+	}
 	for _, tenantId := range tenantIds {
-		tid := strconv.FormatUint(tenantId, 10)
-		partitionDuration := time.Duration(24) * time.Hour
-		writeTimeout := time.Duration(60) * time.Second
-		storage, _ := tstorage.NewStorage(
-			tstorage.WithDataPath("tsdb/"+tid),
-			tstorage.WithTimestampPrecision(tstorage.Seconds),
-			tstorage.WithPartitionDuration(partitionDuration),
-			tstorage.WithWriteTimeout(writeTimeout),
-		)
+		storage, err := s.openStorage(context.Background(), tenantId)
+		if err != nil {
+			log.Fatalf("failed to open storage for tenant id #%v: %v", tenantId, err)
+		}
 		storageMap[tenantId] = storage
-		log.Println("TSDB ready for tenant id #" + tid)
+		log.Printf("TSDB ready for tenant id #%v\n", tenantId)
 	}
 	s.storageMap = storageMap
 
+	// Remove the data points which are older than their retention in the
+	// background.
+	go s.runRetentionJanitor(s.retentionInterval)
+
+	// Serve the Prometheus compatible HTTP API alongside the gRPC server if
+	// it was enabled.
+	if s.httpAddress != "" {
+		s.httpServer = &http.Server{Addr: s.httpAddress, Handler: s.newHTTPHandler()}
+		go func() {
+			log.Printf("HTTP API is running on %v", s.httpAddress)
+			if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("failed to serve http: %v", err)
+			}
+		}()
+	}
+
+	// Accept InfluxDB line protocol over raw TCP and UDP if it was enabled.
+	if s.lineTCPAddress != "" || s.lineUDPAddress != "" {
+		s.lineServer, err = s.listenLineProtocol(s.lineTCPAddress, s.lineUDPAddress)
+		if err != nil {
+			log.Fatalf("failed to listen for line protocol: %v", err)
+		}
+	}
+
 	// Block the main runtime loop for accepting and processing gRPC requests.
 	pb.RegisterMothershipServer(grpcServer, s)
+	pb.RegisterMothershipAdminServer(grpcServer, s)
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
@@ -107,20 +192,299 @@ func (s *Controller) RunMainRuntimeLoop() {
 func (s *Controller) StopMainRuntimeLoop() {
 	log.Printf("Starting graceful shutdown now...")
 
+	// Stop our background tasks.
+	close(s.done)
+
+	// Finish the HTTP requests and line protocol writes taking place at the
+	// moment before we close the storages they use.
+	if s.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			log.Printf("HTTP API shutdown failed: %v", err)
+		}
+		cancel()
+	}
+	if s.lineServer != nil {
+		s.lineServer.Close()
+	}
+
 	// Shutdown our implementation sub-system.
 	// Iterate through all the time-series data storage instances running.
-	tenantIds, _ := s.tenantRepo.ListAllIds(context.Background())
-	for _, tenantId := range tenantIds {
+	s.storageMu.Lock()
+	for tenantId, storage := range s.storageMap {
 		// Finish our database operations running.
-		storage := s.storageMap[tenantId]
 		storage.Close()
 		log.Printf("TSDB shutdown for tenant id #%v\n", tenantId)
 	}
+	s.storageMu.Unlock()
+	s.unlockDataDir()
 
 	// Finish our database operations running.
 	defer s.dbpool.Close()
 
+	// Disconnect from our session store.
+	defer s.manager.Close()
+
 	// Finish any RPC communication taking place at the moment before
 	// shutting down the gRPC server.
 	s.grpcServer.GracefulStop()
 }
+
+// Close releases the database connections and the session store. Use this
+// when the controller was created without running the main runtime loop, for
+// example by a sub-command.
+func (s *Controller) Close() {
+	s.unlockDataDir()
+	s.dbpool.Close()
+	s.manager.Close()
+}
+
+// LockDataDir locks the directory the time-series data is saved in until the
+// controller is closed. Sub-commands which change the time-series data must
+// call this first so they cannot run while the server has the data open.
+func (s *Controller) LockDataDir() error {
+	lock, err := tsdb.Lock(dataDir)
+	if err == tsdb.ErrLocked {
+		return fmt.Errorf("the %v directory is used by a running server, please stop the server first", dataDir)
+	}
+	if err != nil {
+		return err
+	}
+	s.dataLock = lock
+	return nil
+}
+
+// Utility function which releases the lock of the data directory, if we
+// hold it.
+func (s *Controller) unlockDataDir() {
+	if s.dataLock == nil {
+		return
+	}
+	if err := s.dataLock.Unlock(); err != nil {
+		log.Println("unlockDataDir | Unlock | err", err)
+	}
+	s.dataLock = nil
+}
+
+// The directory the time-series data of every tenant is saved in.
+const dataDir = "tsdb"
+
+// Utility function which returns the directory the tenant's time-series data
+// is saved in.
+func tenantDataPath(tenantId uint64) string {
+	return filepath.Join(dataDir, strconv.FormatUint(tenantId, 10))
+}
+
+// tenantStorage is the open time-series storage of a tenant along with the
+// settings it was opened with, which we need to convert the timestamps, and
+// the index of the series saved in it.
+//
+// DEVELOPERS NOTE:
+// The storage still accepts data points after it was closed but never saves
+// them, therefore closing waits until the data points being inserted are
+// saved and afterwards every insert fails with `errStorageClosed`.
+type tenantStorage struct {
+	tstorage.Storage
+	config *models.TenantStorageConfig
+	index  *tsdb.Index
+	mu     sync.RWMutex
+	closed bool
+}
+
+// The error returned by a tenant's storage once it was closed. The tenant's
+// storage may have been reopened in the meantime so look it up again.
+var errStorageClosed = status.Errorf(codes.Unavailable, "Time-series storage was closed, please try again")
+
+// Utility function which opens the time-series storage in the directory with
+// the settings along with its series index.
+func newTenantStorage(dataPath string, cfg *models.TenantStorageConfig) (*tenantStorage, error) {
+	storage, err := tsdb.NewStorage(dataPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	index, err := tsdb.OpenIndex(dataPath)
+	if err != nil {
+		storage.Close()
+		return nil, err
+	}
+	return &tenantStorage{Storage: storage, config: cfg, index: index}, nil
+}
+
+// InsertRows inserts the rows into the storage and adds their series to the
+// index. Every way we ingest data goes through here.
+func (ts *tenantStorage) InsertRows(rows []tstorage.Row) error {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if ts.closed {
+		return errStorageClosed
+	}
+	if err := ts.Storage.InsertRows(rows); err != nil {
+		return err
+	}
+	return ts.index.Add(rows)
+}
+
+// Select returns the data points of the series between start and end.
+func (ts *tenantStorage) Select(metric string, labels []tstorage.Label, start, end int64) ([]*tstorage.DataPoint, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if ts.closed {
+		return nil, errStorageClosed
+	}
+	return ts.Storage.Select(metric, labels, start, end)
+}
+
+// Close waits until the rows being inserted are saved, flushes the storage to
+// disk and closes the index. Closing it again does nothing.
+func (ts *tenantStorage) Close() error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.closed {
+		return nil
+	}
+	ts.closed = true
+	err := ts.Storage.Close()
+	if indexErr := ts.index.Close(); err == nil {
+		err = indexErr
+	}
+	return err
+}
+
+// Utility function which returns the timestamp precision the storage saves
+// its data points with.
+func (ts *tenantStorage) precision() string {
+	return ts.config.TimestampPrecision
+}
+
+// Utility function which opens the time-series storage of the tenant with the
+// tenant's storage settings.
+func (s *Controller) openStorage(ctx context.Context, tenantId uint64) (*tenantStorage, error) {
+	cfg, err := s.getStorageConfig(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return newTenantStorage(tenantDataPath(tenantId), cfg)
+}
+
+// Utility function which returns the storage settings of the tenant or the
+// defaults if the tenant never changed them.
+func (s *Controller) getStorageConfig(ctx context.Context, tenantId uint64) (*models.TenantStorageConfig, error) {
+	cfg, err := s.storageConfigRepo.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = models.DefaultTenantStorageConfig(tenantId)
+	}
+	return cfg, nil
+}
+
+// Utility function which returns the time-series storage of the tenant.
+func (s *Controller) getStorage(tenantId uint64) (*tenantStorage, error) {
+	s.storageMu.RLock()
+	defer s.storageMu.RUnlock()
+
+	storage, ok := s.storageMap[tenantId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Time-series storage for tenant #%v does not exist", tenantId)
+	}
+	return storage, nil
+}
+
+// Utility function which inserts the rows made by `toRows` for the precision
+// of the tenant's storage and returns the number of rows inserted. If the
+// storage was closed in the meantime, because it is being reopened, the
+// rows are inserted into the reopened storage.
+func (s *Controller) insertRows(tenantId uint64, toRows func(precision string) []tstorage.Row) (int, error) {
+	for attempt := 0; ; attempt++ {
+		// Lookup the dedicated time-series storage instance for our particular tenant.
+		storage, err := s.getStorage(tenantId)
+		if err != nil {
+			return 0, err
+		}
+
+		rows := toRows(storage.precision())
+		err = storage.InsertRows(rows)
+		if err == errStorageClosed && attempt == 0 {
+			continue
+		}
+		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return 0, err
+			}
+			if tsdb.IsOverloaded(err) {
+				return 0, status.Errorf(codes.ResourceExhausted, err.Error())
+			}
+			return 0, status.Errorf(codes.Internal, err.Error())
+		}
+		s.ingestion.Record(tenantId, len(rows))
+		return len(rows), nil
+	}
+}
+
+// Utility function which returns the data points of the series in the
+// tenant's storage between start (inclusive) and end (exclusive). If the
+// storage was closed in the meantime, because it is being reopened, the data
+// points are read from the reopened storage.
+func (s *Controller) selectPoints(tenantId uint64, metric string, labels []tstorage.Label, start int64, end int64) ([]*tstorage.DataPoint, error) {
+	for attempt := 0; ; attempt++ {
+		// Lookup the dedicated time-series storage instance for our particular tenant.
+		storage, err := s.getStorage(tenantId)
+		if err != nil {
+			return nil, err
+		}
+
+		points, err := storage.Select(metric, labels, start, end)
+		if err == errStorageClosed && attempt == 0 {
+			continue
+		}
+		if errors.Is(err, tstorage.ErrNoDataPoints) {
+			return []*tstorage.DataPoint{}, nil
+		}
+		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		return points, nil
+	}
+}
+
+// Utility function which saves the time-series storage of the tenant.
+func (s *Controller) setStorage(tenantId uint64, storage *tenantStorage) {
+	s.storageMu.Lock()
+	defer s.storageMu.Unlock()
+
+	s.storageMap[tenantId] = storage
+}
+
+// Utility function which closes the time-series storage of the tenant and
+// saves the storage returned by `reopen` in its place, if any. The function
+// gets the closed storage, or nil if the tenant had none, and runs while no
+// one can use the tenant's storage: requests for it wait until the function
+// is finished. Every place which closes a tenant's storage while the server
+// is running must go through here.
+func (s *Controller) swapStorage(tenantId uint64, reopen func(closed *tenantStorage) (*tenantStorage, error)) error {
+	s.storageMu.Lock()
+	defer s.storageMu.Unlock()
+
+	// Closing waits for the rows being inserted and flushes the data kept in
+	// memory to disk.
+	closed, ok := s.storageMap[tenantId]
+	if ok {
+		if err := closed.Close(); err != nil {
+			return status.Errorf(codes.Internal, err.Error())
+		}
+		delete(s.storageMap, tenantId)
+	}
+
+	storage, err := reopen(closed)
+	if storage != nil {
+		s.storageMap[tenantId] = storage
+	}
+	return err
+}
@@ -5,24 +5,26 @@ import (
 	"errors"
 	"io"
 	"log"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
-	tspb "github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
 	"github.com/nakabonne/tstorage"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
 	"github.com/bartmika/mothership-server/internal/utils"
 	pb "github.com/bartmika/mothership-server/proto"
 )
 
 func (s *Controller) Register(ctx context.Context, in *pb.RegistrationReq) (*pb.RegistrationRes, error) {
+	if isReservedTenantName(in.Company) {
+		return nil, errors.New("Company name is reserved")
+	}
 	doesExist, err := s.tenantRepo.CheckIfExistsByName(ctx, in.Company)
 	if err != nil {
 		return nil, err
@@ -49,13 +51,6 @@ func (s *Controller) Register(ctx context.Context, in *pb.RegistrationReq) (*pb.
 		CreatedTime:  time.Now(),
 		ModifiedTime: time.Now(),
 	}
-	err = s.tenantRepo.Insert(ctx, t)
-	if err != nil {
-		return nil, err
-	}
-
-	t, err = s.tenantRepo.GetByUuid(ctx, t.Uuid)
-
 	passwordPlain := strings.TrimSpace(in.Password)
 	passwordHash, err := utils.HashPassword(passwordPlain)
 	if err != nil {
@@ -63,7 +58,6 @@ func (s *Controller) Register(ctx context.Context, in *pb.RegistrationReq) (*pb.
 	}
 
 	u := &models.User{
-		TenantId:          t.Id,
 		Uuid:              uuid.NewString(),
 		Email:             in.Email,
 		FirstName:         in.FirstName,
@@ -76,26 +70,36 @@ func (s *Controller) Register(ctx context.Context, in *pb.RegistrationReq) (*pb.
 		PasswordAlgorithm: "bcrypt",
 		RoleId:            models.UserTenantAdminRoleId,
 	}
-	err = s.userRepo.Insert(ctx, u)
+	// The tenant and user are saved together so neither is left behind
+	// without the other.
+	err = s.tenantRepo.InsertWithUser(ctx, t, u)
 	if err != nil {
 		return nil, err
 	}
 
-	//TODO: WRITE A NICE EXPLANATION
-	partitionDuration := time.Duration(24) * time.Hour
-	writeTimeout := time.Duration(60) * time.Second
-	tid := strconv.FormatUint(t.Id, 10)
-	storage, _ := tstorage.NewStorage(
-		tstorage.WithDataPath("tsdb/"+tid),
-		tstorage.WithTimestampPrecision(tstorage.Seconds),
-		tstorage.WithPartitionDuration(partitionDuration),
-		tstorage.WithWriteTimeout(writeTimeout),
-	)
-	s.storageMap[t.Id] = storage
+	// Open the dedicated time-series storage of the new tenant. Without it
+	// the tenant cannot be used, so remove the tenant again to free its name
+	// and email for another try.
+	storage, err := s.openStorage(ctx, t.Id)
+	if err != nil {
+		if err := s.tenantRepo.DeleteById(ctx, t.Id); err != nil {
+			log.Println("Register | DeleteById | err", err)
+		}
+		return nil, err
+	}
+	s.setStorage(t.Id, storage)
 	log.Println("TSDB ready for tenant id #", t.Id)
 
+	// DEVELOPERS NOTE:
+	// The tenant is registered by now so failing to send the email must not
+	// fail the registration; the user can ask for another code with
+	// `ResendVerification`.
+	if err := s.sendEmailVerification(ctx, u); err != nil {
+		log.Println("Register | sendEmailVerification | err", err)
+	}
+
 	return &pb.RegistrationRes{
-		Message: "You have been successfully registered. Please login to begin using the system.",
+		Message: "You have been successfully registered. Please verify your email with the code we sent you and login to begin using the system.",
 	}, nil
 }
 
@@ -115,101 +119,105 @@ func (s *Controller) Login(ctx context.Context, in *pb.LoginReq) (*pb.LoginRes,
 		return nil, errors.New("Email or password are incorrect")
 	}
 
-	sessionUuid := uuid.NewString()
-	sessionExpiryTime := time.Hour * 24 * 7 // 1 week
+	if err := s.checkIsActive(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if s.requireEmailVerification && !user.WasEmailActivated {
+		return nil, status.Errorf(codes.FailedPrecondition, "Please verify your email before logging in")
+	}
 
-	err = s.manager.SaveUser(ctx, sessionUuid, user, sessionExpiryTime)
+	// Users with two-factor authentication must complete the login with
+	// their one-time password before they get their tokens.
+	if user.TotpEnabled {
+		challengeToken, err := utils.GenerateChallengeToken([]byte(s.hmacSecret), user.Id, challengeTokenExpiryTime)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.LoginRes{ChallengeToken: challengeToken, TotpRequired: true}, nil
+	}
+
+	accessToken, refreshToken, err := s.createSession(ctx, user, newFamilyUuid())
 	if err != nil {
 		return nil, err
 	}
 
-	b := []byte(s.hmacSecret)
-	accessToken, refreshToken, err := utils.GenerateJWTTokenPair(b, sessionUuid, sessionExpiryTime)
-
 	return &pb.LoginRes{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
 
 func (s *Controller) RefreshToken(ctx context.Context, in *pb.RefreshTokenReq) (*pb.RefreshTokenRes, error) {
-	return &pb.RefreshTokenRes{}, status.Errorf(codes.Unimplemented, "TODO: implement")
-}
-
-func (s *Controller) InsertTimeSeriesDatum(ctx context.Context, in *pb.TimeSeriesDatumReq) (*empty.Empty, error) {
-	// Get our authenticated user.
-	user := ctx.Value("user").(*models.User)
-
-	// Lookup the dedicated time-series storage instance for our particular tenant.
-	storage := s.storageMap[user.TenantId]
-
-	// Generate our labels, if there are any.
-	labels := []tstorage.Label{}
-	for _, label := range in.Labels {
-		labels = append(labels, tstorage.Label{Name: label.Name, Value: label.Value})
+	b := []byte(s.hmacSecret)
+	sessionUuid, familyUuid, err := utils.ProcessRefreshToken(b, strings.TrimSpace(in.Value))
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
 	}
 
-	// Generate our datapoint.
-	dataPoint := tstorage.DataPoint{Timestamp: in.Timestamp.Seconds, Value: in.Value}
-
-	err := storage.InsertRows([]tstorage.Row{
-		{
-			Metric:    in.Metric,
-			Labels:    labels,
-			DataPoint: dataPoint,
-		},
-	})
+	family, err := s.manager.GetTokenFamily(ctx, familyUuid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if family == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Session expired - please log in again")
+	}
 
-	return &empty.Empty{}, err
-}
+	// DEVELOPERS NOTE:
+	// Every `refresh token` may only be used once. If we get an older token
+	// from the family then somebody else is holding a copy of it and we have
+	// no way of telling who is the legitimate owner, therefore revoke the
+	// entire family and force the user to log in again.
+	if family.SessionUuid != sessionUuid {
+		s.revokeTokenFamily(ctx, familyUuid, family)
+		return nil, status.Errorf(codes.Unauthenticated, "Refresh token was already used - please log in again")
+	}
 
-// Utility function which will return the `User` stored in our session for the
-// associated `access token` which was provided by the incoming context
-// (which was included by the client when making the `InsertTimeSeriesData`
-// RPC request). In theory this function could be removed and somesort of
-// `ClientSreamInterceptor` be writte instead but for now use this utility
-// function.
-func (s *Controller) getUserFromInsertTimeSeriesDataMiddleware(stream pb.Mothership_InsertTimeSeriesDataServer) (*models.User, error) {
-	ctx, cancel := context.WithCancel(stream.Context())
-	defer cancel()
-
-	md, ok := metadata.FromIncomingContext(ctx) // get context from stream
-	if !ok {
-		return nil, status.Errorf(codes.InvalidArgument, "Retrieving metadata is failed")
-    }
-
-	authHeader, ok := md["authorization"]
-	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "Authorization token is not supplied")
-    }
-
-	token := authHeader[0]
-
-	sessionUuid, err := utils.ProcessBearerToken([]byte(s.hmacSecret), token)
+	// Always lookup the latest copy of the user as their account details could
+	// of changed since they logged in.
+	user, err := s.userRepo.GetById(ctx, family.UserId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
+	if user == nil {
+		s.revokeTokenFamily(ctx, familyUuid, family)
+		return nil, status.Errorf(codes.Unauthenticated, "Session expired - please log in again")
+	}
+	if err := s.checkIsActive(ctx, user); err != nil {
+		s.revokeTokenFamily(ctx, familyUuid, family)
+		return nil, err
+	}
 
-	user, err := s.manager.GetUser(ctx, sessionUuid)
+	accessToken, refreshToken, err := s.rotateSession(ctx, user, familyUuid, family)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, err
 	}
 
-	return user, nil
+	return &pb.RefreshTokenRes{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
 
-func (s *Controller) InsertTimeSeriesData(stream pb.Mothership_InsertTimeSeriesDataServer) error {
-	// Extract the `User` account associated with the incoming context 'access
-	// token' value sent by the client with this RPC request.
-    user, err := s.getUserFromInsertTimeSeriesDataMiddleware(stream)
+func (s *Controller) InsertTimeSeriesDatum(ctx context.Context, in *pb.TimeSeriesDatumReq) (*empty.Empty, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	_, err := s.insertRows(user.TenantId, func(precision string) []tstorage.Row {
+		return []tstorage.Row{toRow(in, precision)}
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Lookup the dedicated time-series storage instance for our particular tenant.
-	storage := s.storageMap[user.TenantId]
+	return &empty.Empty{}, nil
+}
+
+func (s *Controller) InsertTimeSeriesData(stream pb.Mothership_InsertTimeSeriesDataServer) error {
+	// Get our authenticated user (which the stream interceptor saved).
+	user := stream.Context().Value("user").(*models.User)
 
 	// DEVELOPERS NOTE:
 	// If you don't understand how server side streaming works using gRPC then
 	// please visit the documentation to get an understanding:
 	// https://grpc.io/docs/languages/go/basics/#server-side-streaming-rpc-1
+	//
+	// The stream may outlive the tenant's storage, which is reopened when its
+	// settings change, so the storage is looked up for every datum.
 
 	// Wait and receieve the stream from the client.
 	for {
@@ -221,50 +229,22 @@ func (s *Controller) InsertTimeSeriesData(stream pb.Mothership_InsertTimeSeriesD
 			return err
 		}
 
-		// Generate our labels, if there are any.
-		labels := []tstorage.Label{}
-		for _, label := range datum.Labels {
-			labels = append(labels, tstorage.Label{Name: label.Name, Value: label.Value})
-		}
-
-		// Generate our datapoint.
-		dataPoint := tstorage.DataPoint{Timestamp: datum.Timestamp.Seconds, Value: datum.Value}
-
-		err = storage.InsertRows([]tstorage.Row{
-			{
-				Metric:    datum.Metric,
-				Labels:    labels,
-				DataPoint: dataPoint,
-			},
+		_, err = s.insertRows(user.TenantId, func(precision string) []tstorage.Row {
+			return []tstorage.Row{toRow(datum, precision)}
 		})
+		if err != nil {
+			return err
+		}
 	}
-
-	return nil
 }
 
 func (s *Controller) InsertBulkTimeSeriesData(ctx context.Context, in *pb.BulkTimeSeriesDataReq) (*empty.Empty, error) {
 	// Get our authenticated user.
 	user := ctx.Value("user").(*models.User)
 
-	// Lookup the dedicated time-series storage instance for our particular tenant.
-	storage := s.storageMap[user.TenantId]
-
 	for _, datum := range in.Data {
-		// Generate our labels, if there are any.
-		labels := []tstorage.Label{}
-		for _, label := range datum.Labels {
-			labels = append(labels, tstorage.Label{Name: label.Name, Value: label.Value})
-		}
-
-		// Generate our datapoint.
-		dataPoint := tstorage.DataPoint{Timestamp: datum.Timestamp.Seconds, Value: datum.Value}
-
-		err := storage.InsertRows([]tstorage.Row{
-			{
-				Metric:    datum.Metric,
-				Labels:    labels,
-				DataPoint: dataPoint,
-			},
+		_, err := s.insertRows(user.TenantId, func(precision string) []tstorage.Row {
+			return []tstorage.Row{toRow(datum, precision)}
 		})
 		if err != nil {
 			return &empty.Empty{}, err
@@ -279,31 +259,78 @@ func (s *Controller) SelectBulkTimeSeriesData(ctx context.Context, in *pb.Filter
 	user := ctx.Value("user").(*models.User)
 
 	// Lookup the dedicated time-series storage instance for our particular tenant.
-	storage := s.storageMap[user.TenantId]
-
-    // The results variable to return.
-	results := []*pb.DataPointRes{}
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return nil, err
+	}
 
-	// Generate our labels, if there are any.
-	labels := []tstorage.Label{}
-	for _, label := range in.Labels {
-		labels = append(labels, tstorage.Label{Name: label.Name, Value: label.Value})
+	if len(in.Matchers) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Matchers are only supported by SelectSeries")
+	}
+	start, end, err := toStorageRange(in, storage.precision())
+	if err != nil {
+		return nil, err
+	}
+	downsample, err := s.newDownsampler(ctx, user.TenantId, in, storage.precision())
+	if err != nil {
+		return nil, err
 	}
 
-	points, err := storage.Select(in.Metric, labels, in.Start.Seconds, in.End.Seconds)
+	points, err := storage.Select(in.Metric, toLabels(in.Labels), start, end)
 	if err != nil {
 		log.Println("SelectTimeSeriesData | storage.Select | err", err)
-		return &pb.SelectBulkRes{DataPoints: results}, nil
+		return &pb.SelectBulkRes{DataPoints: []*pb.DataPointRes{}}, nil
 	}
 
-	for _, point := range points {
-		ts := &tspb.Timestamp{
-			Seconds: point.Timestamp,
-			Nanos:   0,
+	if downsample != nil {
+		points, err = downsample(points)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
 		}
-		dataPoint := &pb.DataPointRes{Value: point.Value, Timestamp: ts}
-		results = append(results, dataPoint)
 	}
 
-	return &pb.SelectBulkRes{DataPoints: results}, nil
+	return &pb.SelectBulkRes{DataPoints: serializers.ToDataPointResList(points, storage.precision())}, nil
+}
+
+// Utility function which converts the range of the filter into the precision
+// the tenant's data is saved with.
+func toStorageRange(in *pb.FilterReq, precision string) (int64, int64, error) {
+	if in.Start == nil || in.End == nil {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "Start and end are required")
+	}
+	start, end := serializers.FromTimestamp(in.Start), serializers.FromTimestamp(in.End)
+	if !tsdb.IsStorageTime(start, precision) || !tsdb.IsStorageTime(end, precision) {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "Start and end must be times the %v precision can save", precision)
+	}
+	return tsdb.ToStorageTime(start, precision), tsdb.ToStorageTime(end, precision), nil
+}
+
+// Utility function which converts the labels of the request into the labels
+// used by the storage.
+func toLabels(arr []*pb.LabelReq) []tstorage.Label {
+	labels := []tstorage.Label{}
+	for _, label := range arr {
+		labels = append(labels, tstorage.Label{Name: label.Name, Value: label.Value})
+	}
+	return labels
+}
+
+// Utility function which converts the datum of the request into the row to
+// insert into a storage saving timestamps with the precision. A datum without
+// a timestamp is recorded at the current time.
+func toRow(datum *pb.TimeSeriesDatumReq, precision string) tstorage.Row {
+	t := time.Now()
+	if datum.Timestamp != nil {
+		t = serializers.FromTimestamp(datum.Timestamp)
+	}
+	return tsdb.NewRow(datum.Metric, toLabels(datum.Labels), t, datum.Value, precision)
+}
+
+// Utility function which converts every datum into its row.
+func toRows(data []*pb.TimeSeriesDatumReq, precision string) []tstorage.Row {
+	rows := make([]tstorage.Row, 0, len(data))
+	for _, datum := range data {
+		rows = append(rows, toRow(datum, precision))
+	}
+	return rows
 }
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// How long a root user may impersonate a tenant before they need to ask again.
+const impersonationExpiryTime = time.Hour
+
+// The name of the tenant the first root user is created in.
+const rootTenantName = "root"
+
+func (s *Controller) ListTenants(ctx context.Context, in *empty.Empty) (*pb.AdminListTenantsRes, error) {
+	tenants, err := s.tenantRepo.ListAll(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	res := make([]*pb.AdminTenantRes, 0, len(tenants))
+	for _, t := range tenants {
+		usersCount, err := s.userRepo.CountByTenantId(ctx, t.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		dataSize, partitionsCount := tenantStorageStats(t.Id)
+
+		res = append(res, &pb.AdminTenantRes{
+			Id:              t.Id,
+			Uuid:            t.Uuid,
+			Name:            t.Name,
+			Active:          t.State == models.TenantActiveState,
+			Timezone:        t.Timezone,
+			UsersCount:      usersCount,
+			DataSize:        dataSize,
+			PartitionsCount: partitionsCount,
+			CreatedTime:     serializers.ToTimestamp(t.CreatedTime),
+		})
+	}
+
+	return &pb.AdminListTenantsRes{Tenants: res}, nil
+}
+
+// Impersonate gives the root user a short lived, read-only session in the
+// tenant so they can see what the tenant sees when helping them.
+func (s *Controller) Impersonate(ctx context.Context, in *pb.AdminImpersonateReq) (*pb.AdminImpersonateRes, error) {
+	// Get our authenticated user.
+	user, err := s.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doesExist, err := s.tenantRepo.CheckIfExistsById(ctx, in.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if !doesExist {
+		return nil, status.Errorf(codes.NotFound, "Tenant #%v does not exist", in.TenantId)
+	}
+
+	// The session keeps the root user's id so it is listed (and revoked)
+	// along with the rest of their sessions.
+	u := *user
+	u.TenantId = in.TenantId
+	u.RoleId = models.UserTenantPlainRoleId
+
+	sess := newSession(ctx, &u, newFamilyUuid())
+	sess.ExpiryTime = time.Now().Add(impersonationExpiryTime)
+	sess.ReadOnly = true
+	err = s.manager.SaveSession(ctx, sess, impersonationExpiryTime)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// DEVELOPERS NOTE:
+	// We do not save a token family so the `refresh token` is useless and we
+	// do not hand it out; the root user must impersonate again once the
+	// session expires.
+	accessToken, _, err := utils.GenerateJWTTokenPair([]byte(s.hmacSecret), sess.Uuid, sess.FamilyUuid, impersonationExpiryTime)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	log.Printf("Root user id #%v is impersonating tenant id #%v\n", user.Id, in.TenantId)
+
+	return &pb.AdminImpersonateRes{
+		AccessToken: accessToken,
+		ExpiryTime:  serializers.ToTimestamp(sess.ExpiryTime),
+	}, nil
+}
+
+// ForceLogout revokes every session of the user or, if no user is given, of
+// every user in the tenant.
+func (s *Controller) ForceLogout(ctx context.Context, in *pb.AdminForceLogoutReq) (*empty.Empty, error) {
+	var users []*models.User
+	if in.UserUuid != "" {
+		u, err := s.userRepo.GetByUuid(ctx, in.UserUuid)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if u == nil {
+			return nil, status.Errorf(codes.NotFound, "User does not exist")
+		}
+		users = append(users, u)
+	} else if in.TenantId != 0 {
+		arr, err := s.userRepo.ListByTenantId(ctx, in.TenantId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		users = arr
+	} else {
+		return nil, status.Errorf(codes.InvalidArgument, "Either the user uuid or tenant id is required")
+	}
+
+	for _, u := range users {
+		if err := s.revokeAllSessions(ctx, u.Id, ""); err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *Controller) GetIngestionStats(ctx context.Context, in *empty.Empty) (*pb.AdminIngestionStatsRes, error) {
+	res := &pb.AdminIngestionStatsRes{
+		StartTime: serializers.ToTimestamp(s.ingestion.startTime),
+		Tenants:   []*pb.AdminTenantIngestionRes{},
+	}
+	for _, t := range s.ingestion.Snapshot() {
+		res.TotalRows += t.TotalRows
+		res.RowsPerSecond += t.RowsPerSecond
+		res.Tenants = append(res.Tenants, &pb.AdminTenantIngestionRes{
+			TenantId:      t.TenantId,
+			TotalRows:     t.TotalRows,
+			RowsPerSecond: t.RowsPerSecond,
+		})
+	}
+	return res, nil
+}
+
+func (s *Controller) GetRetentionStats(ctx context.Context, in *empty.Empty) (*pb.AdminRetentionStatsRes, error) {
+	res := &pb.AdminRetentionStatsRes{
+		Tenants: []*pb.AdminTenantRetentionRes{},
+	}
+	for _, t := range s.retention.Snapshot() {
+		res.RemovedPartitions += t.RemovedPartitions
+		res.ReclaimedBytes += t.ReclaimedBytes
+		res.Tenants = append(res.Tenants, &pb.AdminTenantRetentionRes{
+			TenantId:          t.TenantId,
+			RemovedPartitions: t.RemovedPartitions,
+			ReclaimedBytes:    t.ReclaimedBytes,
+			LastRunTime:       serializers.ToTimestamp(t.LastRunTime),
+		})
+	}
+	return res, nil
+}
+
+// BootstrapRootUser creates the root user (and the tenant they belong to) if
+// no user with the email exists yet. This is how the very first root user of
+// an installation is created.
+func (s *Controller) BootstrapRootUser(ctx context.Context, email string, password string) error {
+	email = strings.TrimSpace(email)
+	password = strings.TrimSpace(password)
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user != nil {
+		if user.RoleId != models.UserRootRoleId {
+			log.Printf("Skipped creating root user because %v belongs to a regular user\n", email)
+		}
+		return nil
+	}
+
+	// Re-use the root tenant in case the previous root user was deleted.
+	tenant, err := s.getOrCreateRootTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	u := &models.User{
+		TenantId:          tenant.Id,
+		Uuid:              uuid.NewString(),
+		Email:             email,
+		FirstName:         "Root",
+		State:             models.UserActiveState,
+		Timezone:          tenant.Timezone,
+		CreatedTime:       time.Now(),
+		ModifiedTime:      time.Now(),
+		PasswordHash:      passwordHash,
+		PasswordAlgorithm: "bcrypt",
+		RoleId:            models.UserRootRoleId,
+		WasEmailActivated: true,
+	}
+	err = s.userRepo.Insert(ctx, u)
+	if err != nil {
+		return err
+	}
+	log.Printf("Created root user %v in tenant id #%v\n", email, tenant.Id)
+	return nil
+}
+
+// Utility function which returns the tenant our root users belong to,
+// creating it if it does not exist.
+func (s *Controller) getOrCreateRootTenant(ctx context.Context) (*models.Tenant, error) {
+	t, err := s.tenantRepo.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		return t, nil
+	}
+
+	t = &models.Tenant{
+		Uuid:         uuid.NewString(),
+		Name:         rootTenantName,
+		State:        models.TenantActiveState,
+		Timezone:     "utc",
+		IsRoot:       true,
+		CreatedTime:  time.Now(),
+		ModifiedTime: time.Now(),
+	}
+	err = s.tenantRepo.Insert(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return s.tenantRepo.GetByUuid(ctx, t.Uuid)
+}
+
+// Utility function which returns true if the name is the one of the root
+// tenant, which no other tenant may use.
+func isReservedTenantName(name string) bool {
+	return strings.EqualFold(strings.TrimSpace(name), rootTenantName)
+}
+
+// Utility function which returns the size in bytes and the number of
+// partitions of the tenant's time-series data on disk.
+func tenantStorageStats(tenantId uint64) (int64, int64) {
+	dataPath := tenantDataPath(tenantId)
+	dataSize, err := utils.DirSize(dataPath)
+	if err != nil {
+		log.Println("tenantStorageStats | DirSize | err", err)
+	}
+
+	var partitionsCount int64
+	files, err := ioutil.ReadDir(dataPath)
+	if err != nil && !os.IsNotExist(err) {
+		log.Println("tenantStorageStats | ReadDir | err", err)
+	}
+	for _, f := range files {
+		if f.IsDir() && strings.HasPrefix(f.Name(), "p-") {
+			partitionsCount++
+		}
+	}
+	return dataSize, partitionsCount
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func (s *Controller) CreateAPIKey(ctx context.Context, in *pb.CreateAPIKeyReq) (*pb.CreateAPIKeyRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	if len(in.Scopes) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "At least one scope is required")
+	}
+	for _, scope := range in.Scopes {
+		if !utils.Contains(models.APIKeyScopes, scope) {
+			return nil, status.Errorf(codes.InvalidArgument, "Scope %q is not supported", scope)
+		}
+	}
+
+	expiryTime := serializers.FromOptionalTimestamp(in.ExpiryTime)
+	if expiryTime != nil && expiryTime.Before(time.Now()) {
+		return nil, status.Errorf(codes.InvalidArgument, "Expiry time must be in the future")
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	m := &models.APIKey{
+		Uuid:         uuid.NewString(),
+		TenantId:     user.TenantId,
+		UserId:       user.Id,
+		Name:         strings.TrimSpace(in.Name),
+		Prefix:       prefix,
+		KeyHash:      utils.HashToken(key),
+		Scopes:       in.Scopes,
+		State:        models.APIKeyActiveState,
+		ExpiryTime:   expiryTime,
+		CreatedTime:  time.Now(),
+		ModifiedTime: time.Now(),
+	}
+	err = s.apiKeyRepo.Insert(ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// DEVELOPERS NOTE:
+	// This is the only time the plaintext key leaves the server, afterwords we
+	// only keep the hash of it.
+	return &pb.CreateAPIKeyRes{ApiKey: serializers.ToAPIKeyRes(m), Key: key}, nil
+}
+
+func (s *Controller) ListAPIKeys(ctx context.Context, in *empty.Empty) (*pb.ListAPIKeysRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	arr, err := s.apiKeyRepo.ListByTenantId(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.ListAPIKeysRes{ApiKeys: serializers.ToAPIKeyResList(arr)}, nil
+}
+
+func (s *Controller) RevokeAPIKey(ctx context.Context, in *pb.RevokeAPIKeyReq) (*empty.Empty, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	m, err := s.apiKeyRepo.GetByUuid(ctx, in.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if m == nil || m.TenantId != user.TenantId {
+		return nil, status.Errorf(codes.NotFound, "API key does not exist")
+	}
+
+	m.State = models.APIKeyRevokedState
+	m.ModifiedTime = time.Now()
+	err = s.apiKeyRepo.UpdateById(ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &empty.Empty{}, nil
+}
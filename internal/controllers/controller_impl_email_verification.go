@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/notifier"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+const emailVerificationExpiryTime = time.Hour * 24
+
+func (s *Controller) VerifyEmail(ctx context.Context, in *pb.VerifyEmailReq) (*pb.VerifyEmailRes, error) {
+	email := strings.TrimSpace(in.Email)
+	code := strings.TrimSpace(in.Code)
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Verification code is invalid")
+	}
+	if user.WasEmailActivated {
+		return &pb.VerifyEmailRes{Message: "Your email has already been verified."}, nil
+	}
+	if user.EmailVerificationCode == "" || code == "" || !utils.CheckTokenHash(code, user.EmailVerificationCode) {
+		return nil, status.Errorf(codes.InvalidArgument, "Verification code is invalid")
+	}
+	if time.Now().After(user.EmailVerificationExpiryTime) {
+		return nil, status.Errorf(codes.InvalidArgument, "Verification code expired - please request a new one")
+	}
+
+	user.WasEmailActivated = true
+	user.EmailVerificationCode = ""
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.VerifyEmailRes{Message: "Your email has been verified."}, nil
+}
+
+func (s *Controller) ResendVerification(ctx context.Context, in *pb.ResendVerificationReq) (*pb.ResendVerificationRes, error) {
+	// DEVELOPERS NOTE:
+	// We always return the same message so nobody can use this RPC to find
+	// out which emails have an account with us.
+	res := &pb.ResendVerificationRes{
+		Message: "If an unverified account exists for this email then you will receive a verification code shortly.",
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(in.Email))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil || user.WasEmailActivated {
+		return res, nil
+	}
+
+	if err := s.sendEmailVerification(ctx, user); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return res, nil
+}
+
+// Utility function which generates a new verification code for the user and
+// sends it to their email.
+func (s *Controller) sendEmailVerification(ctx context.Context, user *models.User) error {
+	code, err := utils.NewSecureToken(24)
+	if err != nil {
+		return err
+	}
+
+	// Only the hash of the code is kept so a leaked database cannot be used
+	// to verify emails.
+	user.EmailVerificationCode = utils.HashToken(code)
+	user.EmailVerificationExpiryTime = time.Now().Add(emailVerificationExpiryTime)
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	m := &notifier.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Welcome to the mothership!\n\n"+
+			"Your email verification code is: %s\n\n"+
+			"The code expires in %v.",
+			code, emailVerificationExpiryTime),
+	}
+	go func() {
+		if err := s.notifier.Send(context.Background(), m); err != nil {
+			log.Println("sendEmailVerification | Send | err", err)
+		}
+	}()
+	return nil
+}
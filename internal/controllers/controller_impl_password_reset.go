@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/notifier"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+const passwordResetExpiryTime = time.Hour
+
+func (s *Controller) RequestPasswordReset(ctx context.Context, in *pb.RequestPasswordResetReq) (*pb.RequestPasswordResetRes, error) {
+	// DEVELOPERS NOTE:
+	// We always return the same message so nobody can use this RPC to find
+	// out which emails have an account with us.
+	res := &pb.RequestPasswordResetRes{
+		Message: "If an account exists for this email then you will receive a password reset code shortly.",
+	}
+
+	email := strings.TrimSpace(in.Email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return res, nil
+	}
+
+	code, err := utils.NewSecureToken(24)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// Only the hash of the code is kept so a leaked database cannot be used
+	// to reset passwords.
+	user.PrAccessCode = utils.HashToken(code)
+	user.PrExpiryTime = time.Now().Add(passwordResetExpiryTime)
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// Send in the background so the response time does not give away
+	// whether the account exists.
+	m := &notifier.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Somebody requested to reset the password of your account.\n\n"+
+			"Your password reset code is: %s\n\n"+
+			"The code expires in %v. If you did not request this then you can ignore this message.",
+			code, passwordResetExpiryTime),
+	}
+	go func() {
+		if err := s.notifier.Send(context.Background(), m); err != nil {
+			log.Println("RequestPasswordReset | Send | err", err)
+		}
+	}()
+
+	return res, nil
+}
+
+func (s *Controller) ConfirmPasswordReset(ctx context.Context, in *pb.ConfirmPasswordResetReq) (*pb.ConfirmPasswordResetRes, error) {
+	email := strings.TrimSpace(in.Email)
+	code := strings.TrimSpace(in.Code)
+	passwordPlain := strings.TrimSpace(in.Password)
+	if passwordPlain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Password is required")
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil || user.PrAccessCode == "" || code == "" || !utils.CheckTokenHash(code, user.PrAccessCode) {
+		return nil, status.Errorf(codes.InvalidArgument, "Password reset code is invalid")
+	}
+	if time.Now().After(user.PrExpiryTime) {
+		return nil, status.Errorf(codes.InvalidArgument, "Password reset code expired - please request a new one")
+	}
+
+	passwordHash, err := utils.HashPassword(passwordPlain)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// Clear the code so it can only be used once.
+	user.PasswordHash = passwordHash
+	user.PasswordAlgorithm = "bcrypt"
+	user.PrAccessCode = ""
+	user.PrExpiryTime = time.Now()
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// Whoever knew the old password must not stay logged in.
+	if err := s.revokeAllSessions(ctx, user.Id, ""); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.ConfirmPasswordResetRes{
+		Message: "Your password has been reset. Please login with your new password.",
+	}, nil
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/promql"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// Query evaluates the PromQL query against the tenant's data at the time of
+// the request, or now if it has no time.
+func (s *Controller) Query(ctx context.Context, in *pb.QueryReq) (*pb.QueryRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	t := time.Now()
+	if in.Time != nil {
+		t = serializers.FromTimestamp(in.Time)
+	}
+
+	res, err := promql.Query(&tenantQueryable{controller: s, tenantId: user.TenantId}, in.Query, t)
+	if err != nil {
+		return nil, toQueryError(err)
+	}
+	return serializers.ToQueryRes(res), nil
+}
+
+// QueryRange evaluates the PromQL query against the tenant's data at every
+// step between the start and end of the request.
+func (s *Controller) QueryRange(ctx context.Context, in *pb.QueryRangeReq) (*pb.QueryRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	if in.Start == nil || in.End == nil || in.Step == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Start, end and step are required")
+	}
+	step := time.Duration(in.Step.Seconds)*time.Second + time.Duration(in.Step.Nanos)
+	start := serializers.FromTimestamp(in.Start)
+	end := serializers.FromTimestamp(in.End)
+	res, err := promql.QueryRange(&tenantQueryable{controller: s, tenantId: user.TenantId}, in.Query, start, end, step)
+	if err != nil {
+		return nil, toQueryError(err)
+	}
+	return serializers.ToQueryRes(res), nil
+}
+
+// Utility function which converts the error of a query into a status. Every
+// error other than reading the storage is caused by the query itself.
+func toQueryError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.InvalidArgument, err.Error())
+}
+
+// tenantQueryable lets queries read the series of a tenant's storage. The
+// storage is looked up for every selection as it may be reopened while the
+// query is evaluated.
+type tenantQueryable struct {
+	controller *Controller
+	tenantId   uint64
+}
+
+// Select returns every series of the tenant's storage matching the matchers
+// along with their data points between start and end, both inclusive and in
+// Unix nanoseconds.
+func (q *tenantQueryable) Select(matchers []*tsdb.Matcher, start int64, end int64) ([]*promql.Series, error) {
+	for attempt := 0; ; attempt++ {
+		// Lookup the dedicated time-series storage instance for our particular tenant.
+		storage, err := q.controller.getStorage(q.tenantId)
+		if err != nil {
+			return nil, err
+		}
+
+		arr, err := selectSeries(storage, matchers, start, end)
+		if err == errStorageClosed && attempt == 0 {
+			continue
+		}
+		return arr, err
+	}
+}
+
+// Utility function which returns every series of the storage matching the
+// matchers along with their data points between start and end.
+func selectSeries(storage *tenantStorage, matchers []*tsdb.Matcher, start int64, end int64) ([]*promql.Series, error) {
+	precision := storage.precision()
+	from := tsdb.ToStorageTime(time.Unix(0, start), precision)
+	to := tsdb.ToStorageTime(time.Unix(0, end), precision)
+	if to < math.MaxInt64 {
+		// Include the data points at the end.
+		to++
+	}
+
+	entries := storage.index.Find(from, to, func(e *tsdb.IndexEntry) bool {
+		return tsdb.MatchSeries(&e.Series, matchers)
+	})
+	if len(entries) > maxSelectedSeries {
+		return nil, status.Errorf(codes.ResourceExhausted, "Query matches %v series, at most %v are allowed", len(entries), maxSelectedSeries)
+	}
+
+	arr := []*promql.Series{}
+	for _, e := range entries {
+		points, err := storage.Select(e.Metric, e.Labels, from, to)
+		if errors.Is(err, tstorage.ErrNoDataPoints) {
+			continue
+		}
+		if err == errStorageClosed {
+			return nil, err
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+
+		labels := []promql.Label{{Name: tsdb.MetricNameLabel, Value: e.Metric}}
+		for _, l := range e.Labels {
+			labels = append(labels, promql.Label{Name: l.Name, Value: l.Value})
+		}
+		series := &promql.Series{Labels: promql.NewLabels(labels...), Points: make([]promql.Point, 0, len(points))}
+		for _, p := range points {
+			series.Points = append(series.Points, promql.Point{T: tsdb.FromStorageTime(p.Timestamp, precision).UnixNano(), V: p.Value})
+		}
+		arr = append(arr, series)
+	}
+	return arr, nil
+}
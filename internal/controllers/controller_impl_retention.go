@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func (s *Controller) GetRetention(ctx context.Context, in *empty.Empty) (*pb.RetentionRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	return s.getRetentionRes(ctx, user.TenantId)
+}
+
+// UpdateRetention changes how long the tenant's data points are kept. The
+// retention of the tenant is left as is if it is not in the request. A metric
+// sent with a retention of zero goes back to the retention of the tenant.
+func (s *Controller) UpdateRetention(ctx context.Context, in *pb.UpdateRetentionReq) (*pb.RetentionRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	current, err := s.getStorageConfig(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cfg := *current
+	if in.RetentionSeconds != 0 {
+		cfg.Retention = time.Duration(in.RetentionSeconds) * time.Second
+		cfg.ModifiedTime = time.Now()
+	}
+	if err := validateStorageConfig(&cfg); err != nil {
+		return nil, err
+	}
+	for _, m := range in.Metrics {
+		if strings.TrimSpace(m.Metric) == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Metric is required")
+		}
+		if m.RetentionSeconds < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "Retention of metric %q cannot be negative", m.Metric)
+		}
+		if m.RetentionSeconds != 0 && time.Duration(m.RetentionSeconds)*time.Second < cfg.PartitionDuration {
+			return nil, status.Errorf(codes.InvalidArgument, "Retention of metric %q must be at least the partition duration", m.Metric)
+		}
+	}
+
+	if in.RetentionSeconds != 0 {
+		if err := s.storageConfigRepo.InsertOrUpdateByTenantId(ctx, &cfg); err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+	for _, m := range in.Metrics {
+		metric := strings.TrimSpace(m.Metric)
+		if m.RetentionSeconds == 0 {
+			err = s.metricRetentionRepo.DeleteByTenantIdAndMetric(ctx, user.TenantId, metric)
+		} else {
+			err = s.metricRetentionRepo.InsertOrUpdateByTenantIdAndMetric(ctx, &models.MetricRetentionPolicy{
+				TenantId:     user.TenantId,
+				Metric:       metric,
+				Retention:    time.Duration(m.RetentionSeconds) * time.Second,
+				CreatedTime:  time.Now(),
+				ModifiedTime: time.Now(),
+			})
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	return s.getRetentionRes(ctx, user.TenantId)
+}
+
+// Utility function which returns the retention of the tenant along with what
+// the retention janitor removed from it.
+func (s *Controller) getRetentionRes(ctx context.Context, tenantId uint64) (*pb.RetentionRes, error) {
+	cfg, err := s.getStorageConfig(ctx, tenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	policies, err := s.metricRetentionRepo.ListByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	r := s.retention.Get(tenantId)
+	return serializers.ToRetentionRes(cfg, policies, r.RemovedPartitions, r.ReclaimedBytes, r.LastRunTime), nil
+}
+
+// Utility function which removes the expired partitions of every tenant at
+// the interval until the server is stopped.
+func (s *Controller) runRetentionJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			tenantIds, err := s.tenantRepo.ListAllIds(context.Background())
+			if err != nil {
+				log.Println("runRetentionJanitor | ListAllIds | err", err)
+				continue
+			}
+			for _, tenantId := range tenantIds {
+				if err := s.enforceRetention(context.Background(), tenantId); err != nil {
+					log.Printf("runRetentionJanitor | enforceRetention | tenant id #%v | err %v\n", tenantId, err)
+				}
+			}
+		}
+	}
+}
+
+// Utility function which removes the partitions of the tenant which only have
+// data points older than their retention.
+func (s *Controller) enforceRetention(ctx context.Context, tenantId uint64) error {
+	cfg, err := s.getStorageConfig(ctx, tenantId)
+	if err != nil {
+		return err
+	}
+	policies, err := s.metricRetentionRepo.ListByTenantId(ctx, tenantId)
+	if err != nil {
+		return err
+	}
+	metricRetentions := make(map[string]time.Duration, len(policies))
+	for _, p := range policies {
+		metricRetentions[p.Metric] = p.Retention
+	}
+
+	storage, err := s.getStorage(tenantId)
+	if err != nil {
+		return err
+	}
+	dataPath := tenantDataPath(tenantId)
+	expired, err := tsdb.ExpiredPartitions(dataPath, time.Now(), storage.precision(), cfg.Retention, metricRetentions)
+	if err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		s.retention.Record(tenantId, 0, 0)
+		return nil
+	}
+
+	// DEVELOPERS NOTE:
+	// The storage keeps its partitions open so it must be closed before they
+	// are removed; requests for the tenant's storage wait until it has been
+	// reopened.
+	var removed, reclaimed int64
+	var removeErr error
+	err = s.swapStorage(tenantId, func(closed *tenantStorage) (*tenantStorage, error) {
+		if closed == nil {
+			return nil, nil // The tenant was deleted in the meantime.
+		}
+
+		for _, p := range expired {
+			size, err := utils.DirSize(p.Path)
+			if err != nil {
+				log.Println("enforceRetention | DirSize | err", err)
+			}
+			if err := os.RemoveAll(p.Path); err != nil {
+				removeErr = err
+				continue
+			}
+			removed++
+			reclaimed += size
+		}
+
+		// Everything is on disk now so the index can be rebuilt from what is
+		// left to drop the series which no longer have data points.
+		if err := tsdb.ResetIndex(dataPath); err != nil {
+			log.Println("enforceRetention | ResetIndex | err", err)
+		}
+		return newTenantStorage(dataPath, closed.config)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.retention.Record(tenantId, removed, reclaimed)
+	log.Printf("Removed %v expired partitions (%v bytes) of tenant #%v\n", removed, reclaimed, tenantId)
+	return removeErr
+}
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// The most series a single query may return.
+const maxSelectedSeries = 10000
+
+// SelectSeries returns the data points of every series matching the filter
+// along with the metric and labels of the series. Besides the metric and
+// labels of the filter, which must match exactly, the series can be matched
+// with the same label matchers Prometheus has.
+func (s *Controller) SelectSeries(ctx context.Context, in *pb.FilterReq) (*pb.SelectSeriesRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	// Lookup the dedicated time-series storage instance for our particular tenant.
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := toStorageRange(in, storage.precision())
+	if err != nil {
+		return nil, err
+	}
+	if start >= end {
+		return nil, status.Errorf(codes.InvalidArgument, "Start must be before end")
+	}
+	downsample, err := s.newDownsampler(ctx, user.TenantId, in, storage.precision())
+	if err != nil {
+		return nil, err
+	}
+	matchers, err := toMatchers(in)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := storage.index.Find(start, end, func(e *tsdb.IndexEntry) bool {
+		return tsdb.MatchSeries(&e.Series, matchers)
+	})
+	if len(entries) > maxSelectedSeries {
+		return nil, status.Errorf(codes.ResourceExhausted, "Filter matches %v series, at most %v are allowed", len(entries), maxSelectedSeries)
+	}
+
+	res := &pb.SelectSeriesRes{Series: []*pb.SeriesRes{}}
+	for _, e := range entries {
+		points, err := storage.Select(e.Metric, e.Labels, start, end)
+		if errors.Is(err, tstorage.ErrNoDataPoints) {
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if downsample != nil {
+			if points, err = downsample(points); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, err.Error())
+			}
+		}
+		res.Series = append(res.Series, serializers.ToSeriesRes(&e.Series, points, storage.precision()))
+	}
+	return res, nil
+}
+
+// ListMetrics returns the name of every metric the tenant has data points for,
+// optionally only the ones starting with the prefix or which have data points
+// in the range.
+func (s *Controller) ListMetrics(ctx context.Context, in *pb.ListMetricsReq) (*pb.ListMetricsRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	// Lookup the dedicated time-series storage instance for our particular tenant.
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := toIndexRange(in.Start, in.End, storage.precision())
+	entries := storage.index.Find(start, end, func(e *tsdb.IndexEntry) bool {
+		return strings.HasPrefix(e.Metric, in.Prefix)
+	})
+
+	metrics := []string{}
+	for _, e := range entries {
+		metrics = append(metrics, e.Metric)
+	}
+	return &pb.ListMetricsRes{Metrics: uniqueSorted(metrics)}, nil
+}
+
+// ListLabelNames returns the name of every label the tenant's series have,
+// optionally only of the metric, starting with the prefix or of the series
+// which have data points in the range.
+func (s *Controller) ListLabelNames(ctx context.Context, in *pb.ListLabelNamesReq) (*pb.ListLabelNamesRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	// Lookup the dedicated time-series storage instance for our particular tenant.
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := toIndexRange(in.Start, in.End, storage.precision())
+	entries := storage.index.Find(start, end, func(e *tsdb.IndexEntry) bool {
+		return in.Metric == "" || e.Metric == in.Metric
+	})
+
+	names := []string{}
+	for _, e := range entries {
+		for _, l := range e.Labels {
+			if strings.HasPrefix(l.Name, in.Prefix) {
+				names = append(names, l.Name)
+			}
+		}
+	}
+	return &pb.ListLabelNamesRes{Names: uniqueSorted(names)}, nil
+}
+
+// ListLabelValues returns every value of the label the tenant's series have,
+// optionally only of the metric, starting with the prefix or of the series
+// which have data points in the range.
+func (s *Controller) ListLabelValues(ctx context.Context, in *pb.ListLabelValuesReq) (*pb.ListLabelValuesRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	if in.Label == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Label is required")
+	}
+
+	// Lookup the dedicated time-series storage instance for our particular tenant.
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := toIndexRange(in.Start, in.End, storage.precision())
+	entries := storage.index.Find(start, end, func(e *tsdb.IndexEntry) bool {
+		return in.Metric == "" || e.Metric == in.Metric
+	})
+
+	values := []string{}
+	for _, e := range entries {
+		for _, l := range e.Labels {
+			if l.Name == in.Label && strings.HasPrefix(l.Value, in.Prefix) {
+				values = append(values, l.Value)
+			}
+		}
+	}
+	return &pb.ListLabelValuesRes{Values: uniqueSorted(values)}, nil
+}
+
+// Utility function which returns the matchers of the filter: the metric and
+// labels of the filter must match exactly along with every label matcher.
+func toMatchers(in *pb.FilterReq) ([]*tsdb.Matcher, error) {
+	matchers := []*tsdb.Matcher{}
+	if in.Metric != "" {
+		m, _ := tsdb.NewMatcher(tsdb.MatchEqual, tsdb.MetricNameLabel, in.Metric)
+		matchers = append(matchers, m)
+	}
+	for _, label := range in.Labels {
+		m, _ := tsdb.NewMatcher(tsdb.MatchEqual, label.Name, label.Value)
+		matchers = append(matchers, m)
+	}
+	for _, matcher := range in.Matchers {
+		if matcher.Name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Matcher name is required")
+		}
+		m, err := tsdb.NewMatcher(matcher.Type, matcher.Name, matcher.Value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Matcher %v%v%q is invalid: %v", matcher.Name, matcher.Type, matcher.Value, err)
+		}
+		matchers = append(matchers, m)
+	}
+	if len(matchers) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "At least one metric, label or matcher is required")
+	}
+	return matchers, nil
+}
+
+// Utility function which converts the optional range into the precision the
+// tenant's data is saved with. A missing start or end leaves the range open.
+func toIndexRange(start *tspb.Timestamp, end *tspb.Timestamp, precision string) (int64, int64) {
+	var from, to int64 = math.MinInt64, math.MaxInt64
+	if start != nil {
+		from = clampStorageTime(serializers.FromTimestamp(start), precision)
+	}
+	if end != nil {
+		to = clampStorageTime(serializers.FromTimestamp(end), precision)
+	}
+	return from, to
+}
+
+// Utility function which converts the time into the precision the tenant's
+// data is saved with, using the earliest or latest time the precision can
+// save for the times which do not fit.
+func clampStorageTime(t time.Time, precision string) int64 {
+	if tsdb.IsStorageTime(t, precision) {
+		return tsdb.ToStorageTime(t, precision)
+	}
+	if t.Unix() < 0 {
+		return math.MinInt64
+	}
+	return math.MaxInt64
+}
+
+// Utility function which returns the values sorted and without duplicates.
+func uniqueSorted(arr []string) []string {
+	sort.Strings(arr)
+	res := []string{}
+	for i, v := range arr {
+		if i > 0 && arr[i-1] == v {
+			continue
+		}
+		res = append(res, v)
+	}
+	return res
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/session"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+const sessionExpiryTime = time.Hour * 24 * 7 // 1 week
+
+func (s *Controller) Logout(ctx context.Context, in *empty.Empty) (*empty.Empty, error) {
+	sessionUuid, ok := ctx.Value("session_uuid").(string)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "You are not logged in with a session")
+	}
+
+	sess, err := s.manager.GetSession(ctx, sessionUuid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if sess != nil {
+		if err := s.revokeSession(ctx, sess); err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *Controller) ListSessions(ctx context.Context, in *empty.Empty) (*pb.ListSessionsRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+	sessionUuid, _ := ctx.Value("session_uuid").(string)
+
+	sessions, err := s.manager.ListSessionsByUserId(ctx, user.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.ListSessionsRes{Sessions: serializers.ToSessionResList(sessions, sessionUuid)}, nil
+}
+
+func (s *Controller) RevokeSession(ctx context.Context, in *pb.RevokeSessionReq) (*empty.Empty, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	sess, err := s.manager.GetSession(ctx, in.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if sess == nil || sess.User.Id != user.Id {
+		return nil, status.Errorf(codes.NotFound, "Session does not exist")
+	}
+
+	if err := s.revokeSession(ctx, sess); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *Controller) RevokeAllSessions(ctx context.Context, in *pb.RevokeAllSessionsReq) (*empty.Empty, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	keepSessionUuid := ""
+	if in.KeepCurrent {
+		keepSessionUuid, _ = ctx.Value("session_uuid").(string)
+	}
+
+	if err := s.revokeAllSessions(ctx, user.Id, keepSessionUuid); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Utility function which creates a new session for the user which belongs to
+// the token family and returns the `access token` and `refresh token`.
+func (s *Controller) createSession(ctx context.Context, user *models.User, familyUuid string) (string, string, error) {
+	sess := newSession(ctx, user, familyUuid)
+
+	err := s.manager.SaveSession(ctx, sess, sessionExpiryTime)
+	if err != nil {
+		return "", "", err
+	}
+
+	family := &session.TokenFamily{SessionUuid: sess.Uuid, UserId: user.Id}
+	err = s.manager.SaveTokenFamily(ctx, familyUuid, family, sessionExpiryTime+utils.RefreshTokenGracePeriod)
+	if err != nil {
+		return "", "", err
+	}
+
+	b := []byte(s.hmacSecret)
+	return utils.GenerateJWTTokenPair(b, sess.Uuid, familyUuid, sessionExpiryTime)
+}
+
+// Utility function which replaces the current session of the token family with
+// a brand new session and returns the new `access token` and `refresh token`.
+func (s *Controller) rotateSession(ctx context.Context, user *models.User, familyUuid string, family *session.TokenFamily) (string, string, error) {
+	sess := newSession(ctx, user, familyUuid)
+	newFamily := &session.TokenFamily{SessionUuid: sess.Uuid, UserId: user.Id}
+
+	ok, err := s.manager.RotateTokenFamily(ctx, familyUuid, family.SessionUuid, newFamily, sessionExpiryTime+utils.RefreshTokenGracePeriod)
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, err.Error())
+	}
+	if !ok {
+		// Another request rotated the family with the same token first.
+		s.revokeTokenFamily(ctx, familyUuid, family)
+		return "", "", status.Errorf(codes.Unauthenticated, "Refresh token was already used - please log in again")
+	}
+
+	err = s.manager.SaveSession(ctx, sess, sessionExpiryTime)
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, err.Error())
+	}
+
+	// The previous `access token` must stop working now.
+	if err := s.manager.DeleteSession(ctx, family.SessionUuid); err != nil {
+		log.Println("rotateSession | DeleteSession | err", err)
+	}
+
+	b := []byte(s.hmacSecret)
+	accessToken, refreshToken, err := utils.GenerateJWTTokenPair(b, sess.Uuid, familyUuid, sessionExpiryTime)
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, err.Error())
+	}
+	return accessToken, refreshToken, nil
+}
+
+// Utility function which deletes the token family and the session it
+// currently points to.
+func (s *Controller) revokeTokenFamily(ctx context.Context, familyUuid string, family *session.TokenFamily) {
+	if err := s.manager.DeleteTokenFamily(ctx, familyUuid); err != nil {
+		log.Println("revokeTokenFamily | DeleteTokenFamily | err", err)
+	}
+	if err := s.manager.DeleteSession(ctx, family.SessionUuid); err != nil {
+		log.Println("revokeTokenFamily | DeleteSession | err", err)
+	}
+	log.Printf("Revoked token family %v for user id #%v\n", familyUuid, family.UserId)
+}
+
+// Utility function which deletes the session along with its token family so
+// the `refresh token` cannot be used to bring the session back.
+func (s *Controller) revokeSession(ctx context.Context, sess *session.Session) error {
+	if err := s.manager.DeleteTokenFamily(ctx, sess.FamilyUuid); err != nil {
+		return err
+	}
+	return s.manager.DeleteSession(ctx, sess.Uuid)
+}
+
+// Utility function which revokes every session of the user except for the
+// session with the `keepSessionUuid` (if set).
+func (s *Controller) revokeAllSessions(ctx context.Context, userId uint64, keepSessionUuid string) error {
+	sessions, err := s.manager.ListSessionsByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, sess := range sessions {
+		if sess.Uuid == keepSessionUuid {
+			continue
+		}
+		if err := s.revokeSession(ctx, sess); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Utility function which returns the id of a new token family; every login
+// starts a new family.
+func newFamilyUuid() string {
+	return uuid.NewString()
+}
+
+// Utility function which creates the session for the user along with the
+// details of the client making the request. The session only caches the user
+// without their secrets; look the user up if you need those.
+func newSession(ctx context.Context, user *models.User, familyUuid string) *session.Session {
+	sess := &session.Session{
+		Uuid:        uuid.NewString(),
+		FamilyUuid:  familyUuid,
+		User:        user.WithoutSecrets(),
+		CreatedTime: time.Now(),
+		ExpiryTime:  time.Now().Add(sessionExpiryTime),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		sess.ClientIp = host
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+			sess.UserAgent = userAgent[0]
+		}
+	}
+	return sess
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// Utility function which returns true if the session of the `access token`
+// was not revoked.
+func isSessionActive(t *testing.T, s *Controller, accessToken string) bool {
+	sessionUuid, err := utils.ProcessBearerToken([]byte(s.hmacSecret), accessToken)
+	if err != nil {
+		t.Fatalf("ProcessBearerToken failed: %v", err)
+	}
+	sess, err := s.manager.GetSession(context.Background(), sessionUuid)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	return sess != nil
+}
+
+func TestRefreshToken(t *testing.T) {
+	s := newTestController(t)
+	user := &models.User{Id: 1, TenantId: testTenantId, RoleId: models.UserTenantAdminRoleId, State: models.UserActiveState}
+	s.userRepo = &testUserRepo{users: []*models.User{user}}
+	ctx := context.Background()
+
+	access1, refresh1, err := s.createSession(ctx, user, newFamilyUuid())
+	if err != nil {
+		t.Fatalf("createSession failed: %v", err)
+	}
+
+	// Every refresh replaces the session with a new one.
+	res2, err := s.RefreshToken(ctx, &pb.RefreshTokenReq{Value: refresh1})
+	if err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if isSessionActive(t, s, access1) || !isSessionActive(t, s, res2.AccessToken) {
+		t.Errorf("RefreshToken did not replace the session")
+	}
+	res3, err := s.RefreshToken(ctx, &pb.RefreshTokenReq{Value: res2.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken of the rotated token failed: %v", err)
+	}
+	if isSessionActive(t, s, res2.AccessToken) || !isSessionActive(t, s, res3.AccessToken) {
+		t.Errorf("RefreshToken did not replace the rotated session")
+	}
+
+	// Using a token again revokes the whole family, including the newest
+	// session and `refresh token`.
+	if _, err := s.RefreshToken(ctx, &pb.RefreshTokenReq{Value: refresh1}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RefreshToken of a used token = %v, want %v", err, codes.Unauthenticated)
+	}
+	if isSessionActive(t, s, res3.AccessToken) {
+		t.Errorf("RefreshToken of a used token did not revoke the newest session")
+	}
+	if _, err := s.RefreshToken(ctx, &pb.RefreshTokenReq{Value: res3.RefreshToken}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RefreshToken of a revoked family = %v, want %v", err, codes.Unauthenticated)
+	}
+}
+
+func TestRefreshTokenErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		// The user the session was created for, as saved in the database
+		// when the token is refreshed.
+		user *models.User
+		// Replaces the `refresh token` of the session if set.
+		token string
+		want  codes.Code
+	}{
+		{name: "malformed", user: &models.User{Id: 1, TenantId: testTenantId, State: models.UserActiveState}, token: "not-a-token", want: codes.Unauthenticated},
+		{name: "deactivated user", user: &models.User{Id: 1, TenantId: testTenantId, State: models.UserInactiveState}, want: codes.PermissionDenied},
+		{name: "deleted user", want: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestController(t)
+			repo := &testUserRepo{}
+			if tt.user != nil {
+				repo.users = append(repo.users, tt.user)
+			}
+			s.userRepo = repo
+			ctx := context.Background()
+
+			user := &models.User{Id: 1, TenantId: testTenantId, State: models.UserActiveState}
+			access, refresh, err := s.createSession(ctx, user, newFamilyUuid())
+			if err != nil {
+				t.Fatalf("createSession failed: %v", err)
+			}
+			if tt.token != "" {
+				refresh = tt.token
+			}
+
+			if _, err := s.RefreshToken(ctx, &pb.RefreshTokenReq{Value: refresh}); status.Code(err) != tt.want {
+				t.Errorf("RefreshToken = %v, want %v", err, tt.want)
+			}
+			// Only a token which belongs to the session revokes it.
+			if tt.token == "" && isSessionActive(t, s, access) {
+				t.Errorf("RefreshToken did not revoke the session")
+			}
+		})
+	}
+}
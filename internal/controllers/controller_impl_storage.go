@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// The smallest storage settings we accept so a tenant cannot make their
+// storage unusable.
+const (
+	minPartitionDuration = time.Minute
+	minWriteTimeout      = time.Second
+)
+
+func (s *Controller) GetStorageConfig(ctx context.Context, in *empty.Empty) (*pb.StorageConfigRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	cfg, err := s.getStorageConfig(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return serializers.ToStorageConfigRes(cfg), nil
+}
+
+// UpdateStorageConfig changes the storage settings of the tenant and reopens
+// the tenant's storage so they take effect right away. Any setting left out
+// of the request is not changed.
+func (s *Controller) UpdateStorageConfig(ctx context.Context, in *pb.UpdateStorageConfigReq) (*pb.StorageConfigRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	current, err := s.getStorageConfig(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cfg := *current
+	if in.PartitionDurationSeconds != 0 {
+		cfg.PartitionDuration = time.Duration(in.PartitionDurationSeconds) * time.Second
+	}
+	if in.TimestampPrecision != "" {
+		cfg.TimestampPrecision = in.TimestampPrecision
+	}
+	if in.WriteTimeoutSeconds != 0 {
+		cfg.WriteTimeout = time.Duration(in.WriteTimeoutSeconds) * time.Second
+	}
+	if in.RetentionSeconds != 0 {
+		cfg.Retention = time.Duration(in.RetentionSeconds) * time.Second
+	}
+	cfg.ModifiedTime = time.Now()
+
+	if err := validateStorageConfig(&cfg); err != nil {
+		return nil, err
+	}
+	if err := s.reopenStorage(ctx, current, &cfg); err != nil {
+		return nil, err
+	}
+
+	return serializers.ToStorageConfigRes(&cfg), nil
+}
+
+// Utility function which closes the tenant's storage, saves the new settings
+// and opens the storage again with them. Requests for the tenant's storage
+// wait until it has been reopened.
+func (s *Controller) reopenStorage(ctx context.Context, current *models.TenantStorageConfig, cfg *models.TenantStorageConfig) error {
+	return s.swapStorage(cfg.TenantId, func(closed *tenantStorage) (*tenantStorage, error) {
+		// DEVELOPERS NOTE:
+		// The timestamps are saved as plain numbers so data already saved with
+		// one precision would be read back wrong with another. Tenants with data
+		// must be migrated with the `tenant migrate-precision` sub-command.
+		var err error
+		if cfg.TimestampPrecision != current.TimestampPrecision {
+			if _, partitionsCount := tenantStorageStats(cfg.TenantId); partitionsCount > 0 {
+				err = status.Errorf(codes.FailedPrecondition, "Timestamp precision cannot be changed once data has been stored, please ask the administrator to migrate your data")
+			}
+		}
+		if err == nil {
+			if saveErr := s.storageConfigRepo.InsertOrUpdateByTenantId(ctx, cfg); saveErr != nil {
+				err = status.Errorf(codes.Internal, saveErr.Error())
+			}
+		}
+
+		// Fallback to the settings we had if the new ones were not saved.
+		open := cfg
+		if err != nil {
+			open = current
+		}
+		storage, openErr := newTenantStorage(tenantDataPath(cfg.TenantId), open)
+		if openErr != nil {
+			return nil, status.Errorf(codes.Internal, openErr.Error())
+		}
+		return storage, err
+	})
+}
+
+// MigrateTenantPrecision converts the time-series data of the tenant into the
+// timestamp precision and saves the precision as the tenant's setting. The
+// data is copied into a new directory and the old directory is kept as a
+// backup, the path of which is returned along with the number of data points
+// copied.
+//
+// DEVELOPERS NOTE:
+// The tenant's storage must not be open while migrating, therefore this must
+// be run with the server stopped (see the `tenant migrate-precision`
+// sub-command).
+func (s *Controller) MigrateTenantPrecision(ctx context.Context, tenantId uint64, precision string) (int, string, error) {
+	doesExist, err := s.tenantRepo.CheckIfExistsById(ctx, tenantId)
+	if err != nil {
+		return 0, "", err
+	}
+	if !doesExist {
+		return 0, "", status.Errorf(codes.NotFound, "Tenant #%v does not exist", tenantId)
+	}
+
+	current, err := s.getStorageConfig(ctx, tenantId)
+	if err != nil {
+		return 0, "", err
+	}
+	if current.TimestampPrecision == precision {
+		return 0, "", status.Errorf(codes.FailedPrecondition, "Tenant #%v already uses the %q timestamp precision", tenantId, precision)
+	}
+	cfg := *current
+	cfg.TimestampPrecision = precision
+	cfg.ModifiedTime = time.Now()
+	if err := validateStorageConfig(&cfg); err != nil {
+		return 0, "", err
+	}
+
+	dataPath := tenantDataPath(tenantId)
+	newPath := dataPath + ".migrating"
+	backupPath := fmt.Sprintf("%v.bak-%v", dataPath, time.Now().Unix())
+
+	// Start from scratch if a previous migration did not finish.
+	if err := os.RemoveAll(newPath); err != nil {
+		return 0, "", err
+	}
+	count, err := tsdb.MigratePrecision(dataPath, newPath, current, &cfg)
+	if err != nil {
+		os.RemoveAll(newPath)
+		return count, "", err
+	}
+
+	// Swap the directories and only then save the precision so the setting
+	// always matches the data in the tenant's directory.
+	if err := os.Rename(dataPath, backupPath); err != nil {
+		os.RemoveAll(newPath)
+		return count, "", err
+	}
+	if err := os.Rename(newPath, dataPath); err != nil {
+		os.Rename(backupPath, dataPath)
+		return count, "", err
+	}
+	if err := s.storageConfigRepo.InsertOrUpdateByTenantId(ctx, &cfg); err != nil {
+		os.Rename(dataPath, newPath)
+		os.Rename(backupPath, dataPath)
+		return count, "", err
+	}
+
+	log.Printf("Migrated %v data points of tenant #%v from %q to %q timestamp precision\n", count, tenantId, current.TimestampPrecision, precision)
+	return count, backupPath, nil
+}
+
+// validateStorageConfig function returns an error if the storage settings
+// cannot be used.
+func validateStorageConfig(cfg *models.TenantStorageConfig) error {
+	if !utils.Contains(models.TimestampPrecisions, cfg.TimestampPrecision) {
+		return status.Errorf(codes.InvalidArgument, "Timestamp precision %q is not supported", cfg.TimestampPrecision)
+	}
+	if cfg.PartitionDuration < minPartitionDuration {
+		return status.Errorf(codes.InvalidArgument, "Partition duration must be at least %v", minPartitionDuration)
+	}
+	if cfg.WriteTimeout < minWriteTimeout {
+		return status.Errorf(codes.InvalidArgument, "Write timeout must be at least %v", minWriteTimeout)
+	}
+	if cfg.Retention < cfg.PartitionDuration {
+		return status.Errorf(codes.InvalidArgument, "Retention must be at least the partition duration")
+	}
+	return nil
+}
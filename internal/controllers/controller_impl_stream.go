@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// The number of data points sent in every message unless the client asks for
+// another size. The maximum keeps every message well below the 4 MB limit of
+// gRPC.
+const (
+	defaultChunkSize = 1000
+	maxChunkSize     = 10000
+)
+
+// StreamTimeSeriesData sends the data points matching the filter in chunks so
+// ranges of any length can be read. Every chunk comes with a cursor; sending
+// the cursor of the last chunk received continues an interrupted stream right
+// after that chunk.
+func (s *Controller) StreamTimeSeriesData(in *pb.StreamTimeSeriesDataReq, stream pb.Mothership_StreamTimeSeriesDataServer) error {
+	ctx := stream.Context()
+
+	// Get our authenticated user (which the stream interceptor saved).
+	user := ctx.Value("user").(*models.User)
+
+	// Lookup the dedicated time-series storage instance for our particular tenant.
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return err
+	}
+
+	if in.Filter == nil {
+		return status.Errorf(codes.InvalidArgument, "Filter is required")
+	}
+	if len(in.Filter.Matchers) > 0 {
+		return status.Errorf(codes.InvalidArgument, "Matchers are only supported by SelectSeries")
+	}
+	start, end, err := toStorageRange(in.Filter, storage.precision())
+	if err != nil {
+		return err
+	}
+	downsample, err := s.newDownsampler(ctx, user.TenantId, in.Filter, storage.precision())
+	if err != nil {
+		return err
+	}
+
+	chunkSize := int(in.ChunkSize)
+	if chunkSize == 0 {
+		chunkSize = defaultChunkSize
+	}
+	if chunkSize < 0 || chunkSize > maxChunkSize {
+		return status.Errorf(codes.InvalidArgument, "Chunk size must be between 1 and %v", maxChunkSize)
+	}
+
+	c := &streamCursor{}
+	if in.Cursor != "" {
+		if c, err = decodeStreamCursor(in.Cursor); err != nil {
+			return status.Errorf(codes.InvalidArgument, "Cursor is malformed")
+		}
+	}
+
+	// Start counting from the cursor so the cursor of the first chunk also
+	// counts the data points sent before the stream was interrupted.
+	sender := &chunkSender{stream: stream, precision: storage.precision(), size: chunkSize, cursor: c, last: *c}
+	labels := toLabels(in.Filter.Labels)
+
+	// DEVELOPERS NOTE:
+	// Downsampled data points are few enough to compute at once, while raw
+	// data points are read one partition duration at a time so we never keep
+	// the whole range in memory.
+	if downsample != nil {
+		points, err := s.selectPoints(user.TenantId, in.Filter.Metric, labels, start, end)
+		if err != nil {
+			return err
+		}
+		if points, err = downsample(points); err != nil {
+			return status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if err := sender.Add(points); err != nil {
+			return err
+		}
+		return sender.Flush()
+	}
+
+	if c.Timestamp > start {
+		start = c.Timestamp
+	}
+	window := uint64(tsdb.ToStorageDuration(storage.config.PartitionDuration, storage.precision()))
+	for from := start; from < end; {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		// Lookup the storage again as it may have been reopened meanwhile.
+		storage, err := s.getStorage(user.TenantId)
+		if err != nil {
+			return err
+		}
+
+		// Skip the windows before the first and after the last data point of
+		// the series, so a range like "since the epoch" does not select
+		// every partition duration of it.
+		e := storage.index.Get(in.Filter.Metric, labels)
+		if e == nil || e.MaxTimestamp < from {
+			break
+		}
+		if e.MinTimestamp > from {
+			if from = e.MinTimestamp; from >= end {
+				break
+			}
+		}
+
+		// The difference of the timestamps may not fit in an `int64`.
+		to := end
+		if uint64(end-from) > window {
+			to = from + int64(window)
+		}
+
+		points, err := s.selectPoints(user.TenantId, in.Filter.Metric, labels, from, to)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(points, func(i, j int) bool {
+			return points[i].Timestamp < points[j].Timestamp
+		})
+		if err := sender.Add(points); err != nil {
+			return err
+		}
+		from = to
+	}
+	return sender.Flush()
+}
+
+// streamCursor is where a stream left off: the timestamp of the last data
+// point sent and how many data points with that timestamp were sent.
+type streamCursor struct {
+	Timestamp int64
+	Count     int64
+}
+
+func (c *streamCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.Timestamp, c.Count)))
+}
+
+func decodeStreamCursor(value string) (*streamCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	c := &streamCursor{}
+	if _, err := fmt.Sscanf(string(b), "%d:%d", &c.Timestamp, &c.Count); err != nil {
+		return nil, err
+	}
+	if c.Count < 0 {
+		return nil, errors.New("count cannot be negative")
+	}
+	return c, nil
+}
+
+// chunkSender collects the data points, in order, into chunks and sends every
+// full chunk. Data points up to the cursor were already sent and are skipped.
+// The `last` cursor points to the last data point added.
+type chunkSender struct {
+	stream    pb.Mothership_StreamTimeSeriesDataServer
+	precision string
+	size      int
+	cursor    *streamCursor
+	skipped   int64
+	chunk     []*pb.DataPointRes
+	last      streamCursor
+}
+
+func (cs *chunkSender) Add(points []*tstorage.DataPoint) error {
+	for _, point := range points {
+		if point.Timestamp < cs.cursor.Timestamp {
+			continue
+		}
+		if point.Timestamp == cs.cursor.Timestamp && cs.skipped < cs.cursor.Count {
+			cs.skipped++
+			continue
+		}
+
+		if point.Timestamp == cs.last.Timestamp {
+			cs.last.Count++
+		} else {
+			cs.last = streamCursor{Timestamp: point.Timestamp, Count: 1}
+		}
+
+		cs.chunk = append(cs.chunk, serializers.ToDataPointRes(point, cs.precision))
+		if len(cs.chunk) >= cs.size {
+			if err := cs.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Flush sends the data points which were not sent yet.
+func (cs *chunkSender) Flush() error {
+	if len(cs.chunk) == 0 {
+		return nil
+	}
+	err := cs.stream.Send(&pb.DataPointsChunkRes{DataPoints: cs.chunk, Cursor: cs.last.encode()})
+	cs.chunk = nil
+	return err
+}
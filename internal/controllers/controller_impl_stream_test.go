@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func TestStreamCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *streamCursor
+		wantErr bool
+	}{
+		{name: "encoded", value: (&streamCursor{Timestamp: 1600000000, Count: 3}).encode(), want: &streamCursor{Timestamp: 1600000000, Count: 3}},
+		{name: "negative timestamp", value: (&streamCursor{Timestamp: -5, Count: 1}).encode(), want: &streamCursor{Timestamp: -5, Count: 1}},
+		{name: "empty", value: "", wantErr: true},
+		{name: "not base64", value: "!!!", wantErr: true},
+		{name: "padded base64", value: base64.URLEncoding.EncodeToString([]byte("1:10")), wantErr: true},
+		{name: "missing count", value: base64.RawURLEncoding.EncodeToString([]byte("1600000000")), wantErr: true},
+		{name: "not numbers", value: base64.RawURLEncoding.EncodeToString([]byte("a:b")), wantErr: true},
+		{name: "negative count", value: base64.RawURLEncoding.EncodeToString([]byte("1600000000:-1")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeStreamCursor(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("decodeStreamCursor(%q) = %+v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeStreamCursor(%q) failed: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeStreamCursor(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingStream keeps the chunks sent on the stream.
+type recordingStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*pb.DataPointsChunkRes
+}
+
+func (s *recordingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recordingStream) Send(res *pb.DataPointsChunkRes) error {
+	s.chunks = append(s.chunks, res)
+	return nil
+}
+
+// Utility function which sends the data points, in batches, through a chunk
+// sender starting at the cursor and returns the values and cursor of every
+// chunk sent.
+func sendChunks(t *testing.T, cursor *streamCursor, size int, batches ...[]*tstorage.DataPoint) ([][]float64, []*streamCursor) {
+	stream := &recordingStream{}
+	sender := &chunkSender{stream: stream, precision: models.SecondsPrecision, size: size, cursor: cursor, last: *cursor}
+	for _, points := range batches {
+		if err := sender.Add(points); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := sender.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	chunks := [][]float64{}
+	cursors := []*streamCursor{}
+	for _, chunk := range stream.chunks {
+		values := []float64{}
+		for _, p := range chunk.DataPoints {
+			values = append(values, p.Value)
+		}
+		chunks = append(chunks, values)
+
+		c, err := decodeStreamCursor(chunk.Cursor)
+		if err != nil {
+			t.Fatalf("decodeStreamCursor(%q) failed: %v", chunk.Cursor, err)
+		}
+		cursors = append(cursors, c)
+	}
+	return chunks, cursors
+}
+
+func TestChunkSender(t *testing.T) {
+	// Three data points share the timestamp 20.
+	points := []*tstorage.DataPoint{
+		{Timestamp: 10, Value: 1},
+		{Timestamp: 20, Value: 2},
+		{Timestamp: 20, Value: 3},
+		{Timestamp: 20, Value: 4},
+		{Timestamp: 30, Value: 5},
+	}
+
+	tests := []struct {
+		name       string
+		cursor     *streamCursor
+		size       int
+		batches    [][]*tstorage.DataPoint
+		want       [][]float64
+		wantCursor *streamCursor
+	}{
+		{
+			name:       "chunks",
+			cursor:     &streamCursor{},
+			size:       2,
+			batches:    [][]*tstorage.DataPoint{points},
+			want:       [][]float64{{1, 2}, {3, 4}, {5}},
+			wantCursor: &streamCursor{Timestamp: 30, Count: 1},
+		},
+		{
+			name:       "batches",
+			cursor:     &streamCursor{},
+			size:       3,
+			batches:    [][]*tstorage.DataPoint{points[:2], points[2:]},
+			want:       [][]float64{{1, 2, 3}, {4, 5}},
+			wantCursor: &streamCursor{Timestamp: 30, Count: 1},
+		},
+		{
+			name:       "resume within a timestamp",
+			cursor:     &streamCursor{Timestamp: 20, Count: 2},
+			size:       2,
+			batches:    [][]*tstorage.DataPoint{points},
+			want:       [][]float64{{4, 5}},
+			wantCursor: &streamCursor{Timestamp: 30, Count: 1},
+		},
+		{
+			name:       "resume after a timestamp",
+			cursor:     &streamCursor{Timestamp: 10, Count: 1},
+			size:       10,
+			batches:    [][]*tstorage.DataPoint{points},
+			want:       [][]float64{{2, 3, 4, 5}},
+			wantCursor: &streamCursor{Timestamp: 30, Count: 1},
+		},
+		{
+			// The cursor of a chunk ending within a timestamp counts the
+			// data points sent before the stream was resumed.
+			name:       "cursor counts from the resumed cursor",
+			cursor:     &streamCursor{Timestamp: 20, Count: 1},
+			size:       1,
+			batches:    [][]*tstorage.DataPoint{points[:3]},
+			want:       [][]float64{{3}},
+			wantCursor: &streamCursor{Timestamp: 20, Count: 2},
+		},
+		{
+			name:       "nothing left",
+			cursor:     &streamCursor{Timestamp: 30, Count: 1},
+			size:       2,
+			batches:    [][]*tstorage.DataPoint{points},
+			want:       [][]float64{},
+			wantCursor: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cursors := sendChunks(t, tt.cursor, tt.size, tt.batches...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunks = %v, want %v", got, tt.want)
+			}
+			var cursor *streamCursor
+			if len(cursors) > 0 {
+				cursor = cursors[len(cursors)-1]
+			}
+			if !reflect.DeepEqual(cursor, tt.wantCursor) {
+				t.Errorf("cursor of the last chunk = %+v, want %+v", cursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+// Resuming from the cursor of any chunk sends the data points after that
+// chunk exactly once.
+func TestChunkSenderResume(t *testing.T) {
+	points := []*tstorage.DataPoint{
+		{Timestamp: 10, Value: 1},
+		{Timestamp: 20, Value: 2},
+		{Timestamp: 20, Value: 3},
+		{Timestamp: 20, Value: 4},
+		{Timestamp: 30, Value: 5},
+	}
+	all, cursors := sendChunks(t, &streamCursor{}, 1, points)
+	for i, cursor := range cursors {
+		got, _ := sendChunks(t, cursor, 1, points)
+		if want := all[i+1:]; !reflect.DeepEqual(got, want) {
+			t.Errorf("resumed after chunk #%v = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestStreamTimeSeriesData(t *testing.T) {
+	s := newTestController(t)
+	dataPath, cfg := t.TempDir(), models.DefaultTenantStorageConfig(testTenantId)
+	storage, err := newTenantStorage(dataPath, cfg)
+	if err != nil {
+		t.Fatalf("newTenantStorage failed: %v", err)
+	}
+	s.setStorage(testTenantId, storage)
+
+	labels := []tstorage.Label{{Name: "room", Value: "kitchen"}}
+	at := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	_, err = s.insertRows(testTenantId, func(precision string) []tstorage.Row {
+		rows := []tstorage.Row{}
+		for i := 0; i < 5; i++ {
+			// One data point every partition duration, but the third.
+			if i != 2 {
+				rows = append(rows, tsdb.NewRow("temperature", labels, at.Add(time.Duration(i)*24*time.Hour), float64(i), precision))
+			}
+		}
+		return rows
+	})
+	if err != nil {
+		t.Fatalf("insertRows failed: %v", err)
+	}
+
+	// Reopen the storage so the data points are read from disk, as `tstorage`
+	// cannot select the data points kept in memory by a range ending before
+	// the last of them.
+	err = s.swapStorage(testTenantId, func(closed *tenantStorage) (*tenantStorage, error) {
+		return newTenantStorage(dataPath, cfg)
+	})
+	if err != nil {
+		t.Fatalf("swapStorage failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if storage, err := s.getStorage(testTenantId); err == nil {
+			storage.Close()
+		}
+	})
+
+	user := &models.User{Id: 1, TenantId: testTenantId}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	// The ranges are far longer than the series so the stream only finishes
+	// quickly if it skips the partition durations without data points.
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  []float64
+	}{
+		{name: "every year", start: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), end: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: []float64{0, 1, 3, 4}},
+		{name: "since the series", start: at.Add(24 * time.Hour), end: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: []float64{1, 3, 4}},
+		{name: "until the series", start: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), end: at.Add(72 * time.Hour), want: []float64{0, 1}},
+		{name: "before the series", start: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), end: at, want: []float64{}},
+		{name: "after the series", start: at.Add(5 * 24 * time.Hour), end: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &recordingStream{ctx: ctx}
+			in := &pb.StreamTimeSeriesDataReq{
+				Filter: &pb.FilterReq{
+					Metric: "temperature",
+					Labels: []*pb.LabelReq{{Name: "room", Value: "kitchen"}},
+					Start:  serializers.ToTimestamp(tt.start),
+					End:    serializers.ToTimestamp(tt.end),
+				},
+			}
+			if err := s.StreamTimeSeriesData(in, stream); err != nil {
+				t.Fatalf("StreamTimeSeriesData failed: %v", err)
+			}
+			got := []float64{}
+			for _, chunk := range stream.chunks {
+				for _, p := range chunk.DataPoints {
+					got = append(got, p.Value)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StreamTimeSeriesData sent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
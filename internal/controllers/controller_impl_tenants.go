@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// How long we remember the state of a tenant before looking it up again. A
+// suspended tenant's sessions are revoked straight away but any API keys keep
+// working on other servers for up to this long.
+const tenantStateCacheTTL = time.Second * 30
+
+// How long the tenant admin has to confirm they want to delete their tenant.
+const tenantDeletionConfirmationExpiryTime = time.Minute * 10
+
+func (s *Controller) GetTenant(ctx context.Context, in *empty.Empty) (*pb.TenantRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	tenant, err := s.tenantRepo.GetById(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if tenant == nil {
+		return nil, status.Errorf(codes.NotFound, "Tenant does not exist")
+	}
+
+	return serializers.ToTenantRes(tenant), nil
+}
+
+func (s *Controller) UpdateTenant(ctx context.Context, in *pb.UpdateTenantReq) (*pb.TenantRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	tenant, err := s.tenantRepo.GetById(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if tenant == nil {
+		return nil, status.Errorf(codes.NotFound, "Tenant does not exist")
+	}
+
+	name := strings.TrimSpace(in.Name)
+	if name != "" && name != tenant.Name {
+		if isReservedTenantName(name) {
+			return nil, status.Errorf(codes.InvalidArgument, "Company name is reserved")
+		}
+		doesExist, err := s.tenantRepo.CheckIfExistsByName(ctx, name)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if doesExist {
+			return nil, status.Errorf(codes.AlreadyExists, "Company name is not unique")
+		}
+		tenant.Name = name
+	}
+	if timezone := strings.TrimSpace(in.Timezone); timezone != "" {
+		tenant.Timezone = timezone
+	}
+
+	tenant.ModifiedTime = time.Now()
+	err = s.tenantRepo.UpdateById(ctx, tenant)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return serializers.ToTenantRes(tenant), nil
+}
+
+// DeleteTenant deletes the tenant of the authenticated user in two steps: the
+// first call returns a confirmation token and only calling it again with the
+// token actually deletes the tenant.
+func (s *Controller) DeleteTenant(ctx context.Context, in *pb.DeleteTenantReq) (*pb.DeleteTenantRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	// Root users must not delete the tenant they belong to or nobody would
+	// be left to manage the installation.
+	actualUser, err := s.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if actualUser.RoleId == models.UserRootRoleId && actualUser.TenantId == user.TenantId {
+		return nil, status.Errorf(codes.FailedPrecondition, "You cannot delete the tenant of a root user")
+	}
+
+	b := []byte(s.hmacSecret)
+	confirmationToken := strings.TrimSpace(in.ConfirmationToken)
+	if confirmationToken == "" {
+		token, err := utils.GenerateTenantDeletionToken(b, user.TenantId, tenantDeletionConfirmationExpiryTime)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		return &pb.DeleteTenantRes{
+			Deleted:                false,
+			ConfirmationToken:      token,
+			ConfirmationExpiryTime: serializers.ToTimestamp(time.Now().Add(tenantDeletionConfirmationExpiryTime)),
+			Message:                "Deleting the tenant removes all of its users, API keys and time-series data. Call again with the confirmation token to continue.",
+		}, nil
+	}
+
+	tenantId, err := utils.ProcessTenantDeletionToken(b, confirmationToken)
+	if err != nil || tenantId != user.TenantId {
+		return nil, status.Errorf(codes.InvalidArgument, "Confirmation token is invalid")
+	}
+
+	if err := s.DeleteTenantById(ctx, tenantId, actualUser.Email); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteTenantRes{Deleted: true, Message: "The tenant has been deleted."}, nil
+}
+
+func (s *Controller) SuspendTenant(ctx context.Context, in *pb.SuspendTenantReq) (*empty.Empty, error) {
+	// Get our authenticated user. The tenant in the context is the one the
+	// root user acts as, so look up the tenant they actually belong to.
+	user, err := s.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.TenantId == user.TenantId {
+		return nil, status.Errorf(codes.FailedPrecondition, "You cannot suspend your own tenant")
+	}
+	if err := s.setTenantState(ctx, in.TenantId, models.TenantInactiveState); err != nil {
+		return nil, err
+	}
+
+	// Log everybody out so the suspension takes effect right away.
+	users, err := s.userRepo.ListByTenantId(ctx, in.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	for _, u := range users {
+		if err := s.revokeAllSessions(ctx, u.Id, ""); err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *Controller) ReactivateTenant(ctx context.Context, in *pb.ReactivateTenantReq) (*empty.Empty, error) {
+	if err := s.setTenantState(ctx, in.TenantId, models.TenantActiveState); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// Utility function which changes the state of the tenant and forgets the
+// state we cached.
+func (s *Controller) setTenantState(ctx context.Context, tenantId uint64, state int8) error {
+	doesExist, err := s.tenantRepo.CheckIfExistsById(ctx, tenantId)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if !doesExist {
+		return status.Errorf(codes.NotFound, "Tenant #%v does not exist", tenantId)
+	}
+
+	err = s.tenantRepo.UpdateStateById(ctx, tenantId, state)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	s.tenantStates.Delete(tenantId)
+	return nil
+}
+
+// Utility function which returns the state of the tenant, looking it up in
+// the database if we have not cached it.
+func (s *Controller) getTenantState(ctx context.Context, tenantId uint64) (int8, error) {
+	if state, ok := s.tenantStates.Get(tenantId); ok {
+		return state, nil
+	}
+
+	tenant, err := s.tenantRepo.GetById(ctx, tenantId)
+	if err != nil {
+		return 0, err
+	}
+
+	// A tenant which was deleted is treated as suspended.
+	state := models.TenantInactiveState
+	if tenant != nil {
+		state = tenant.State
+	}
+	s.tenantStates.Set(tenantId, state)
+	return state, nil
+}
+
+// Utility function which returns an error if the user was deactivated or the
+// tenant they belong to was suspended.
+func (s *Controller) checkIsActive(ctx context.Context, user *models.User) error {
+	if user.State != models.UserActiveState {
+		return status.Errorf(codes.PermissionDenied, "Your account has been deactivated")
+	}
+
+	state, err := s.getTenantState(ctx, user.TenantId)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if state != models.TenantActiveState {
+		return status.Errorf(codes.PermissionDenied, "Your tenant has been suspended")
+	}
+	return nil
+}
+
+// DeleteTenantById permanently deletes the tenant: its users, invitations and
+// API keys are deleted, everybody is logged out, the time-series storage is
+// closed and removed from disk and an audit record of the deletion is saved.
+// The `requestedBy` is saved in the audit record to tell who deleted it.
+func (s *Controller) DeleteTenantById(ctx context.Context, tenantId uint64, requestedBy string) error {
+	tenant, err := s.tenantRepo.GetById(ctx, tenantId)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if tenant == nil {
+		return status.Errorf(codes.NotFound, "Tenant #%v does not exist", tenantId)
+	}
+	if tenant.IsRoot {
+		return status.Errorf(codes.FailedPrecondition, "The root tenant cannot be deleted")
+	}
+
+	// Lookup what belongs to the tenant before the rows are gone so we can
+	// find the sessions and keep count for the audit record.
+	users, err := s.userRepo.ListByTenantId(ctx, tenantId)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	apiKeys, err := s.apiKeyRepo.ListByTenantId(ctx, tenantId)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+
+	err = s.tenantRepo.DeleteById(ctx, tenantId)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	s.tenantStates.Delete(tenantId)
+	s.ingestion.Delete(tenantId)
+	s.retention.Delete(tenantId)
+	log.Printf("Deleted tenant #%v requested by %v\n", tenantId, requestedBy)
+
+	for _, u := range users {
+		if err := s.revokeAllSessions(ctx, u.Id, ""); err != nil {
+			log.Println("DeleteTenantById | revokeAllSessions | err", err)
+		}
+	}
+
+	// DEVELOPERS NOTE:
+	// The storage must be closed before the directory is removed, otherwise
+	// it would flush its in-memory partitions back to disk.
+	err = s.swapStorage(tenantId, func(closed *tenantStorage) (*tenantStorage, error) {
+		return nil, nil
+	})
+	if err != nil {
+		log.Println("DeleteTenantById | swapStorage | err", err)
+	}
+	dataPath := tenantDataPath(tenantId)
+	dataSize, err := utils.DirSize(dataPath)
+	if err != nil {
+		log.Println("DeleteTenantById | DirSize | err", err)
+	}
+	removeErr := os.RemoveAll(dataPath)
+
+	m := &models.TenantDeletion{
+		TenantId:     tenant.Id,
+		TenantUuid:   tenant.Uuid,
+		TenantName:   tenant.Name,
+		RequestedBy:  requestedBy,
+		UsersCount:   len(users),
+		APIKeysCount: len(apiKeys),
+		DataSize:     dataSize,
+		CreatedTime:  time.Now(),
+	}
+	if err := s.deletionRepo.Insert(ctx, m); err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+
+	if removeErr != nil {
+		return status.Errorf(codes.Internal, "Tenant was deleted but its data in %v could not be removed: %v", dataPath, removeErr)
+	}
+	return nil
+}
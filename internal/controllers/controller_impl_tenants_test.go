@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func TestSuspendOwnTenant(t *testing.T) {
+	root := &models.User{Id: 1, TenantId: testTenantId, RoleId: models.UserRootRoleId}
+	s := &Controller{userRepo: &testUserRepo{users: []*models.User{root}}}
+
+	// The root user acts as another tenant, which changes the tenant of the
+	// user in the context but not the tenant they belong to.
+	actingAs := *root
+	actingAs.TenantId = testTenantId + 1
+	ctx := context.WithValue(context.Background(), "user", &actingAs)
+
+	_, err := s.SuspendTenant(ctx, &pb.SuspendTenantReq{TenantId: testTenantId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("SuspendTenant of their own tenant = %v, want %v", err, codes.FailedPrecondition)
+	}
+}
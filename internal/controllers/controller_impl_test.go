@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// testUserRepo keeps the users in memory. Calling a function it does not
+// implement panics.
+type testUserRepo struct {
+	models.UserRepository
+	users []*models.User
+}
+
+func (r *testUserRepo) GetById(ctx context.Context, id uint64) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Id == id {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testUserRepo) GetByUuid(ctx context.Context, uuid string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Uuid == uuid {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testUserRepo) CheckIfExistsByEmail(ctx context.Context, email string) (bool, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *testUserRepo) UpdateById(ctx context.Context, m *models.User) error {
+	for i, u := range r.users {
+		if u.Id == m.Id {
+			c := *m
+			r.users[i] = &c
+		}
+	}
+	return nil
+}
+
+// testTenantRepo keeps the tenants in memory, along with the users inserted
+// with them. Calling a function it does not implement panics.
+type testTenantRepo struct {
+	models.TenantRepository
+	tenants []*models.Tenant
+	users   *testUserRepo
+}
+
+func (r *testTenantRepo) CheckIfExistsByName(ctx context.Context, name string) (bool, error) {
+	for _, m := range r.tenants {
+		if m.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *testTenantRepo) InsertWithUser(ctx context.Context, m *models.Tenant, u *models.User) error {
+	m.Id = uint64(len(r.tenants) + 1)
+	u.Id = uint64(len(r.users.users) + 1)
+	u.TenantId = m.Id
+	r.tenants = append(r.tenants, m)
+	r.users.users = append(r.users.users, u)
+	return nil
+}
+
+func (r *testTenantRepo) DeleteById(ctx context.Context, id uint64) error {
+	tenants := []*models.Tenant{}
+	for _, m := range r.tenants {
+		if m.Id != id {
+			tenants = append(tenants, m)
+		}
+	}
+	users := []*models.User{}
+	for _, u := range r.users.users {
+		if u.TenantId != id {
+			users = append(users, u)
+		}
+	}
+	r.tenants, r.users.users = tenants, users
+	return nil
+}
+
+// failingStorageConfigRepo fails to look up any storage settings.
+type failingStorageConfigRepo struct {
+	models.TenantStorageConfigRepository
+}
+
+func (r *failingStorageConfigRepo) GetByTenantId(ctx context.Context, tenantId uint64) (*models.TenantStorageConfig, error) {
+	return nil, errors.New("database is down")
+}
+
+func TestRegisterStorageFails(t *testing.T) {
+	users := &testUserRepo{}
+	tenants := &testTenantRepo{users: users}
+	s := &Controller{userRepo: users, tenantRepo: tenants, storageConfigRepo: &failingStorageConfigRepo{}}
+
+	in := &pb.RegistrationReq{Company: "Arrakis", Email: "frank@example.com", Password: "spice", Timezone: "UTC"}
+	if _, err := s.Register(context.Background(), in); err == nil {
+		t.Fatalf("Register did not fail without the storage")
+	}
+
+	// Neither the company name nor the email stays taken.
+	if len(tenants.tenants) != 0 || len(users.users) != 0 {
+		t.Errorf("Register left %v tenants and %v users behind, want none", len(tenants.tenants), len(users.users))
+	}
+}
+
+func TestToStorageRange(t *testing.T) {
+	year1 := serializers.ToTimestamp(time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC))
+	year9999 := serializers.ToTimestamp(time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	start := serializers.ToTimestamp(time.Unix(1600000000, 0))
+	end := serializers.ToTimestamp(time.Unix(1600003600, 0))
+
+	tests := []struct {
+		name      string
+		in        *pb.FilterReq
+		precision string
+		wantStart int64
+		wantEnd   int64
+		wantCode  codes.Code
+	}{
+		{name: "seconds", in: &pb.FilterReq{Start: start, End: end}, precision: models.SecondsPrecision, wantStart: 1600000000, wantEnd: 1600003600},
+		{name: "nanoseconds", in: &pb.FilterReq{Start: start, End: end}, precision: models.NanosecondsPrecision, wantStart: 1600000000e9, wantEnd: 1600003600e9},
+		{name: "milliseconds far apart", in: &pb.FilterReq{Start: year1, End: year9999}, precision: models.MillisecondsPrecision, wantStart: -62135596800000, wantEnd: 253402214400000},
+		{name: "missing start", in: &pb.FilterReq{End: end}, precision: models.SecondsPrecision, wantCode: codes.InvalidArgument},
+		{name: "missing end", in: &pb.FilterReq{Start: start}, precision: models.SecondsPrecision, wantCode: codes.InvalidArgument},
+		{name: "start before nanoseconds fit", in: &pb.FilterReq{Start: year1, End: end}, precision: models.NanosecondsPrecision, wantCode: codes.InvalidArgument},
+		{name: "end after nanoseconds fit", in: &pb.FilterReq{Start: start, End: year9999}, precision: models.NanosecondsPrecision, wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd, err := toStorageRange(tt.in, tt.precision)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("toStorageRange failed with %v, want %v", err, tt.wantCode)
+			}
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("toStorageRange = %v, %v, want %v, %v", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestToIndexRange(t *testing.T) {
+	year1 := serializers.ToTimestamp(time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC))
+	year9999 := serializers.ToTimestamp(time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC))
+	start := serializers.ToTimestamp(time.Unix(1600000000, 0))
+
+	tests := []struct {
+		name       string
+		start, end *tspb.Timestamp
+		wantStart  int64
+		wantEnd    int64
+	}{
+		{name: "open", wantStart: math.MinInt64, wantEnd: math.MaxInt64},
+		{name: "start", start: start, wantStart: 1600000000e9, wantEnd: math.MaxInt64},
+		{name: "too early and too late", start: year1, end: year9999, wantStart: math.MinInt64, wantEnd: math.MaxInt64},
+		// A range starting after every time which fits selects nothing.
+		{name: "start too late", start: year9999, wantStart: math.MaxInt64, wantEnd: math.MaxInt64},
+		{name: "end too early", end: year1, wantStart: math.MinInt64, wantEnd: math.MinInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStart, gotEnd := toIndexRange(tt.start, tt.end, models.NanosecondsPrecision)
+			if gotStart != tt.wantStart || gotEnd != tt.wantEnd {
+				t.Errorf("toIndexRange = %v, %v, want %v, %v", gotStart, gotEnd, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
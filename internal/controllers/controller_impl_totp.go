@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+const (
+	// The name shown in the user's authenticator app.
+	totpIssuer = "Mothership"
+
+	// How long the user has to enter their one-time password after they
+	// entered their email and password.
+	challengeTokenExpiryTime = time.Minute * 5
+
+	recoveryCodesCount = 10
+)
+
+func (s *Controller) CompleteLogin(ctx context.Context, in *pb.CompleteLoginReq) (*pb.LoginRes, error) {
+	b := []byte(s.hmacSecret)
+	userId, err := utils.ProcessChallengeToken(b, strings.TrimSpace(in.ChallengeToken))
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	user, err := s.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil || !user.TotpEnabled {
+		return nil, status.Errorf(codes.Unauthenticated, "Login challenge is invalid - please log in again")
+	}
+
+	if err := s.checkIsActive(ctx, user); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.verifySecondFactor(ctx, user, in.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Code is incorrect")
+	}
+
+	accessToken, refreshToken, err := s.createSession(ctx, user, newFamilyUuid())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.LoginRes{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (s *Controller) EnrollTOTP(ctx context.Context, in *empty.Empty) (*pb.EnrollTOTPRes, error) {
+	user, err := s.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "Two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// The secret is saved but not enabled until the user proves they added it
+	// to their authenticator app with `ConfirmTOTP`.
+	user.TotpSecret = secret
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.EnrollTOTPRes{
+		Secret: secret,
+		Uri:    utils.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+func (s *Controller) ConfirmTOTP(ctx context.Context, in *pb.ConfirmTOTPReq) (*pb.ConfirmTOTPRes, error) {
+	user, err := s.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "Two-factor authentication is already enabled")
+	}
+	if user.TotpSecret == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "Please enroll in two-factor authentication first")
+	}
+	if !s.totpLimiter.Allow(user.Id) {
+		return nil, status.Errorf(codes.ResourceExhausted, "Too many incorrect codes - please try again later")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TotpSecret, in.Code, time.Now(), 0)
+	if !ok {
+		s.totpLimiter.Fail(user.Id)
+		return nil, status.Errorf(codes.InvalidArgument, "Code is incorrect")
+	}
+	s.totpLimiter.Reset(user.Id)
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, utils.HashToken(code))
+	}
+
+	user.TotpEnabled = true
+	user.TotpRecoveryCodes = hashes
+	user.TotpLastUsedStep = step
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// DEVELOPERS NOTE:
+	// This is the only time the recovery codes leave the server, afterwords
+	// we only keep the hashes of them.
+	return &pb.ConfirmTOTPRes{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *Controller) DisableTOTP(ctx context.Context, in *pb.DisableTOTPReq) (*empty.Empty, error) {
+	user, err := s.getAuthenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "Two-factor authentication is not enabled")
+	}
+
+	// Require both factors so a stolen session cannot turn this off.
+	if utils.CheckPasswordHash(strings.TrimSpace(in.Password), user.PasswordHash) == false {
+		return nil, status.Errorf(codes.InvalidArgument, "Password is incorrect")
+	}
+	ok, err := s.verifySecondFactor(ctx, user, in.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Code is incorrect")
+	}
+
+	user.TotpEnabled = false
+	user.TotpSecret = ""
+	user.TotpRecoveryCodes = []string{}
+	user.TotpLastUsedStep = 0
+	user.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Utility function which checks the one-time password or recovery code of the
+// user. A code can only be used once, so the user is updated to remember the
+// code was used.
+func (s *Controller) verifySecondFactor(ctx context.Context, user *models.User, code string) (bool, error) {
+	if !s.totpLimiter.Allow(user.Id) {
+		return false, status.Errorf(codes.ResourceExhausted, "Too many incorrect codes - please try again later")
+	}
+
+	if step, ok := utils.ValidateTOTP(user.TotpSecret, code, time.Now(), user.TotpLastUsedStep); ok {
+		user.TotpLastUsedStep = step
+	} else if i := indexOfRecoveryCode(user, code); i >= 0 {
+		user.TotpRecoveryCodes = append(user.TotpRecoveryCodes[:i], user.TotpRecoveryCodes[i+1:]...)
+	} else {
+		s.totpLimiter.Fail(user.Id)
+		return false, nil
+	}
+	s.totpLimiter.Reset(user.Id)
+
+	user.ModifiedTime = time.Now()
+	if err := s.userRepo.UpdateById(ctx, user); err != nil {
+		return false, status.Errorf(codes.Internal, err.Error())
+	}
+	return true, nil
+}
+
+// Utility function which returns the index of the recovery code in the
+// user's recovery codes or -1 if it does not exist.
+func indexOfRecoveryCode(user *models.User, code string) int {
+	code = utils.NormalizeRecoveryCode(code)
+	if code == "" {
+		return -1
+	}
+	for i, hash := range user.TotpRecoveryCodes {
+		if utils.CheckTokenHash(code, hash) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Utility function which returns the latest copy of the authenticated user
+// from the database. Use this instead of the user cached in the session when
+// the user is going to be updated.
+func (s *Controller) getAuthenticatedUser(ctx context.Context) (*models.User, error) {
+	sessionUser := ctx.Value("user").(*models.User)
+	user, err := s.userRepo.GetById(ctx, sessionUser.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "User does not exist")
+	}
+	return user, nil
+}
@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/notifier"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+const invitationExpiryTime = time.Hour * 24 * 7
+
+func (s *Controller) InviteUser(ctx context.Context, in *pb.InviteUserReq) (*pb.InvitationRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	email := strings.TrimSpace(in.Email)
+	if !strings.Contains(email, "@") {
+		return nil, status.Errorf(codes.InvalidArgument, "Email is invalid")
+	}
+	roleId := int8(in.RoleId)
+	if err := checkAssignableRole(user, roleId); err != nil {
+		return nil, err
+	}
+
+	doesExist, err := s.userRepo.CheckIfExistsByEmail(ctx, email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if doesExist {
+		return nil, status.Errorf(codes.AlreadyExists, "Email is not unique")
+	}
+
+	// Inviting the same email again replaces the previous invitation.
+	err = s.invitationRepo.RevokePendingByTenantIdAndEmail(ctx, user.TenantId, email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	token, err := utils.NewSecureToken(32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	m := &models.Invitation{
+		Uuid:            uuid.NewString(),
+		TenantId:        user.TenantId,
+		InvitedByUserId: user.Id,
+		Email:           email,
+		RoleId:          roleId,
+		TokenHash:       utils.HashToken(token),
+		State:           models.InvitationPendingState,
+		ExpiryTime:      time.Now().Add(invitationExpiryTime),
+		CreatedTime:     time.Now(),
+		ModifiedTime:    time.Now(),
+	}
+	err = s.invitationRepo.Insert(ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	msg := &notifier.Message{
+		To:      email,
+		Subject: "You have been invited to the mothership",
+		Body: fmt.Sprintf("%s %s has invited you to join their team on the mothership.\n\n"+
+			"Your invitation token is: %s\n\n"+
+			"The invitation expires in %v.",
+			user.FirstName, user.LastName, token, invitationExpiryTime),
+	}
+	go func() {
+		if err := s.notifier.Send(context.Background(), msg); err != nil {
+			log.Println("InviteUser | Send | err", err)
+		}
+	}()
+
+	return serializers.ToInvitationRes(m), nil
+}
+
+func (s *Controller) ListInvitations(ctx context.Context, in *empty.Empty) (*pb.ListInvitationsRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	arr, err := s.invitationRepo.ListByTenantId(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.ListInvitationsRes{Invitations: serializers.ToInvitationResList(arr)}, nil
+}
+
+func (s *Controller) RevokeInvitation(ctx context.Context, in *pb.RevokeInvitationReq) (*empty.Empty, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	m, err := s.invitationRepo.GetByUuid(ctx, in.Uuid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if m == nil || m.TenantId != user.TenantId {
+		return nil, status.Errorf(codes.NotFound, "Invitation does not exist")
+	}
+	if m.State != models.InvitationPendingState {
+		return nil, status.Errorf(codes.FailedPrecondition, "Invitation is no longer pending")
+	}
+
+	m.State = models.InvitationRevokedState
+	m.ModifiedTime = time.Now()
+	err = s.invitationRepo.UpdateById(ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *Controller) AcceptInvitation(ctx context.Context, in *pb.AcceptInvitationReq) (*pb.AcceptInvitationRes, error) {
+	token := strings.TrimSpace(in.Token)
+	passwordPlain := strings.TrimSpace(in.Password)
+	if token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Invitation is invalid")
+	}
+	if passwordPlain == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Password is required")
+	}
+
+	invitation, err := s.invitationRepo.GetByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if invitation == nil || invitation.State != models.InvitationPendingState {
+		return nil, status.Errorf(codes.InvalidArgument, "Invitation is invalid")
+	}
+	if invitation.IsExpired() {
+		return nil, status.Errorf(codes.InvalidArgument, "Invitation expired - please ask for a new one")
+	}
+
+	doesExist, err := s.userRepo.CheckIfExistsByEmail(ctx, invitation.Email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if doesExist {
+		return nil, status.Errorf(codes.AlreadyExists, "Email is not unique")
+	}
+
+	// Default to the timezone of the tenant if the user did not pick one.
+	timezone := strings.TrimSpace(in.Timezone)
+	if timezone == "" {
+		tenant, err := s.tenantRepo.GetById(ctx, invitation.TenantId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if tenant == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invitation is invalid")
+		}
+		timezone = tenant.Timezone
+	}
+
+	passwordHash, err := utils.HashPassword(passwordPlain)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// DEVELOPERS NOTE:
+	// The invitation token was sent to the email so having it proves the
+	// user owns the email, therefore we do not need to verify it again.
+	u := &models.User{
+		TenantId:          invitation.TenantId,
+		Uuid:              uuid.NewString(),
+		Email:             invitation.Email,
+		FirstName:         in.FirstName,
+		LastName:          in.LastName,
+		State:             models.UserActiveState,
+		Timezone:          timezone,
+		CreatedTime:       time.Now(),
+		ModifiedTime:      time.Now(),
+		PasswordHash:      passwordHash,
+		PasswordAlgorithm: "bcrypt",
+		RoleId:            invitation.RoleId,
+		WasEmailActivated: true,
+	}
+	err = s.userRepo.Insert(ctx, u)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	invitation.State = models.InvitationAcceptedState
+	invitation.ModifiedTime = time.Now()
+	err = s.invitationRepo.UpdateById(ctx, invitation)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.AcceptInvitationRes{
+		Message: "Your account has been created. Please login to begin using the system.",
+	}, nil
+}
+
+func (s *Controller) ListUsers(ctx context.Context, in *empty.Empty) (*pb.ListUsersRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	arr, err := s.userRepo.ListByTenantId(ctx, user.TenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.ListUsersRes{Users: serializers.ToUserResList(arr)}, nil
+}
+
+func (s *Controller) UpdateUser(ctx context.Context, in *pb.UpdateUserReq) (*pb.UserRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	m, err := s.getTenantUserByUuid(ctx, user, in.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields left empty are not changed.
+	if firstName := strings.TrimSpace(in.FirstName); firstName != "" {
+		m.FirstName = firstName
+	}
+	if lastName := strings.TrimSpace(in.LastName); lastName != "" {
+		m.LastName = lastName
+	}
+	if timezone := strings.TrimSpace(in.Timezone); timezone != "" {
+		m.Timezone = timezone
+	}
+	m.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return serializers.ToUserRes(m), nil
+}
+
+func (s *Controller) ChangeUserRole(ctx context.Context, in *pb.ChangeUserRoleReq) (*pb.UserRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	m, err := s.getTenantUserByUuid(ctx, user, in.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	if m.Id == user.Id {
+		return nil, status.Errorf(codes.FailedPrecondition, "You cannot change your own role")
+	}
+
+	roleId := int8(in.RoleId)
+	if err := checkAssignableRole(user, roleId); err != nil {
+		return nil, err
+	}
+	if err := checkAssignableRole(user, m.RoleId); err != nil {
+		return nil, err
+	}
+
+	m.RoleId = roleId
+	m.ModifiedTime = time.Now()
+	if err := s.updateUserAndRevokeSessions(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return serializers.ToUserRes(m), nil
+}
+
+func (s *Controller) DeactivateUser(ctx context.Context, in *pb.DeactivateUserReq) (*pb.UserRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	m, err := s.getTenantUserByUuid(ctx, user, in.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	if m.Id == user.Id {
+		return nil, status.Errorf(codes.FailedPrecondition, "You cannot deactivate yourself")
+	}
+	if err := checkAssignableRole(user, m.RoleId); err != nil {
+		return nil, err
+	}
+
+	m.State = models.UserInactiveState
+	m.ModifiedTime = time.Now()
+	if err := s.updateUserAndRevokeSessions(ctx, m); err != nil {
+		return nil, err
+	}
+
+	return serializers.ToUserRes(m), nil
+}
+
+func (s *Controller) ActivateUser(ctx context.Context, in *pb.ActivateUserReq) (*pb.UserRes, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	m, err := s.getTenantUserByUuid(ctx, user, in.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAssignableRole(user, m.RoleId); err != nil {
+		return nil, err
+	}
+
+	m.State = models.UserActiveState
+	m.ModifiedTime = time.Now()
+	err = s.userRepo.UpdateById(ctx, m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return serializers.ToUserRes(m), nil
+}
+
+// Utility function which returns the user with the uuid if they belong to the
+// same tenant as the authenticated user.
+func (s *Controller) getTenantUserByUuid(ctx context.Context, user *models.User, uid string) (*models.User, error) {
+	m, err := s.userRepo.GetByUuid(ctx, uid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if m == nil || m.TenantId != user.TenantId {
+		return nil, status.Errorf(codes.NotFound, "User does not exist")
+	}
+	return m, nil
+}
+
+// Utility function which saves the user and logs them out everywhere. The
+// sessions cache the user so this must be used whenever the role or state
+// changes, otherwise the old values stay in effect until the session expires.
+func (s *Controller) updateUserAndRevokeSessions(ctx context.Context, m *models.User) error {
+	err := s.userRepo.UpdateById(ctx, m)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if err := s.revokeAllSessions(ctx, m.Id, ""); err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// checkAssignableRole function returns an error if the role does not exist or
+// would grant more than the user is allowed to grant. Only root users can
+// manage other root users.
+func checkAssignableRole(user *models.User, roleId int8) error {
+	if _, ok := rolePermissions[roleId]; !ok {
+		return status.Errorf(codes.InvalidArgument, "Role #%v does not exist", roleId)
+	}
+	if rolePermissions[roleId] > rolePermissions[user.RoleId] {
+		return status.Errorf(codes.PermissionDenied, "You do not have permission to manage role #%v", roleId)
+	}
+	return nil
+}
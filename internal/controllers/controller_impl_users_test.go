@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func TestUpdateUser(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *pb.UpdateUserReq
+		want models.User
+	}{
+		{name: "nothing", in: &pb.UpdateUserReq{}, want: models.User{FirstName: "Frank", LastName: "Herbert", Timezone: "America/Toronto"}},
+		{name: "blank", in: &pb.UpdateUserReq{FirstName: " ", LastName: " ", Timezone: " "}, want: models.User{FirstName: "Frank", LastName: "Herbert", Timezone: "America/Toronto"}},
+		{name: "first name", in: &pb.UpdateUserReq{FirstName: " Brian "}, want: models.User{FirstName: "Brian", LastName: "Herbert", Timezone: "America/Toronto"}},
+		{name: "last name", in: &pb.UpdateUserReq{LastName: "Anderson"}, want: models.User{FirstName: "Frank", LastName: "Anderson", Timezone: "America/Toronto"}},
+		{name: "timezone", in: &pb.UpdateUserReq{Timezone: "Asia/Tokyo"}, want: models.User{FirstName: "Frank", LastName: "Herbert", Timezone: "Asia/Tokyo"}},
+		{name: "everything", in: &pb.UpdateUserReq{FirstName: "Brian", LastName: "Anderson", Timezone: "Asia/Tokyo"}, want: models.User{FirstName: "Brian", LastName: "Anderson", Timezone: "Asia/Tokyo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{Id: 1, Uuid: "frank", TenantId: testTenantId, FirstName: "Frank", LastName: "Herbert", Timezone: "America/Toronto"}
+			repo := &testUserRepo{users: []*models.User{user}}
+			s := &Controller{userRepo: repo}
+			ctx := context.WithValue(context.Background(), "user", user)
+
+			tt.in.Uuid = user.Uuid
+			if _, err := s.UpdateUser(ctx, tt.in); err != nil {
+				t.Fatalf("UpdateUser failed: %v", err)
+			}
+			got := repo.users[0]
+			if got.FirstName != tt.want.FirstName || got.LastName != tt.want.LastName || got.Timezone != tt.want.Timezone {
+				t.Errorf("UpdateUser saved %q %q %q, want %q %q %q", got.FirstName, got.LastName, got.Timezone, tt.want.FirstName, tt.want.LastName, tt.want.Timezone)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	"github.com/bartmika/mothership-server/internal/utils"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// The most buckets a single query may return so a tiny step over a long
+// range cannot make us build a huge response.
+const maxBuckets = 11000
+
+// downsampler combines the selected data points into buckets.
+type downsampler func(points []*tstorage.DataPoint) ([]*tstorage.DataPoint, error)
+
+// Utility function which returns the downsampler requested by the filter or
+// nil if the raw data points were requested.
+func (s *Controller) newDownsampler(ctx context.Context, tenantId uint64, in *pb.FilterReq, precision string) (downsampler, error) {
+	if in.Step == nil {
+		if in.Aggregation != "" || in.AlignToTimezone {
+			return nil, status.Errorf(codes.InvalidArgument, "Aggregation requires a step")
+		}
+		return nil, nil
+	}
+
+	step := time.Duration(in.Step.Seconds)*time.Second + time.Duration(in.Step.Nanos)
+	if step <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Step must be greater than zero")
+	}
+	// The step cannot be finer than what the tenant's timestamps can tell.
+	if tsdb.ToStorageDuration(step, precision) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Step is smaller than the %q timestamp precision", precision)
+	}
+	if serializers.FromTimestamp(in.End).Sub(serializers.FromTimestamp(in.Start))/step > maxBuckets {
+		return nil, status.Errorf(codes.InvalidArgument, "Step is too small for the range, at most %v buckets are allowed", maxBuckets)
+	}
+
+	aggregation := in.Aggregation
+	if aggregation == "" {
+		aggregation = tsdb.AvgAggregation
+	}
+	if !utils.Contains(tsdb.Aggregations, aggregation) {
+		return nil, status.Errorf(codes.InvalidArgument, "Aggregation %q is not supported", aggregation)
+	}
+	if aggregation == tsdb.PercentileAggregation && (in.Percentile <= 0 || in.Percentile > 100) {
+		return nil, status.Errorf(codes.InvalidArgument, "Percentile must be greater than 0 and at most 100")
+	}
+
+	// Buckets are aligned to the midnight of the tenant's timezone if asked.
+	var loc *time.Location
+	if in.AlignToTimezone {
+		if !tsdb.IsAlignableStep(step) {
+			return nil, status.Errorf(codes.InvalidArgument, "Step longer than a day must be whole days to align it to the timezone")
+		}
+		tenant, err := s.tenantRepo.GetById(ctx, tenantId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if tenant == nil {
+			return nil, status.Errorf(codes.NotFound, "Tenant #%v does not exist", tenantId)
+		}
+		loc, err = utils.LoadLocation(tenant.Timezone)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "Timezone %q of your tenant is not supported", tenant.Timezone)
+		}
+	}
+
+	return func(points []*tstorage.DataPoint) ([]*tstorage.DataPoint, error) {
+		return tsdb.Downsample(points, precision, step, loc, aggregation, in.Percentile)
+	}, nil
+}
@@ -0,0 +1,393 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/promql"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+)
+
+// DEVELOPERS NOTE:
+// The HTTP API mirrors the query API of Prometheus so tools like Grafana can
+// use the server as a Prometheus data source. The response format is
+// documented at https://prometheus.io/docs/prometheus/latest/querying/api/.
+// Every endpoint is authorized as if the equivalent RPC was called so the
+// roles, API key scopes and read-only sessions apply the same way.
+
+// httpHandlerFunc handles an authorized request to the HTTP API and returns
+// the `data` of the response.
+type httpHandlerFunc func(ctx context.Context, r *http.Request) (interface{}, error)
+
+// Utility function which returns the handler of the HTTP API.
+func (s *Controller) newHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query", s.httpHandler("/proto.Mothership/Query", s.httpQuery))
+	mux.HandleFunc("/api/v1/query_range", s.httpHandler("/proto.Mothership/QueryRange", s.httpQueryRange))
+	mux.HandleFunc("/api/v1/labels", s.httpHandler("/proto.Mothership/ListLabelNames", s.httpLabels))
+	mux.HandleFunc("/api/v1/label/", s.httpHandler("/proto.Mothership/ListLabelValues", s.httpLabelValues))
+	mux.HandleFunc("/api/v1/series", s.httpHandler("/proto.Mothership/SelectSeries", s.httpSeries))
+	mux.HandleFunc("/api/v1/write", s.httpRemoteWrite)
+	mux.HandleFunc("/write", s.httpWriteLines)
+	return mux
+}
+
+// httpHandler function authorizes the request as if it called the RPC, runs
+// the handler and writes its result in the format Prometheus uses.
+func (s *Controller) httpHandler(method string, handler httpHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		var data interface{}
+		err := r.ParseForm()
+		if err != nil {
+			err = status.Errorf(codes.InvalidArgument, "Form is malformed: %v", err)
+		} else if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			err = status.Errorf(codes.Unimplemented, "Method %v is not allowed", r.Method)
+		} else {
+			var ctx context.Context
+			if ctx, err = s.authorizeHTTP(r, method); err == nil {
+				data, err = handler(ctx, r)
+			}
+		}
+
+		if err != nil {
+			writeHTTPError(w, err)
+		} else {
+			writeHTTPJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": data})
+		}
+
+		// Logging
+		log.Printf("HTTP - Path:%s\tDuration:%s\tError:%v\n",
+			r.URL.Path,
+			time.Since(start),
+			err)
+	}
+}
+
+// authorizeHTTP function authorizes the token of the request the same way the
+// token of an RPC is authorized and returns the context with our
+// authenticated user saved to it. The token is sent in the `Authorization`
+// header, either as a bearer token or as the password of basic auth (which is
+// easier to set up in Grafana).
+func (s *Controller) authorizeHTTP(r *http.Request, method string) (context.Context, error) {
+	md := metadata.MD{}
+	if _, password, ok := r.BasicAuth(); ok {
+		md.Set("authorization", password)
+	} else if token := r.Header.Get("Authorization"); token != "" {
+		// The InfluxDB clients send `Token <token>` instead of a bearer token.
+		md.Set("authorization", strings.TrimPrefix(token, "Token "))
+	}
+	if tenantId := r.Header.Get(tenantIdMetadataKey); tenantId != "" {
+		md.Set(tenantIdMetadataKey, tenantId)
+	}
+	return s.authorize(metadata.NewIncomingContext(r.Context(), md), method)
+}
+
+func (s *Controller) httpQuery(ctx context.Context, r *http.Request) (interface{}, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	t := time.Now()
+	if v := r.Form.Get("time"); v != "" {
+		var err error
+		if t, err = parseHTTPTime(v); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid time: %v", err)
+		}
+	}
+
+	res, err := promql.Query(&tenantQueryable{controller: s, tenantId: user.TenantId}, r.Form.Get("query"), t)
+	if err != nil {
+		return nil, err
+	}
+	return toHTTPQueryData(res), nil
+}
+
+func (s *Controller) httpQueryRange(ctx context.Context, r *http.Request) (interface{}, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	start, err := parseHTTPTime(r.Form.Get("start"))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid start: %v", err)
+	}
+	end, err := parseHTTPTime(r.Form.Get("end"))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid end: %v", err)
+	}
+	step, err := parseHTTPDuration(r.Form.Get("step"))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid step: %v", err)
+	}
+	if end.Before(start) {
+		return nil, status.Errorf(codes.InvalidArgument, "End timestamp must not be before start time")
+	}
+	if steps := int64(end.Sub(start)/step) + 1; steps > promql.MaxSteps {
+		return nil, status.Errorf(codes.InvalidArgument, "Exceeded maximum resolution of %v points per timeseries, try decreasing the query resolution (?step=XX)", promql.MaxSteps)
+	}
+
+	res, err := promql.QueryRange(&tenantQueryable{controller: s, tenantId: user.TenantId}, r.Form.Get("query"), start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	return toHTTPQueryData(res), nil
+}
+
+func (s *Controller) httpLabels(ctx context.Context, r *http.Request) (interface{}, error) {
+	entries, err := s.findHTTPSeries(ctx, r, false)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, tsdb.MetricNameLabel)
+		for _, l := range e.Labels {
+			names = append(names, l.Name)
+		}
+	}
+	return uniqueSorted(names), nil
+}
+
+func (s *Controller) httpLabelValues(ctx context.Context, r *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/label/")
+	if !strings.HasSuffix(name, "/values") {
+		return nil, status.Errorf(codes.NotFound, "Path %v does not exist", r.URL.Path)
+	}
+	name = strings.TrimSuffix(name, "/values")
+
+	entries, err := s.findHTTPSeries(ctx, r, false)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []string{}
+	for _, e := range entries {
+		if name == tsdb.MetricNameLabel {
+			values = append(values, e.Metric)
+		}
+		for _, l := range e.Labels {
+			if l.Name == name {
+				values = append(values, l.Value)
+			}
+		}
+	}
+	return uniqueSorted(values), nil
+}
+
+func (s *Controller) httpSeries(ctx context.Context, r *http.Request) (interface{}, error) {
+	entries, err := s.findHTTPSeries(ctx, r, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > maxSelectedSeries {
+		return nil, status.Errorf(codes.ResourceExhausted, "Query matches %v series, at most %v are allowed", len(entries), maxSelectedSeries)
+	}
+
+	arr := []map[string]string{}
+	for _, e := range entries {
+		series := map[string]string{tsdb.MetricNameLabel: e.Metric}
+		for _, l := range e.Labels {
+			series[l.Name] = l.Value
+		}
+		arr = append(arr, series)
+	}
+	return arr, nil
+}
+
+// Utility function which returns the series of the tenant matching any of the
+// `match[]` selectors of the request, or every series if there are none and
+// they are not required. Only series with data points between the optional
+// `start` and `end` of the request are returned.
+func (s *Controller) findHTTPSeries(ctx context.Context, r *http.Request, requireMatch bool) ([]*tsdb.IndexEntry, error) {
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	// Lookup the dedicated time-series storage instance for our particular tenant.
+	storage, err := s.getStorage(user.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	selectors := [][]*tsdb.Matcher{}
+	for _, v := range r.Form["match[]"] {
+		matchers, err := promql.ParseMetricSelector(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid match[] %q: %v", v, err)
+		}
+		selectors = append(selectors, matchers)
+	}
+	if requireMatch && len(selectors) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "No match[] parameter provided")
+	}
+
+	var start, end int64 = math.MinInt64, math.MaxInt64
+	if v := r.Form.Get("start"); v != "" {
+		t, err := parseHTTPTime(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid start: %v", err)
+		}
+		start = tsdb.ToStorageTime(t, storage.precision())
+	}
+	if v := r.Form.Get("end"); v != "" {
+		t, err := parseHTTPTime(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid end: %v", err)
+		}
+		end = tsdb.ToStorageTime(t, storage.precision()) + 1
+	}
+
+	return storage.index.Find(start, end, func(e *tsdb.IndexEntry) bool {
+		if len(selectors) == 0 {
+			return true
+		}
+		for _, matchers := range selectors {
+			if tsdb.MatchSeries(&e.Series, matchers) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// Utility function which parses a time sent to the HTTP API, either as a Unix
+// timestamp in seconds (with an optional fraction) or in RFC 3339 format.
+func parseHTTPTime(value string) (time.Time, error) {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		// Only accept the times which fit in Unix nanoseconds, which also
+		// rejects `NaN` and `Inf`.
+		if !(math.Abs(f) < math.MaxInt64/1e9) {
+			return time.Time{}, errors.New("cannot parse " + strconv.Quote(value) + " to a valid timestamp")
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || !tsdb.IsStorageTime(t, models.NanosecondsPrecision) {
+		return time.Time{}, errors.New("cannot parse " + strconv.Quote(value) + " to a valid timestamp")
+	}
+	return t, nil
+}
+
+// Utility function which parses a duration sent to the HTTP API, either in
+// seconds (with an optional fraction) or the way it is written in a query.
+func parseHTTPDuration(value string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		// Reject `NaN`, `Inf` and the durations which do not fit.
+		if !(math.Abs(f)*float64(time.Second) < math.MaxInt64) {
+			return 0, errors.New("cannot parse " + strconv.Quote(value) + " to a valid duration")
+		}
+		d := time.Duration(math.Round(f * float64(time.Second)))
+		if d <= 0 {
+			return 0, errors.New("duration must be greater than 0")
+		}
+		return d, nil
+	}
+	return promql.ParseDuration(value)
+}
+
+// Utility function which converts the result of a query into the `data` of
+// the response.
+func toHTTPQueryData(r *promql.Result) interface{} {
+	data := map[string]interface{}{"resultType": r.Type}
+	switch r.Type {
+	case promql.ValueTypeScalar:
+		data["result"] = toHTTPPoint(r.Scalar)
+	case promql.ValueTypeVector:
+		result := []interface{}{}
+		for _, sample := range r.Vector {
+			result = append(result, map[string]interface{}{
+				"metric": toHTTPLabels(sample.Labels),
+				"value":  toHTTPPoint(sample.Point),
+			})
+		}
+		data["result"] = result
+	case promql.ValueTypeMatrix:
+		result := []interface{}{}
+		for _, series := range r.Matrix {
+			values := make([][]interface{}, 0, len(series.Points))
+			for _, p := range series.Points {
+				values = append(values, toHTTPPoint(p))
+			}
+			result = append(result, map[string]interface{}{
+				"metric": toHTTPLabels(series.Labels),
+				"values": values,
+			})
+		}
+		data["result"] = result
+	}
+	return data
+}
+
+// Utility function which returns the point as a pair of the Unix timestamp in
+// seconds and the value as a string.
+func toHTTPPoint(p promql.Point) []interface{} {
+	return []interface{}{
+		json.Number(strconv.FormatFloat(float64(p.T)/1e9, 'f', -1, 64)),
+		strconv.FormatFloat(p.V, 'f', -1, 64),
+	}
+}
+
+func toHTTPLabels(labels promql.Labels) map[string]string {
+	m := make(map[string]string, len(labels))
+	for _, l := range labels {
+		m[l.Name] = l.Value
+	}
+	return m
+}
+
+// Utility function which writes the error in the format Prometheus uses.
+// Errors of the query itself are reported as `bad_data` and errors while
+// evaluating it as `execution`.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	code, errorType := http.StatusUnprocessableEntity, "execution"
+
+	var parseErr *promql.ParseError
+	if errors.As(err, &parseErr) {
+		code, errorType = http.StatusBadRequest, "bad_data"
+	} else if st, ok := status.FromError(err); ok {
+		err = errors.New(st.Message())
+		switch st.Code() {
+		case codes.InvalidArgument:
+			code, errorType = http.StatusBadRequest, "bad_data"
+		case codes.Unauthenticated:
+			code, errorType = http.StatusUnauthorized, "unauthorized"
+		case codes.PermissionDenied:
+			code, errorType = http.StatusForbidden, "forbidden"
+		case codes.NotFound:
+			code, errorType = http.StatusNotFound, "not_found"
+		case codes.Unimplemented:
+			code, errorType = http.StatusMethodNotAllowed, "bad_data"
+		case codes.ResourceExhausted, codes.FailedPrecondition:
+			code, errorType = http.StatusUnprocessableEntity, "execution"
+		default:
+			code, errorType = http.StatusInternalServerError, "internal"
+		}
+	}
+
+	writeHTTPJSON(w, code, map[string]interface{}{
+		"status":    "error",
+		"errorType": errorType,
+		"error":     err.Error(),
+	})
+}
+
+func writeHTTPJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("writeHTTPJSON | Encode | err", err)
+	}
+}
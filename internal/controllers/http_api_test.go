@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHTTPTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "1600000000", want: time.Unix(1600000000, 0)},
+		{value: "1600000000.5", want: time.Unix(1600000000, 500000000)},
+		{value: "-1.5", want: time.Unix(-2, 500000000)},
+		{value: "2020-09-13T12:26:40Z", want: time.Unix(1600000000, 0)},
+		{value: "2020-09-13T12:26:40.123456789+02:00", want: time.Unix(1599992800, 123456789)},
+		{value: "NaN", wantErr: true},
+		{value: "Inf", wantErr: true},
+		{value: "1e300", wantErr: true},
+		{value: "yesterday", wantErr: true},
+
+		// Times the query engine cannot tell in Unix nanoseconds.
+		{value: "9999-12-31T00:00:00Z", wantErr: true},
+		{value: "0001-01-01T00:00:00Z", wantErr: true},
+		{value: "2263-01-01T00:00:00Z", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseHTTPTime(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseHTTPTime(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHTTPTime(%q) failed: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseHTTPTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/lineprotocol"
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// The largest line protocol request we accept, after it was decompressed.
+const maxLineProtocolSize = 32 << 20
+
+// httpWriteLines handles the `/write` endpoint of the InfluxDB 1.x HTTP API
+// so Telegraf and devices which speak line protocol can send us their data.
+// The lines which could be parsed are written even if others could not, in
+// which case the response lists every line which was not written.
+func (s *Controller) httpWriteLines(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	n, errs, err := s.writeLinesHTTP(r)
+	switch {
+	case err != nil:
+		writeHTTPJSON(w, remoteWriteStatusCode(err), map[string]interface{}{
+			"error":  status.Convert(err).Message(),
+			"errors": errs,
+		})
+	case len(errs) > 0:
+		writeHTTPJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":  fmt.Sprintf("partial write: line %d: %s dropped=%d", errs[0].Line, errs[0].Error, len(errs)),
+			"errors": errs,
+		})
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	// Logging
+	log.Printf("HTTP - Path:%s\tRows:%v\tFailedLines:%v\tDuration:%s\tError:%v\n",
+		r.URL.Path,
+		n,
+		len(errs),
+		time.Since(start),
+		err)
+}
+
+// writeLinesHTTP function authorizes the request as if it called
+// `InsertBulkTimeSeriesData` and writes its lines into the tenant's storage.
+// Besides the `Authorization` header the token may be sent as the `p` query
+// parameter the InfluxDB 1.x clients use for the password.
+func (s *Controller) writeLinesHTTP(r *http.Request) (int, []*lineError, error) {
+	if r.Method != http.MethodPost {
+		return 0, nil, status.Errorf(codes.Unimplemented, "Method %v is not allowed", r.Method)
+	}
+
+	if p := r.URL.Query().Get("p"); p != "" && r.Header.Get("Authorization") == "" {
+		r.Header.Set("Authorization", p)
+	}
+	ctx, err := s.authorizeHTTP(r, "/proto.Mothership/InsertBulkTimeSeriesData")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	precision, err := lineprotocol.ParsePrecision(r.URL.Query().Get("precision"))
+	if err != nil {
+		return 0, nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	body := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return 0, nil, status.Errorf(codes.InvalidArgument, "Request is not gzip compressed: %v", err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(body, maxLineProtocolSize+1))
+	if err != nil {
+		return 0, nil, status.Errorf(codes.InvalidArgument, "Failed reading the request: %v", err)
+	}
+	if len(b) > maxLineProtocolSize {
+		return 0, nil, status.Errorf(codes.InvalidArgument, "Request is larger than %v bytes", maxLineProtocolSize)
+	}
+
+	return s.writeLines(user.TenantId, strings.Split(string(b), "\n"), 1, precision)
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// The largest remote write request we accept, after it was decompressed.
+const maxRemoteWriteSize = 32 << 20
+
+// The value Prometheus writes to mark a series which stopped being scraped.
+// It is not a real data point so we do not save it.
+const staleNaN uint64 = 0x7ff0000000000002
+
+// DEVELOPERS NOTE:
+// Prometheus retries a remote write which failed with a 5xx or 429 status
+// code and drops the data of any other failure, so only return a 4xx status
+// code if sending the same request again can never succeed. See
+// https://prometheus.io/docs/concepts/remote_write_spec/#retries-backoff.
+
+// httpRemoteWrite handles the snappy compressed `WriteRequest` protocol
+// buffers sent by the `remote_write` of Prometheus and its agents.
+func (s *Controller) httpRemoteWrite(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	n, err := s.remoteWrite(r)
+	if err != nil {
+		code := remoteWriteStatusCode(err)
+		if st, ok := status.FromError(err); ok {
+			http.Error(w, st.Message(), code)
+		} else {
+			http.Error(w, err.Error(), code)
+		}
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	// Logging
+	log.Printf("HTTP - Path:%s\tRows:%v\tDuration:%s\tError:%v\n",
+		r.URL.Path,
+		n,
+		time.Since(start),
+		err)
+}
+
+// remoteWrite function authorizes the request as if it called
+// `InsertBulkTimeSeriesData`, decodes it and inserts its samples into the
+// tenant's storage. Returns the number of rows inserted.
+func (s *Controller) remoteWrite(r *http.Request) (int, error) {
+	if r.Method != http.MethodPost {
+		return 0, status.Errorf(codes.Unimplemented, "Method %v is not allowed", r.Method)
+	}
+
+	ctx, err := s.authorizeHTTP(r, "/proto.Mothership/InsertBulkTimeSeriesData")
+	if err != nil {
+		return 0, err
+	}
+
+	// Get our authenticated user.
+	user := ctx.Value("user").(*models.User)
+
+	in, err := readRemoteWriteReq(r)
+	if err != nil {
+		return 0, err
+	}
+	data, err := toRemoteWriteData(in)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	count, err := s.insertRows(user.TenantId, func(precision string) []tstorage.Row {
+		return toRows(data, precision)
+	})
+	if status.Code(err) == codes.NotFound {
+		return 0, status.Errorf(codes.Unavailable, status.Convert(err).Message())
+	}
+	return count, err
+}
+
+// Utility function which reads the snappy compressed protocol buffer of the
+// request.
+func readRemoteWriteReq(r *http.Request) (*pb.RemoteWriteReq, error) {
+	compressed, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRemoteWriteSize+1))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed reading the request: %v", err)
+	}
+	if len(compressed) > maxRemoteWriteSize {
+		return nil, status.Errorf(codes.InvalidArgument, "Request is larger than %v bytes", maxRemoteWriteSize)
+	}
+
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Request is not snappy compressed: %v", err)
+	}
+	if size > maxRemoteWriteSize {
+		return nil, status.Errorf(codes.InvalidArgument, "Request is %v bytes, at most %v are allowed", size, maxRemoteWriteSize)
+	}
+	b, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Request is not snappy compressed: %v", err)
+	}
+
+	in := &pb.RemoteWriteReq{}
+	if err := proto.Unmarshal(b, in); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Request is not a valid write request: %v", err)
+	}
+	return in, nil
+}
+
+// Utility function which converts every sample of the request into the
+// datum we would get from `InsertBulkTimeSeriesData`, so both are saved the
+// same way. The `__name__` label of a series is its metric.
+func toRemoteWriteData(in *pb.RemoteWriteReq) ([]*pb.TimeSeriesDatumReq, error) {
+	data := []*pb.TimeSeriesDatumReq{}
+	for _, ts := range in.Timeseries {
+		metric := ""
+		labels := make([]*pb.LabelReq, 0, len(ts.Labels))
+		for _, l := range ts.Labels {
+			if l.Name == tsdb.MetricNameLabel {
+				metric = l.Value
+				continue
+			}
+			labels = append(labels, l)
+		}
+		if metric == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Series %v has no %v label", ts.Labels, tsdb.MetricNameLabel)
+		}
+
+		for _, sample := range ts.Samples {
+			if math.Float64bits(sample.Value) == staleNaN {
+				continue
+			}
+			data = append(data, &pb.TimeSeriesDatumReq{
+				Metric:    metric,
+				Labels:    labels,
+				Value:     sample.Value,
+				Timestamp: serializers.ToTimestamp(time.Unix(0, sample.Timestamp*int64(time.Millisecond))),
+			})
+		}
+	}
+	return data, nil
+}
+
+// Utility function which returns the HTTP status code of the error. Only
+// errors which will happen again if the request is retried get a 4xx status
+// code, except for 429 which asks Prometheus to slow down.
+func remoteWriteStatusCode(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unimplemented:
+		return http.StatusMethodNotAllowed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// The number of one second buckets used to calculate the ingestion rate.
+const ingestionRateWindow = 60
+
+// ingestionStats counts the rows inserted into every tenant since the server
+// started along with the rows inserted during each of the last seconds so we
+// can tell the current ingestion rate.
+type ingestionStats struct {
+	mu        sync.Mutex
+	startTime time.Time
+	tenants   map[uint64]*ingestionCounter
+}
+
+type ingestionCounter struct {
+	total   uint64
+	buckets [ingestionRateWindow]uint64
+	seconds [ingestionRateWindow]int64
+}
+
+// tenantIngestion is a snapshot of the ingestion of a tenant.
+type tenantIngestion struct {
+	TenantId      uint64
+	TotalRows     uint64
+	RowsPerSecond float64
+}
+
+func newIngestionStats() *ingestionStats {
+	return &ingestionStats{
+		startTime: time.Now(),
+		tenants:   make(map[uint64]*ingestionCounter),
+	}
+}
+
+// Record counts the rows inserted into the tenant.
+func (st *ingestionStats) Record(tenantId uint64, rows int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	c, ok := st.tenants[tenantId]
+	if !ok {
+		c = &ingestionCounter{}
+		st.tenants[tenantId] = c
+	}
+
+	now := time.Now().Unix()
+	i := now % ingestionRateWindow
+	if c.seconds[i] != now {
+		c.seconds[i] = now
+		c.buckets[i] = 0
+	}
+	c.buckets[i] += uint64(rows)
+	c.total += uint64(rows)
+}
+
+// Snapshot returns the ingestion of every tenant, ordered by tenant id.
+func (st *ingestionStats) Snapshot() []*tenantIngestion {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := time.Now().Unix()
+	arr := make([]*tenantIngestion, 0, len(st.tenants))
+	for tenantId, c := range st.tenants {
+		var recent uint64
+		for i := range c.buckets {
+			if now-c.seconds[i] < ingestionRateWindow {
+				recent += c.buckets[i]
+			}
+		}
+		arr = append(arr, &tenantIngestion{
+			TenantId:      tenantId,
+			TotalRows:     c.total,
+			RowsPerSecond: float64(recent) / ingestionRateWindow,
+		})
+	}
+
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].TenantId < arr[j].TenantId
+	})
+	return arr
+}
+
+// Delete forgets the ingestion of the tenant.
+func (st *ingestionStats) Delete(tenantId uint64) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.tenants, tenantId)
+}
@@ -3,25 +3,21 @@ package controllers
 import (
 	"context"
 	"log"
-	// // "io"
-	"errors"
+	"strings"
 	"time"
-	// "strings"
-	//
-	// "github.com/google/uuid"
-	// "github.com/golang/protobuf/ptypes/empty"
-	// // tspb "github.com/golang/protobuf/ptypes/timestamp"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
-	//
-	// pb "github.com/bartmika/mothership-server/proto"
-	// "github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/models"
 	"github.com/bartmika/mothership-server/internal/utils"
 )
 
+// How often we write the `last_used_time` of an API key to the database.
+const apiKeyLastUsedResolution = time.Minute
+
 func withServerUnaryInterceptor(s *Controller) grpc.ServerOption {
 	return grpc.UnaryInterceptor(s.serverInterceptor)
 }
@@ -33,35 +29,13 @@ func (s *Controller) serverInterceptor(ctx context.Context,
 	handler grpc.UnaryHandler) (interface{}, error) {
 	start := time.Now()
 
-	// Skip authorization for the following RPC paths
-	ignoreMethods := map[string]bool{
-		"/proto.Mothership/Login":        true,
-		"/proto.Mothership/Register":     true,
-		"/proto.Mothership/RefreshToken": true,
-	}
-
-	// Perform our skip on authorization now.
-	if ignoreMethods[info.FullMethod] == false {
-		sessionUuid, err := s.authorize(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		// Lookup our user profile in the session or return 500 error.
-		user, err := s.manager.GetUser(ctx, sessionUuid)
+	// Skip authorization for the public RPC paths.
+	if !isPublicMethod(info.FullMethod) {
+		var err error
+		ctx, err = s.authorize(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
-
-		// If no user was found then that means our session expired and the
-		// user needs to login or use the refresh token.
-		if user == nil {
-			return nil, errors.New("Session expired - please log in again")
-		}
-
-		// Save our user information to the context.
-		ctx = context.WithValue(ctx, "user", user)
-		ctx = context.WithValue(ctx, "session_uuid", sessionUuid)
 	}
 
 	// Calls the handler
@@ -76,27 +50,192 @@ func (s *Controller) serverInterceptor(ctx context.Context,
 	return h, err
 }
 
-// authorize function authorizes the token received from Metadata
-func (s *Controller) authorize(ctx context.Context) (string, error) {
+func withServerStreamInterceptor(s *Controller) grpc.ServerOption {
+	return grpc.StreamInterceptor(s.streamInterceptor)
+}
+
+// Authorization stream interceptor function to handle authorize per streaming
+// RPC call. The authenticated user is made available to the handler through
+// the context of the wrapped stream.
+func (s *Controller) streamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	// Skip authorization for the public RPC paths.
+	if !isPublicMethod(info.FullMethod) {
+		ctx, err := s.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		stream = &wrappedStream{ServerStream: stream, ctx: ctx}
+	}
+
+	// Calls the handler
+	err := handler(srv, stream)
+
+	// Logging
+	log.Printf("Stream - Method:%s\tDuration:%s\tError:%v\n",
+		info.FullMethod,
+		time.Since(start),
+		err)
+
+	return err
+}
+
+// wrappedStream overrides the context of the `grpc.ServerStream` so we can
+// pass values from the stream interceptor to the handler.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// authorize function authorizes the token received from Metadata, makes sure
+// the user is allowed to call the RPC and returns the context with our
+// authenticated user saved to it.
+func (s *Controller) authorize(ctx context.Context, method string) (context.Context, error) {
+	ctx, err := s.authenticate(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	user := ctx.Value("user").(*models.User)
+	if err := s.checkIsActive(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Read-only sessions are already bound to the tenant they impersonate.
+	if readOnly, _ := ctx.Value("read_only").(bool); readOnly {
+		if !readOnlyMethods[method] {
+			return nil, status.Errorf(codes.PermissionDenied, "Method %v is not allowed while impersonating", method)
+		}
+		return ctx, nil
+	}
+
+	if err := checkPermission(user, method); err != nil {
+		return nil, err
+	}
+
+	// API keys are bound to the tenant they were created in.
+	if _, ok := ctx.Value("api_key").(*models.APIKey); ok {
+		return ctx, nil
+	}
+
+	// Root users may act on behalf of other tenants.
+	user, err = s.actAsTenant(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, "user", user), nil
+}
+
+// authenticate function validates the token received from Metadata and
+// returns the context with our authenticated user saved to it.
+func (s *Controller) authenticate(ctx context.Context, method string) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", status.Errorf(codes.InvalidArgument, "Retrieving metadata is failed")
+		return nil, status.Errorf(codes.InvalidArgument, "Retrieving metadata is failed")
 	}
 
 	authHeader, ok := md["authorization"]
 	if !ok {
-		return "", status.Errorf(codes.Unauthenticated, "Authorization token is not supplied")
+		return nil, status.Errorf(codes.Unauthenticated, "Authorization token is not supplied")
 	}
 
-	token := authHeader[0]
+	token := strings.TrimSpace(strings.TrimPrefix(authHeader[0], "Bearer "))
 
+	if utils.IsAPIKey(token) {
+		return s.authorizeAPIKey(ctx, method, token)
+	}
+	return s.authorizeSession(ctx, token)
+}
+
+// authorizeSession function validates the `access token` and looks up the user
+// in the session.
+func (s *Controller) authorizeSession(ctx context.Context, token string) (context.Context, error) {
 	// validateToken function validates the token
 	sessionUuid, err := utils.ProcessBearerToken([]byte(s.hmacSecret), token)
 	if err != nil {
-		return "", status.Errorf(codes.Unauthenticated, err.Error())
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	// Lookup our user profile in the session or return 500 error.
+	sess, err := s.manager.GetSession(ctx, sessionUuid)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// If no session was found then that means our session expired and the
+	// user needs to login or use the refresh token.
+	if sess == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Session expired - please log in again")
+	}
+
+	// Save our user information to the context.
+	ctx = context.WithValue(ctx, "user", sess.User)
+	ctx = context.WithValue(ctx, "session_uuid", sessionUuid)
+	ctx = context.WithValue(ctx, "read_only", sess.ReadOnly)
+	return ctx, nil
+}
+
+// authorizeAPIKey function validates the API key, makes sure it was granted
+// the scope required by the RPC and looks up the user who owns the key.
+func (s *Controller) authorizeAPIKey(ctx context.Context, method string, key string) (context.Context, error) {
+	prefix, ok := utils.ParseAPIKey(key)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "API key is malformed")
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if apiKey == nil || !utils.CheckTokenHash(key, apiKey.KeyHash) {
+		return nil, status.Errorf(codes.Unauthenticated, "API key is invalid")
+	}
+	if apiKey.State != models.APIKeyActiveState {
+		return nil, status.Errorf(codes.Unauthenticated, "API key was revoked")
+	}
+	if apiKey.IsExpired() {
+		return nil, status.Errorf(codes.Unauthenticated, "API key expired")
+	}
+
+	scope, ok := apiKeyScopesByMethod[method]
+	if !ok || !apiKey.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key is not allowed to call %v", method)
+	}
+
+	user, err := s.userRepo.GetById(ctx, apiKey.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "API key is invalid")
+	}
+
+	// The key only grants access to the tenant it was created in, even if
+	// its owner was moved to another tenant since.
+	user.TenantId = apiKey.TenantId
+
+	// Keep track of when the key was last used without writing to the
+	// database on every single request.
+	now := time.Now()
+	if apiKey.LastUsedTime == nil || now.Sub(*apiKey.LastUsedTime) > apiKeyLastUsedResolution {
+		if err := s.apiKeyRepo.UpdateLastUsedTimeById(ctx, apiKey.Id, now); err != nil {
+			log.Println("authorizeAPIKey | UpdateLastUsedTimeById | err", err)
+		}
 	}
 
-	return sessionUuid, nil
+	// Save our user information to the context.
+	ctx = context.WithValue(ctx, "user", user)
+	ctx = context.WithValue(ctx, "api_key", apiKey)
+	return ctx, nil
 }
 
 // DEVELOPERS NOTES:
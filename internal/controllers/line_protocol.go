@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/lineprotocol"
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/serializers"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// The longest line the TCP listener accepts.
+const maxLineProtocolLineSize = 1 << 20
+
+// How long a TCP connection may stay idle before we close it.
+const lineProtocolIdleTimeout = 5 * time.Minute
+
+// How often the token of a TCP connection is authorized again, so a session
+// which was logged out, an API key which was revoked or a tenant which was
+// suspended stops writing into the tenant.
+var lineProtocolReauthorizeInterval = tenantStateCacheTTL
+
+// DEVELOPERS NOTE:
+// The raw TCP and UDP listeners have no headers to send a token in, so the
+// first line of every TCP connection and of every UDP packet must be
+// `auth <token>`, where the token is an access token or an API key with the
+// `ingest` scope. Every line after it is written into the tenant of the token.
+
+// lineError is a line which could not be written along with why.
+type lineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// writeLines function parses the lines of InfluxDB line protocol and inserts
+// a row for every field into the tenant's storage: the metric is the
+// measurement and field joined by `_` and the tags are the labels. The lines
+// are numbered starting at `firstLine`. Returns the number of rows inserted
+// and the lines which could not be parsed; the error is only returned if the
+// rows could not be inserted.
+func (s *Controller) writeLines(tenantId uint64, lines []string, firstLine int, precision time.Duration) (int, []*lineError, error) {
+	data := []*pb.TimeSeriesDatumReq{}
+	errs := []*lineError{}
+	for i, line := range lines {
+		if lineprotocol.IsBlank(line) {
+			continue
+		}
+		p, err := lineprotocol.ParseLine(line, precision)
+		if err != nil {
+			errs = append(errs, &lineError{Line: firstLine + i, Error: err.Error()})
+			continue
+		}
+		data = append(data, toLineProtocolData(p)...)
+	}
+	if len(data) == 0 {
+		return 0, errs, nil
+	}
+
+	count, err := s.insertRows(tenantId, func(precision string) []tstorage.Row {
+		return toRows(data, precision)
+	})
+	return count, errs, err
+}
+
+// Utility function which converts every field of the point into the datum we
+// would get from `InsertBulkTimeSeriesData`, so both are saved the same way.
+// A point without a timestamp is recorded at the time it arrived.
+func toLineProtocolData(p *lineprotocol.Point) []*pb.TimeSeriesDatumReq {
+	labels := make([]*pb.LabelReq, 0, len(p.Tags))
+	for _, tag := range p.Tags {
+		labels = append(labels, &pb.LabelReq{Name: tag.Key, Value: tag.Value})
+	}
+
+	data := make([]*pb.TimeSeriesDatumReq, 0, len(p.Fields))
+	for _, field := range p.Fields {
+		datum := &pb.TimeSeriesDatumReq{
+			Metric: p.Measurement + "_" + field.Key,
+			Labels: labels,
+			Value:  field.Value,
+		}
+		if !p.Time.IsZero() {
+			datum.Timestamp = serializers.ToTimestamp(p.Time)
+		}
+		data = append(data, datum)
+	}
+	return data
+}
+
+// authorizeLineProtocol function authorizes the `auth <token>` line the raw
+// listeners expect first as if `InsertBulkTimeSeriesData` was called and
+// returns our authenticated user.
+func (s *Controller) authorizeLineProtocol(ctx context.Context, line string) (*models.User, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != "auth" {
+		return nil, status.Errorf(codes.Unauthenticated, "The first line must be `auth <token>`")
+	}
+
+	md := metadata.Pairs("authorization", fields[1])
+	ctx, err := s.authorize(metadata.NewIncomingContext(ctx, md), "/proto.Mothership/InsertBulkTimeSeriesData")
+	if err != nil {
+		return nil, err
+	}
+	return ctx.Value("user").(*models.User), nil
+}
+
+// lineProtocolServer accepts InfluxDB line protocol over raw TCP connections
+// and UDP packets.
+type lineProtocolServer struct {
+	controller *Controller
+	tcp        net.Listener
+	udp        net.PacketConn
+	mu         sync.Mutex
+	conns      map[net.Conn]bool
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+// Utility function which starts listening for line protocol on the addresses,
+// either of which may be empty to not listen on it.
+func (s *Controller) listenLineProtocol(tcpAddress string, udpAddress string) (*lineProtocolServer, error) {
+	srv := &lineProtocolServer{controller: s, conns: make(map[net.Conn]bool)}
+	if tcpAddress != "" {
+		lis, err := net.Listen("tcp", tcpAddress)
+		if err != nil {
+			return nil, err
+		}
+		srv.tcp = lis
+		srv.wg.Add(1)
+		go srv.serveTCP()
+		log.Printf("Line protocol TCP listener is running on %v", tcpAddress)
+	}
+	if udpAddress != "" {
+		conn, err := net.ListenPacket("udp", udpAddress)
+		if err != nil {
+			srv.Close()
+			return nil, err
+		}
+		srv.udp = conn
+		srv.wg.Add(1)
+		go srv.serveUDP()
+		log.Printf("Line protocol UDP listener is running on %v", udpAddress)
+	}
+	return srv, nil
+}
+
+// Close stops the listeners, closes every open connection and waits until
+// the lines being written are finished.
+func (srv *lineProtocolServer) Close() {
+	if srv.tcp != nil {
+		srv.tcp.Close()
+	}
+	if srv.udp != nil {
+		srv.udp.Close()
+	}
+	srv.mu.Lock()
+	srv.closed = true
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mu.Unlock()
+	srv.wg.Wait()
+}
+
+func (srv *lineProtocolServer) serveTCP() {
+	defer srv.wg.Done()
+	for {
+		conn, err := srv.tcp.Accept()
+		if err != nil {
+			// The listener was closed.
+			return
+		}
+		srv.mu.Lock()
+		if srv.closed {
+			srv.mu.Unlock()
+			conn.Close()
+			return
+		}
+		srv.conns[conn] = true
+		srv.wg.Add(1)
+		srv.mu.Unlock()
+
+		go srv.serveConn(conn)
+	}
+}
+
+// serveConn writes every line received on the connection after the `auth`
+// line and sends back an `error line <n>: <reason>` line for every line
+// which could not be written. The connection is closed once its token is no
+// longer authorized.
+func (srv *lineProtocolServer) serveConn(conn net.Conn) {
+	defer srv.wg.Done()
+	defer func() {
+		srv.mu.Lock()
+		delete(srv.conns, conn)
+		srv.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxLineProtocolLineSize)
+
+	var user *models.User
+	var authLine string
+	var authorizedAt time.Time
+	n := 0
+	for {
+		conn.SetReadDeadline(time.Now().Add(lineProtocolIdleTimeout))
+		if !scanner.Scan() {
+			break
+		}
+		n++
+		line := scanner.Text()
+		if lineprotocol.IsBlank(line) {
+			continue
+		}
+
+		if user == nil || time.Since(authorizedAt) >= lineProtocolReauthorizeInterval {
+			first := user == nil
+			if first {
+				authLine = line
+			}
+			var err error
+			if user, err = srv.controller.authorizeLineProtocol(context.Background(), authLine); err != nil {
+				fmt.Fprintf(conn, "error: %v\n", status.Convert(err).Message())
+				log.Printf("TCP - Remote:%s\tError:%v\n", conn.RemoteAddr(), err)
+				return
+			}
+			authorizedAt = time.Now()
+			if first {
+				continue
+			}
+		}
+
+		_, errs, err := srv.controller.writeLines(user.TenantId, []string{line}, n, time.Nanosecond)
+		if err != nil {
+			errs = append(errs, &lineError{Line: n, Error: status.Convert(err).Message()})
+		}
+		for _, e := range errs {
+			fmt.Fprintf(conn, "error line %d: %s\n", e.Line, e.Error)
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Printf("TCP - Remote:%s\tError:%v\n", conn.RemoteAddr(), err)
+	}
+}
+
+// serveUDP writes the lines of every packet after its `auth` line. Lines
+// which could not be written are only logged as there is no one to answer.
+func (srv *lineProtocolServer) serveUDP() {
+	defer srv.wg.Done()
+	buf := make([]byte, 64*1024)
+	for {
+		size, addr, err := srv.udp.ReadFrom(buf)
+		if err != nil {
+			// The listener was closed.
+			return
+		}
+		lines := strings.Split(string(buf[:size]), "\n")
+
+		// Skip to the `auth` line.
+		first := 0
+		for first < len(lines) && lineprotocol.IsBlank(lines[first]) {
+			first++
+		}
+		if first == len(lines) {
+			continue
+		}
+		user, err := srv.controller.authorizeLineProtocol(context.Background(), lines[first])
+		if err != nil {
+			log.Printf("UDP - Remote:%s\tError:%v\n", addr, err)
+			continue
+		}
+
+		_, errs, err := srv.controller.writeLines(user.TenantId, lines[first+1:], first+2, time.Nanosecond)
+		for _, e := range errs {
+			log.Printf("UDP - Remote:%s\tLine:%d\tError:%s\n", addr, e.Line, e.Error)
+		}
+		if err != nil {
+			log.Printf("UDP - Remote:%s\tError:%v\n", addr, err)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	"github.com/bartmika/mothership-server/internal/session"
+	"github.com/bartmika/mothership-server/internal/utils"
+)
+
+const testTenantId = 1
+
+// Utility function which returns a controller with only the storage of our
+// test tenant, which is active, so no database is needed.
+func newTestController(t *testing.T) *Controller {
+	storage, err := newTenantStorage(t.TempDir(), models.DefaultTenantStorageConfig(testTenantId))
+	if err != nil {
+		t.Fatalf("newTenantStorage failed: %v", err)
+	}
+	manager := session.NewMemory()
+	t.Cleanup(func() {
+		storage.Close()
+		manager.Close()
+	})
+
+	s := &Controller{
+		hmacSecret:   "secret",
+		manager:      manager,
+		storageMap:   map[uint64]*tenantStorage{testTenantId: storage},
+		tenantStates: newTenantStateCache(tenantStateCacheTTL),
+		ingestion:    newIngestionStats(),
+	}
+	s.tenantStates.Set(testTenantId, models.TenantActiveState)
+	return s
+}
+
+// Utility function which logs in a tenant admin of our test tenant and
+// returns their access token.
+func newTestAccessToken(t *testing.T, s *Controller) string {
+	user := &models.User{
+		Id:       1,
+		TenantId: testTenantId,
+		RoleId:   models.UserTenantAdminRoleId,
+		State:    models.UserActiveState,
+	}
+	ctx := context.Background()
+	sess := newSession(ctx, user, newFamilyUuid())
+	if err := s.manager.SaveSession(ctx, sess, time.Hour); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	accessToken, _, err := utils.GenerateJWTTokenPair([]byte(s.hmacSecret), sess.Uuid, sess.FamilyUuid, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWTTokenPair failed: %v", err)
+	}
+	return accessToken
+}
+
+// Utility function which returns every series saved in the storage of our
+// test tenant.
+func storedSeries(t *testing.T, s *Controller) []string {
+	storage, err := s.getStorage(testTenantId)
+	if err != nil {
+		t.Fatalf("getStorage failed: %v", err)
+	}
+	arr := []string{}
+	for _, e := range storage.index.Find(0, math.MaxInt64, nil) {
+		arr = append(arr, e.String())
+	}
+	return arr
+}
+
+// Utility function which returns the values of the series saved in the
+// storage of our test tenant.
+func storedValues(t *testing.T, s *Controller, metric string, labels ...tstorage.Label) []float64 {
+	storage, err := s.getStorage(testTenantId)
+	if err != nil {
+		t.Fatalf("getStorage failed: %v", err)
+	}
+	points, err := storage.Select(metric, labels, 0, math.MaxInt64)
+	if err != nil {
+		t.Fatalf("Select(%v) failed: %v", metric, err)
+	}
+	values := []float64{}
+	for _, p := range points {
+		values = append(values, p.Value)
+	}
+	return values
+}
+
+func TestWriteLines(t *testing.T) {
+	s := newTestController(t)
+	now := time.Now().Truncate(time.Second)
+
+	lines := []string{
+		"# The weather outside.",
+		fmt.Sprintf("weather,location=us temperature=82,humidity=71 %d", now.UnixNano()),
+		"",
+		"weather,location=us temperature=hot",
+		fmt.Sprintf(`weather,location=ca temperature=65,description="cold" %d`, now.UnixNano()),
+		"weather",
+		fmt.Sprintf("switch on=true %d", now.UnixNano()),
+	}
+	count, errs, err := s.writeLines(testTenantId, lines, 10, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("writeLines failed: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("writeLines inserted %d rows, want 4", count)
+	}
+	var errLines []int
+	for _, e := range errs {
+		errLines = append(errLines, e.Line)
+	}
+	if want := []int{13, 15}; !reflect.DeepEqual(errLines, want) {
+		t.Errorf("writeLines failed on lines %v, want %v", errLines, want)
+	}
+
+	want := []string{
+		"switch_on",
+		`weather_humidity{location="us"}`,
+		`weather_temperature{location="ca"}`,
+		`weather_temperature{location="us"}`,
+	}
+	if got := storedSeries(t, s); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored series = %v, want %v", got, want)
+	}
+	if got := storedValues(t, s, "weather_temperature", tstorage.Label{Name: "location", Value: "ca"}); !reflect.DeepEqual(got, []float64{65}) {
+		t.Errorf("weather_temperature{location=\"ca\"} = %v, want [65]", got)
+	}
+	if got := storedValues(t, s, "switch_on"); !reflect.DeepEqual(got, []float64{1}) {
+		t.Errorf("switch_on = %v, want [1]", got)
+	}
+
+	// Nothing is inserted when no line could be parsed.
+	count, errs, err = s.writeLines(testTenantId, []string{"weather"}, 1, time.Nanosecond)
+	if err != nil || count != 0 || len(errs) != 1 {
+		t.Errorf("writeLines of an invalid line = %d, %v, %v, want 0, 1 error, nil", count, errs, err)
+	}
+}
+
+// Utility function which sends the lines over a new TCP connection to the
+// server and returns every line it answered with until it closed the
+// connection.
+func sendLineProtocolTCP(t *testing.T, srv *lineProtocolServer, lines ...string) []string {
+	conn, err := net.Dial("tcp", srv.tcp.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprint(conn, strings.Join(lines, "\n")+"\n"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// The server closes the connection once it read every line.
+	conn.(*net.TCPConn).CloseWrite()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	replies := []string{}
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		replies = append(replies, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	return replies
+}
+
+func TestLineProtocolTCP(t *testing.T) {
+	s := newTestController(t)
+	token := newTestAccessToken(t, s)
+	srv, err := s.listenLineProtocol("127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("listenLineProtocol failed: %v", err)
+	}
+	defer srv.Close()
+	now := time.Now().Truncate(time.Second)
+
+	// The connection is closed unless the first line authorizes it.
+	replies := sendLineProtocolTCP(t, srv, fmt.Sprintf("weather temperature=1 %d", now.UnixNano()))
+	if want := []string{"error: The first line must be `auth <token>`"}; !reflect.DeepEqual(replies, want) {
+		t.Errorf("replies without auth = %q, want %q", replies, want)
+	}
+	replies = sendLineProtocolTCP(t, srv, "auth bad-token", fmt.Sprintf("weather temperature=2 %d", now.UnixNano()))
+	if len(replies) != 1 || !strings.HasPrefix(replies[0], "error: ") {
+		t.Errorf("replies with a bad token = %q, want an error", replies)
+	}
+
+	// The trailing invalid line tells us every line before it was written.
+	replies = sendLineProtocolTCP(t, srv,
+		"",
+		"auth "+token,
+		fmt.Sprintf("weather,location=us temperature=82 %d", now.UnixNano()),
+		fmt.Sprintf("weather,location=us temperature=83 %d", now.Add(time.Second).UnixNano()),
+		"weather",
+	)
+	if len(replies) != 1 || !strings.HasPrefix(replies[0], "error line 5: ") {
+		t.Errorf("replies = %q, want an error for line 5", replies)
+	}
+
+	if want := []string{`weather_temperature{location="us"}`}; !reflect.DeepEqual(storedSeries(t, s), want) {
+		t.Errorf("stored series = %v, want %v", storedSeries(t, s), want)
+	}
+	if got := storedValues(t, s, "weather_temperature", tstorage.Label{Name: "location", Value: "us"}); !reflect.DeepEqual(got, []float64{82, 83}) {
+		t.Errorf("weather_temperature = %v, want [82 83]", got)
+	}
+}
+
+func TestLineProtocolTCPReauthorize(t *testing.T) {
+	// Authorize the token again for every line.
+	defer func(d time.Duration) { lineProtocolReauthorizeInterval = d }(lineProtocolReauthorizeInterval)
+	lineProtocolReauthorizeInterval = 0
+
+	s := newTestController(t)
+	token := newTestAccessToken(t, s)
+	srv, err := s.listenLineProtocol("127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("listenLineProtocol failed: %v", err)
+	}
+	defer srv.Close()
+	now := time.Now().Truncate(time.Second)
+
+	conn, err := net.Dial("tcp", srv.tcp.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "auth %s\nweather temperature=1 %d\n", token, now.UnixNano())
+	deadline := time.Now().Add(2 * time.Second)
+	for len(storedSeries(t, s)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := storedValues(t, s, "weather_temperature"); !reflect.DeepEqual(got, []float64{1}) {
+		t.Fatalf("weather_temperature = %v, want [1]", got)
+	}
+
+	// Once the user logged out, the next line closes the connection.
+	ctx := context.Background()
+	sessions, err := s.manager.ListSessionsByUserId(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListSessionsByUserId failed: %v", err)
+	}
+	for _, sess := range sessions {
+		if err := s.manager.DeleteSession(ctx, sess.Uuid); err != nil {
+			t.Fatalf("DeleteSession failed: %v", err)
+		}
+	}
+	fmt.Fprintf(conn, "weather temperature=2 %d\n", now.Add(time.Second).UnixNano())
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	replies := []string{}
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		replies = append(replies, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(replies) != 1 || !strings.HasPrefix(replies[0], "error: ") {
+		t.Errorf("replies after logging out = %q, want an error", replies)
+	}
+	if got := storedValues(t, s, "weather_temperature"); !reflect.DeepEqual(got, []float64{1}) {
+		t.Errorf("weather_temperature = %v, want [1]", got)
+	}
+}
+
+func TestLineProtocolUDP(t *testing.T) {
+	s := newTestController(t)
+	token := newTestAccessToken(t, s)
+	srv, err := s.listenLineProtocol("", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listenLineProtocol failed: %v", err)
+	}
+	defer srv.Close()
+	now := time.Now().Truncate(time.Second)
+
+	conn, err := net.Dial("udp", srv.udp.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	packets := []string{
+		fmt.Sprintf("weather,packet=noauth temperature=1 %d", now.UnixNano()),
+		fmt.Sprintf("auth bad-token\nweather,packet=badtoken temperature=2 %d", now.UnixNano()),
+		fmt.Sprintf("\nauth %s\nweather,packet=valid temperature=3 %d\nweather", token, now.UnixNano()),
+	}
+	for _, packet := range packets {
+		if _, err := conn.Write([]byte(packet)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// The packets are handled in order, so once the valid one is written the
+	// others were already dropped.
+	want := []string{`weather_temperature{packet="valid"}`}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(storedSeries(t, s)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := storedSeries(t, s); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored series = %v, want %v", got, want)
+	}
+}
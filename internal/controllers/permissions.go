@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// permission is the level of access required to call an RPC. The levels are
+// ordered so a role which is granted a level is also granted every level
+// below it.
+type permission int8
+
+const (
+	// Anyone can call the RPC without being authenticated.
+	permissionPublic permission = iota
+	// Read the tenant's time-series data.
+	permissionRead
+	// Write time-series data into the tenant.
+	permissionWrite
+	// Manage the tenant's users, API keys and settings.
+	permissionManage
+	// Manage the whole installation across tenants.
+	permissionRoot
+)
+
+// The permission required to call each RPC. Any RPC which is missing from
+// this table is denied so new RPCs must be added here.
+var methodPermissions = map[string]permission{
+	"/proto.Mothership/Register":                 permissionPublic,
+	"/proto.Mothership/Login":                    permissionPublic,
+	"/proto.Mothership/RefreshToken":             permissionPublic,
+	"/proto.Mothership/CompleteLogin":            permissionPublic,
+	"/proto.Mothership/InsertTimeSeriesDatum":    permissionWrite,
+	"/proto.Mothership/InsertTimeSeriesData":     permissionWrite,
+	"/proto.Mothership/InsertBulkTimeSeriesData": permissionWrite,
+	"/proto.Mothership/SelectBulkTimeSeriesData": permissionRead,
+	"/proto.Mothership/StreamTimeSeriesData":     permissionRead,
+	"/proto.Mothership/SelectSeries":             permissionRead,
+	"/proto.Mothership/ListMetrics":              permissionRead,
+	"/proto.Mothership/ListLabelNames":           permissionRead,
+	"/proto.Mothership/ListLabelValues":          permissionRead,
+	"/proto.Mothership/Query":                    permissionRead,
+	"/proto.Mothership/QueryRange":               permissionRead,
+	"/proto.Mothership/CreateAPIKey":             permissionManage,
+	"/proto.Mothership/ListAPIKeys":              permissionManage,
+	"/proto.Mothership/RevokeAPIKey":             permissionManage,
+	"/proto.Mothership/Logout":                   permissionRead,
+	"/proto.Mothership/ListSessions":             permissionRead,
+	"/proto.Mothership/RevokeSession":            permissionRead,
+	"/proto.Mothership/RevokeAllSessions":        permissionRead,
+	"/proto.Mothership/RequestPasswordReset":     permissionPublic,
+	"/proto.Mothership/ConfirmPasswordReset":     permissionPublic,
+	"/proto.Mothership/VerifyEmail":              permissionPublic,
+	"/proto.Mothership/ResendVerification":       permissionPublic,
+	"/proto.Mothership/EnrollTOTP":               permissionRead,
+	"/proto.Mothership/ConfirmTOTP":              permissionRead,
+	"/proto.Mothership/DisableTOTP":              permissionRead,
+	"/proto.Mothership/InviteUser":               permissionManage,
+	"/proto.Mothership/ListInvitations":          permissionManage,
+	"/proto.Mothership/RevokeInvitation":         permissionManage,
+	"/proto.Mothership/AcceptInvitation":         permissionPublic,
+	"/proto.Mothership/ListUsers":                permissionManage,
+	"/proto.Mothership/UpdateUser":               permissionManage,
+	"/proto.Mothership/ChangeUserRole":           permissionManage,
+	"/proto.Mothership/DeactivateUser":           permissionManage,
+	"/proto.Mothership/ActivateUser":             permissionManage,
+	"/proto.Mothership/SuspendTenant":            permissionRoot,
+	"/proto.Mothership/ReactivateTenant":         permissionRoot,
+	"/proto.Mothership/GetTenant":                permissionRead,
+	"/proto.Mothership/UpdateTenant":             permissionManage,
+	"/proto.Mothership/DeleteTenant":             permissionManage,
+	"/proto.Mothership/GetStorageConfig":         permissionManage,
+	"/proto.Mothership/UpdateStorageConfig":      permissionManage,
+	"/proto.Mothership/GetRetention":             permissionManage,
+	"/proto.Mothership/UpdateRetention":          permissionManage,
+	"/proto.MothershipAdmin/ListTenants":         permissionRoot,
+	"/proto.MothershipAdmin/Impersonate":         permissionRoot,
+	"/proto.MothershipAdmin/ForceLogout":         permissionRoot,
+	"/proto.MothershipAdmin/GetIngestionStats":   permissionRoot,
+	"/proto.MothershipAdmin/GetRetentionStats":   permissionRoot,
+}
+
+// The scope an API key must have been granted to call the RPC. API keys are
+// not allowed to call any RPC which is not listed here.
+var apiKeyScopesByMethod = map[string]string{
+	"/proto.Mothership/InsertTimeSeriesDatum":    models.APIKeyIngestScope,
+	"/proto.Mothership/InsertTimeSeriesData":     models.APIKeyIngestScope,
+	"/proto.Mothership/InsertBulkTimeSeriesData": models.APIKeyIngestScope,
+	"/proto.Mothership/SelectBulkTimeSeriesData": models.APIKeyReadScope,
+	"/proto.Mothership/StreamTimeSeriesData":     models.APIKeyReadScope,
+	"/proto.Mothership/SelectSeries":             models.APIKeyReadScope,
+	"/proto.Mothership/ListMetrics":              models.APIKeyReadScope,
+	"/proto.Mothership/ListLabelNames":           models.APIKeyReadScope,
+	"/proto.Mothership/ListLabelValues":          models.APIKeyReadScope,
+	"/proto.Mothership/Query":                    models.APIKeyReadScope,
+	"/proto.Mothership/QueryRange":               models.APIKeyReadScope,
+}
+
+// The RPCs a read-only session (see `Impersonate`) is allowed to call. Any RPC
+// which is missing from this table is denied.
+var readOnlyMethods = map[string]bool{
+	"/proto.Mothership/SelectBulkTimeSeriesData": true,
+	"/proto.Mothership/StreamTimeSeriesData":     true,
+	"/proto.Mothership/SelectSeries":             true,
+	"/proto.Mothership/ListMetrics":              true,
+	"/proto.Mothership/ListLabelNames":           true,
+	"/proto.Mothership/ListLabelValues":          true,
+	"/proto.Mothership/Query":                    true,
+	"/proto.Mothership/QueryRange":               true,
+	"/proto.Mothership/GetTenant":                true,
+	"/proto.Mothership/ListUsers":                true,
+	"/proto.Mothership/ListInvitations":          true,
+	"/proto.Mothership/ListAPIKeys":              true,
+	"/proto.Mothership/Logout":                   true,
+}
+
+// The highest permission granted to each user role.
+var rolePermissions = map[int8]permission{
+	models.UserRootRoleId:        permissionRoot,
+	models.UserTenantAdminRoleId: permissionManage,
+	models.UserTenantPlainRoleId: permissionRead,
+}
+
+// Root users can act on behalf of another tenant by sending the tenant id in
+// this metadata key.
+const tenantIdMetadataKey = "x-tenant-id"
+
+// isPublicMethod function returns true if the RPC can be called without being
+// authenticated.
+func isPublicMethod(method string) bool {
+	required, ok := methodPermissions[method]
+	return ok && required == permissionPublic
+}
+
+// checkPermission function returns an error if the user's role does not grant
+// the permission required by the RPC.
+func checkPermission(user *models.User, method string) error {
+	required, ok := methodPermissions[method]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "Method %v is not allowed", method)
+	}
+	if rolePermissions[user.RoleId] < required {
+		return status.Errorf(codes.PermissionDenied, "You do not have permission to call %v", method)
+	}
+	return nil
+}
+
+// actAsTenant function returns a copy of the root user which belongs to the
+// tenant requested in the metadata. Any other user requesting a tenant other
+// than their own is denied.
+func (s *Controller) actAsTenant(ctx context.Context, user *models.User) (*models.User, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(tenantIdMetadataKey)
+	if len(values) == 0 {
+		return user, nil
+	}
+
+	tenantId, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Tenant id is malformed")
+	}
+	if tenantId == user.TenantId {
+		return user, nil
+	}
+	if user.RoleId != models.UserRootRoleId {
+		return nil, status.Errorf(codes.PermissionDenied, "You do not have permission to access tenant #%v", tenantId)
+	}
+
+	doesExist, err := s.tenantRepo.CheckIfExistsById(ctx, tenantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if !doesExist {
+		return nil, status.Errorf(codes.NotFound, "Tenant #%v does not exist", tenantId)
+	}
+
+	u := *user
+	u.TenantId = tenantId
+	return &u, nil
+}
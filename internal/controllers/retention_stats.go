@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// retentionStats counts what the retention janitor removed from every tenant
+// since the server started.
+type retentionStats struct {
+	mu      sync.Mutex
+	tenants map[uint64]*tenantRetention
+}
+
+// tenantRetention is what the retention janitor removed from a tenant.
+type tenantRetention struct {
+	TenantId          uint64
+	RemovedPartitions int64
+	ReclaimedBytes    int64
+	LastRunTime       time.Time
+}
+
+func newRetentionStats() *retentionStats {
+	return &retentionStats{
+		tenants: make(map[uint64]*tenantRetention),
+	}
+}
+
+// Record counts the partitions and bytes removed from the tenant by a run of
+// the retention janitor.
+func (st *retentionStats) Record(tenantId uint64, partitions int64, bytes int64) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	r, ok := st.tenants[tenantId]
+	if !ok {
+		r = &tenantRetention{TenantId: tenantId}
+		st.tenants[tenantId] = r
+	}
+	r.RemovedPartitions += partitions
+	r.ReclaimedBytes += bytes
+	r.LastRunTime = time.Now()
+}
+
+// Get returns a copy of what was removed from the tenant.
+func (st *retentionStats) Get(tenantId uint64) tenantRetention {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if r, ok := st.tenants[tenantId]; ok {
+		return *r
+	}
+	return tenantRetention{TenantId: tenantId}
+}
+
+// Snapshot returns what was removed from every tenant, ordered by tenant id.
+func (st *retentionStats) Snapshot() []tenantRetention {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	arr := make([]tenantRetention, 0, len(st.tenants))
+	for _, r := range st.tenants {
+		arr = append(arr, *r)
+	}
+
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].TenantId < arr[j].TenantId
+	})
+	return arr
+}
+
+// Delete forgets what was removed from the tenant.
+func (st *retentionStats) Delete(tenantId uint64) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.tenants, tenantId)
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+// tenantStateCache remembers the state of every tenant for a short while so
+// we do not need to hit the database on every request to find out if the
+// tenant was suspended.
+type tenantStateCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	states map[uint64]*cachedTenantState
+}
+
+type cachedTenantState struct {
+	state      int8
+	expiryTime time.Time
+}
+
+func newTenantStateCache(ttl time.Duration) *tenantStateCache {
+	return &tenantStateCache{
+		ttl:    ttl,
+		states: make(map[uint64]*cachedTenantState),
+	}
+}
+
+// Get returns the state of the tenant or false if it is not cached.
+func (c *tenantStateCache) Get(tenantId uint64) (int8, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.states[tenantId]
+	if !ok {
+		return 0, false
+	}
+	if time.Now().After(item.expiryTime) {
+		delete(c.states, tenantId)
+		return 0, false
+	}
+	return item.state, true
+}
+
+// Set caches the state of the tenant.
+func (c *tenantStateCache) Set(tenantId uint64, state int8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.states[tenantId] = &cachedTenantState{state: state, expiryTime: time.Now().Add(c.ttl)}
+}
+
+// Delete forgets the state of the tenant.
+func (c *tenantStateCache) Delete(tenantId uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.states, tenantId)
+}
@@ -0,0 +1,224 @@
+package lineprotocol
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag is a tag of a point, which identifies the series along with the
+// measurement.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Field is a numeric field of a point. Booleans are converted to 1 and 0.
+type Field struct {
+	Key   string
+	Value float64
+}
+
+// Point is a parsed line. The time is zero if the line has no timestamp.
+type Point struct {
+	Measurement string
+	Tags        []Tag
+	Fields      []Field
+	Time        time.Time
+}
+
+// ParsePrecision returns the unit of the timestamps for the precision used by
+// the InfluxDB 1.x (`n`, `u`, `ms`, `s`, `m` and `h`) and 2.x (`ns`, `us`,
+// `ms` and `s`) write APIs. Nanoseconds are used if there is no precision.
+func ParsePrecision(precision string) (time.Duration, error) {
+	switch precision {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u", "us", "µ":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return 0, fmt.Errorf("precision %q is not supported", precision)
+}
+
+// IsBlank returns true if the line has nothing to parse: it is empty or a
+// comment.
+func IsBlank(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "#")
+}
+
+// ParseLine parses a line of the InfluxDB line protocol, for example
+// `weather,location=us-midwest temperature=82,humidity=71i 1465839830100400200`
+// (see https://docs.influxdata.com/influxdb/v1.8/write_protocols/line_protocol_reference/).
+// The timestamp of the line, if it has one, is in the precision. String
+// fields are skipped as only numbers can be saved; a line without any numeric
+// field is an error.
+func ParseLine(line string, precision time.Duration) (*Point, error) {
+	line = strings.TrimSpace(line)
+
+	// The line is split into the key (the measurement and its tags), the
+	// fields and the optional timestamp by the spaces which are not escaped
+	// or within a string field.
+	keyEnd := indexUnescaped(line, ' ', false)
+	if keyEnd < 0 {
+		return nil, errors.New("missing fields")
+	}
+	key := line[:keyEnd]
+	rest := strings.TrimLeft(line[keyEnd:], " ")
+	fieldsEnd := indexUnescaped(rest, ' ', true)
+	if fieldsEnd < 0 {
+		fieldsEnd = len(rest)
+	}
+	fields := rest[:fieldsEnd]
+	timestamp := strings.TrimSpace(rest[fieldsEnd:])
+
+	p := &Point{Tags: []Tag{}, Fields: []Field{}}
+
+	parts := splitUnescaped(key, ',', false)
+	p.Measurement = unescape(parts[0])
+	if p.Measurement == "" {
+		return nil, errors.New("missing measurement")
+	}
+	for _, part := range parts[1:] {
+		k, v, err := splitPair(part, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag %q: %v", part, err)
+		}
+		p.Tags = append(p.Tags, Tag{Key: unescape(k), Value: unescape(v)})
+	}
+
+	if fields == "" {
+		return nil, errors.New("missing fields")
+	}
+	for _, part := range splitUnescaped(fields, ',', true) {
+		k, v, err := splitPair(part, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field %q: %v", part, err)
+		}
+		if strings.HasPrefix(v, `"`) {
+			if len(v) < 2 || !strings.HasSuffix(v, `"`) {
+				return nil, fmt.Errorf("invalid field %q: unterminated string", part)
+			}
+			continue
+		}
+		value, err := parseFieldValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field %q: %v", part, err)
+		}
+		p.Fields = append(p.Fields, Field{Key: unescape(k), Value: value})
+	}
+	if len(p.Fields) == 0 {
+		return nil, errors.New("no numeric fields")
+	}
+
+	if timestamp != "" {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+		if ts > math.MaxInt64/int64(precision) || ts < math.MinInt64/int64(precision) {
+			return nil, fmt.Errorf("timestamp %q is out of range", timestamp)
+		}
+		p.Time = time.Unix(0, ts*int64(precision)).UTC()
+	}
+	return p, nil
+}
+
+// Utility function which parses the value of a field: an integer (`12i`),
+// an unsigned integer (`12u`), a boolean or a float.
+func parseFieldValue(v string) (float64, error) {
+	switch v {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, nil
+	}
+	if strings.HasSuffix(v, "i") {
+		n, err := strconv.ParseInt(strings.TrimSuffix(v, "i"), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer %q", v)
+		}
+		return float64(n), nil
+	}
+	if strings.HasSuffix(v, "u") {
+		n, err := strconv.ParseUint(strings.TrimSuffix(v, "u"), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid unsigned integer %q", v)
+		}
+		return float64(n), nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid number %q", v)
+	}
+	return f, nil
+}
+
+// Utility function which splits the key and value of a tag or field at the
+// first `=` which is not escaped.
+func splitPair(s string, quotes bool) (string, string, error) {
+	i := indexUnescaped(s, '=', quotes)
+	if i < 0 {
+		return "", "", errors.New("missing value")
+	}
+	k, v := s[:i], s[i+1:]
+	if k == "" {
+		return "", "", errors.New("missing key")
+	}
+	if v == "" {
+		return "", "", errors.New("missing value")
+	}
+	return k, v, nil
+}
+
+// Utility function which returns the index of the first separator which is
+// neither escaped with a backslash nor, if `quotes`, within double quotes.
+// Returns -1 if there is none.
+func indexUnescaped(s string, sep byte, quotes bool) int {
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quotes && s[i] == '"':
+			inQuotes = !inQuotes
+		case s[i] == sep && !inQuotes:
+			return i
+		}
+	}
+	return -1
+}
+
+// Utility function which splits the string at every separator which is
+// neither escaped nor, if `quotes`, within double quotes.
+func splitUnescaped(s string, sep byte, quotes bool) []string {
+	parts := []string{}
+	for {
+		i := indexUnescaped(s, sep, quotes)
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i])
+		s = s[i+1:]
+	}
+}
+
+// Utility function which removes the backslashes escaping commas, equal
+// signs and spaces.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\,`, `,`, `\=`, `=`, `\ `, ` `).Replace(s)
+}
@@ -0,0 +1,216 @@
+package lineprotocol
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		precision time.Duration
+		want      *Point
+	}{
+		{
+			name:      "tags, fields and timestamp",
+			line:      "weather,location=us-midwest,season=summer temperature=82,humidity=71 1465839830100400200",
+			precision: time.Nanosecond,
+			want: &Point{
+				Measurement: "weather",
+				Tags:        []Tag{{Key: "location", Value: "us-midwest"}, {Key: "season", Value: "summer"}},
+				Fields:      []Field{{Key: "temperature", Value: 82}, {Key: "humidity", Value: 71}},
+				Time:        time.Unix(0, 1465839830100400200).UTC(),
+			},
+		},
+		{
+			name: "no tags or timestamp",
+			line: "weather temperature=82",
+			want: &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}},
+		},
+		{
+			name: "surrounding and repeated spaces",
+			line: "  weather,location=us   temperature=82   1  ",
+			want: &Point{
+				Measurement: "weather",
+				Tags:        []Tag{{Key: "location", Value: "us"}},
+				Fields:      []Field{{Key: "temperature", Value: 82}},
+				Time:        time.Unix(0, 1).UTC(),
+			},
+		},
+
+		// Escaping.
+		{
+			name: "escaped measurement",
+			line: `wea\ ther\,now temperature=82`,
+			want: &Point{Measurement: "wea ther,now", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}},
+		},
+		{
+			name: "escaped tag key and value",
+			line: `weather,loc\=ation=us\ mid\,west temperature=82`,
+			want: &Point{
+				Measurement: "weather",
+				Tags:        []Tag{{Key: "loc=ation", Value: "us mid,west"}},
+				Fields:      []Field{{Key: "temperature", Value: 82}},
+			},
+		},
+		{
+			name: "escaped field key",
+			line: `weather temp\ erature\=c=82`,
+			want: &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temp erature=c", Value: 82}}},
+		},
+
+		// String fields are skipped, even with separators in them.
+		{
+			name: "quoted strings",
+			line: `weather description="hot, humid day",temperature=82,note="a=b \"quoted\"" 10`,
+			want: &Point{
+				Measurement: "weather",
+				Tags:        []Tag{},
+				Fields:      []Field{{Key: "temperature", Value: 82}},
+				Time:        time.Unix(0, 10).UTC(),
+			},
+		},
+
+		// Field types.
+		{
+			name: "integers and unsigned integers",
+			line: "disk free=12i,used=-3i,total=15u",
+			want: &Point{Measurement: "disk", Tags: []Tag{}, Fields: []Field{{Key: "free", Value: 12}, {Key: "used", Value: -3}, {Key: "total", Value: 15}}},
+		},
+		{
+			name: "floats",
+			line: "disk a=1.5,b=-2e3,c=.5",
+			want: &Point{Measurement: "disk", Tags: []Tag{}, Fields: []Field{{Key: "a", Value: 1.5}, {Key: "b", Value: -2000}, {Key: "c", Value: 0.5}}},
+		},
+		{
+			name: "booleans",
+			line: "switch a=t,b=T,c=true,d=True,e=TRUE,f=f,g=F,h=false,i=False,j=FALSE",
+			want: &Point{Measurement: "switch", Tags: []Tag{}, Fields: []Field{
+				{Key: "a", Value: 1}, {Key: "b", Value: 1}, {Key: "c", Value: 1}, {Key: "d", Value: 1}, {Key: "e", Value: 1},
+				{Key: "f", Value: 0}, {Key: "g", Value: 0}, {Key: "h", Value: 0}, {Key: "i", Value: 0}, {Key: "j", Value: 0},
+			}},
+		},
+
+		// Timestamp precision.
+		{
+			name:      "seconds",
+			line:      "weather temperature=82 1465839830",
+			precision: time.Second,
+			want:      &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}, Time: time.Unix(1465839830, 0).UTC()},
+		},
+		{
+			name:      "milliseconds",
+			line:      "weather temperature=82 1465839830100",
+			precision: time.Millisecond,
+			want:      &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}, Time: time.Unix(1465839830, 100000000).UTC()},
+		},
+		{
+			name:      "microseconds",
+			line:      "weather temperature=82 1465839830100400",
+			precision: time.Microsecond,
+			want:      &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}, Time: time.Unix(1465839830, 100400000).UTC()},
+		},
+		{
+			name:      "hours",
+			line:      "weather temperature=82 2",
+			precision: time.Hour,
+			want:      &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}, Time: time.Unix(7200, 0).UTC()},
+		},
+		{
+			name:      "negative",
+			line:      "weather temperature=82 -1",
+			precision: time.Second,
+			want:      &Point{Measurement: "weather", Tags: []Tag{}, Fields: []Field{{Key: "temperature", Value: 82}}, Time: time.Unix(-1, 0).UTC()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precision := tt.precision
+			if precision == 0 {
+				precision = time.Nanosecond
+			}
+			got, err := ParseLine(tt.line, precision)
+			if err != nil {
+				t.Fatalf("ParseLine(%q) failed: %v", tt.line, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	tests := []struct {
+		line      string
+		precision time.Duration
+		want      string
+	}{
+		{line: "weather", want: "missing fields"},
+		{line: "weather ", want: "missing fields"},
+		{line: ",location=us temperature=82", want: "missing measurement"},
+		{line: "weather,location temperature=82", want: "invalid tag"},
+		{line: "weather,=us temperature=82", want: "invalid tag"},
+		{line: "weather,location= temperature=82", want: "invalid tag"},
+		{line: "weather temperature", want: "invalid field"},
+		{line: "weather temperature=", want: "invalid field"},
+		{line: "weather =82", want: "invalid field"},
+		{line: "weather temperature=hot", want: "invalid number"},
+		{line: "weather temperature=NaN", want: "invalid number"},
+		{line: "weather temperature=12.5i", want: "invalid integer"},
+		{line: "weather temperature=-1u", want: "invalid unsigned integer"},
+		{line: `weather description="hot`, want: "unterminated string"},
+		{line: `weather description="hot"`, want: "no numeric fields"},
+		{line: "weather temperature=82 yesterday", want: "invalid timestamp"},
+		{line: "weather temperature=82 1.5", want: "invalid timestamp"},
+		{line: "weather temperature=82 9223372036854775807", precision: time.Second, want: "out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			precision := tt.precision
+			if precision == 0 {
+				precision = time.Nanosecond
+			}
+			_, err := ParseLine(tt.line, precision)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseLine(%q) failed with %v, want it to contain %q", tt.line, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrecision(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":   time.Nanosecond,
+		"n":  time.Nanosecond,
+		"ns": time.Nanosecond,
+		"u":  time.Microsecond,
+		"us": time.Microsecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+	}
+	for precision, want := range tests {
+		if got, err := ParsePrecision(precision); err != nil || got != want {
+			t.Errorf("ParsePrecision(%q) = %v, %v, want %v", precision, got, err, want)
+		}
+	}
+	if _, err := ParsePrecision("d"); err == nil {
+		t.Errorf("ParsePrecision(%q) did not fail", "d")
+	}
+}
+
+func TestIsBlank(t *testing.T) {
+	for _, line := range []string{"", "   ", "\t", "# a comment", "  # indented comment"} {
+		if !IsBlank(line) {
+			t.Errorf("IsBlank(%q) = false, want true", line)
+		}
+	}
+	if IsBlank("weather temperature=82") {
+		t.Errorf("IsBlank of a point = true, want false")
+	}
+}
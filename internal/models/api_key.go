@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	APIKeyActiveState  int8 = 1
+	APIKeyRevokedState int8 = 0
+)
+
+const (
+	APIKeyIngestScope = "ingest"
+	APIKeyReadScope   = "read"
+)
+
+// APIKeyScopes are all the scopes an API key can be granted.
+var APIKeyScopes = []string{APIKeyIngestScope, APIKeyReadScope}
+
+type APIKey struct {
+	Id           uint64     `json:"id"`
+	Uuid         string     `json:"uuid"`
+	TenantId     uint64     `json:"tenant_id"`
+	UserId       uint64     `json:"user_id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	KeyHash      string     `json:"key_hash"`
+	Scopes       []string   `json:"scopes"`
+	State        int8       `json:"state"`
+	ExpiryTime   *time.Time `json:"expiry_time"`
+	LastUsedTime *time.Time `json:"last_used_time"`
+	CreatedTime  time.Time  `json:"created_time"`
+	ModifiedTime time.Time  `json:"modified_time"`
+}
+
+// HasScope returns true if the API key was granted the scope.
+func (m *APIKey) HasScope(scope string) bool {
+	for _, s := range m.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the API key has an expiry time which has passed.
+func (m *APIKey) IsExpired() bool {
+	return m.ExpiryTime != nil && time.Now().After(*m.ExpiryTime)
+}
+
+type APIKeyRepository interface {
+	Insert(ctx context.Context, m *APIKey) error
+	UpdateById(ctx context.Context, m *APIKey) error
+	UpdateLastUsedTimeById(ctx context.Context, id uint64, t time.Time) error
+	GetByUuid(ctx context.Context, uuid string) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	ListByTenantId(ctx context.Context, tenantId uint64) ([]*APIKey, error)
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	InvitationPendingState  int8 = 1
+	InvitationAcceptedState int8 = 2
+	InvitationRevokedState  int8 = 0
+)
+
+type Invitation struct {
+	Id              uint64    `json:"id"`
+	Uuid            string    `json:"uuid"`
+	TenantId        uint64    `json:"tenant_id"`
+	InvitedByUserId uint64    `json:"invited_by_user_id"`
+	Email           string    `json:"email"`
+	RoleId          int8      `json:"role_id"`
+	TokenHash       string    `json:"token_hash"`
+	State           int8      `json:"state"`
+	ExpiryTime      time.Time `json:"expiry_time"`
+	CreatedTime     time.Time `json:"created_time"`
+	ModifiedTime    time.Time `json:"modified_time"`
+}
+
+// IsExpired returns true if the invitation can no longer be accepted.
+func (m *Invitation) IsExpired() bool {
+	return time.Now().After(m.ExpiryTime)
+}
+
+type InvitationRepository interface {
+	Insert(ctx context.Context, m *Invitation) error
+	UpdateById(ctx context.Context, m *Invitation) error
+	GetByUuid(ctx context.Context, uuid string) (*Invitation, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Invitation, error)
+	ListByTenantId(ctx context.Context, tenantId uint64) ([]*Invitation, error)
+	// RevokePendingByTenantIdAndEmail revokes every pending invitation sent
+	// to the email by the tenant.
+	RevokePendingByTenantIdAndEmail(ctx context.Context, tenantId uint64, email string) error
+}
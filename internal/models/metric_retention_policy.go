@@ -0,0 +1,23 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// MetricRetentionPolicy overrides how long the data points of a metric are
+// kept instead of the retention of the tenant. The retention is saved to the
+// database in seconds.
+type MetricRetentionPolicy struct {
+	TenantId     uint64        `json:"tenant_id"`
+	Metric       string        `json:"metric"`
+	Retention    time.Duration `json:"retention"`
+	CreatedTime  time.Time     `json:"created_time"`
+	ModifiedTime time.Time     `json:"modified_time"`
+}
+
+type MetricRetentionPolicyRepository interface {
+	InsertOrUpdateByTenantIdAndMetric(ctx context.Context, m *MetricRetentionPolicy) error
+	DeleteByTenantIdAndMetric(ctx context.Context, tenantId uint64, metric string) error
+	ListByTenantId(ctx context.Context, tenantId uint64) ([]*MetricRetentionPolicy, error)
+}
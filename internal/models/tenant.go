@@ -16,18 +16,24 @@ type Tenant struct {
 	Name         string    `json:"name"`
 	State        int8      `json:"state"`
 	Timezone     string    `json:"timestamp"`
+	IsRoot       bool      `json:"is_root"`
 	CreatedTime  time.Time `json:"created_time"`
 	ModifiedTime time.Time `json:"modified_time"`
 }
 
 type TenantRepository interface {
 	Insert(ctx context.Context, u *Tenant) error
+	InsertWithUser(ctx context.Context, t *Tenant, u *User) error
 	UpdateById(ctx context.Context, u *Tenant) error
+	UpdateStateById(ctx context.Context, id uint64, state int8) error
+	DeleteById(ctx context.Context, id uint64) error
 	GetById(ctx context.Context, id uint64) (*Tenant, error)
 	GetByUuid(ctx context.Context, uuid string) (*Tenant, error)
+	GetRoot(ctx context.Context) (*Tenant, error)
 	CheckIfExistsById(ctx context.Context, id uint64) (bool, error)
 	CheckIfExistsByName(ctx context.Context, name string) (bool, error)
 	InsertOrUpdateById(ctx context.Context, u *Tenant) error
 	ListAllUuids(ctx context.Context) ([]string, error)
 	ListAllIds(ctx context.Context) ([]uint64, error)
+	ListAll(ctx context.Context) ([]*Tenant, error)
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// TenantDeletion is the audit record we keep of every tenant we deleted.
+type TenantDeletion struct {
+	Id           uint64    `json:"id"`
+	TenantId     uint64    `json:"tenant_id"`
+	TenantUuid   string    `json:"tenant_uuid"`
+	TenantName   string    `json:"tenant_name"`
+	RequestedBy  string    `json:"requested_by"`
+	UsersCount   int       `json:"users_count"`
+	APIKeysCount int       `json:"api_keys_count"`
+	DataSize     int64     `json:"data_size"`
+	CreatedTime  time.Time `json:"created_time"`
+}
+
+type TenantDeletionRepository interface {
+	Insert(ctx context.Context, m *TenantDeletion) error
+	ListAll(ctx context.Context) ([]*TenantDeletion, error)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	SecondsPrecision      = "s"
+	MillisecondsPrecision = "ms"
+	MicrosecondsPrecision = "us"
+	NanosecondsPrecision  = "ns"
+)
+
+// TimestampPrecisions are all the precisions a tenant can store their
+// timestamps in.
+var TimestampPrecisions = []string{SecondsPrecision, MillisecondsPrecision, MicrosecondsPrecision, NanosecondsPrecision}
+
+// TenantStorageConfig are the settings used to open the time-series storage of
+// a tenant. The durations are saved to the database in seconds.
+type TenantStorageConfig struct {
+	TenantId           uint64        `json:"tenant_id"`
+	PartitionDuration  time.Duration `json:"partition_duration"`
+	TimestampPrecision string        `json:"timestamp_precision"`
+	WriteTimeout       time.Duration `json:"write_timeout"`
+	Retention          time.Duration `json:"retention"`
+	CreatedTime        time.Time     `json:"created_time"`
+	ModifiedTime       time.Time     `json:"modified_time"`
+}
+
+// DefaultTenantStorageConfig returns the settings used by tenants which never
+// changed their storage settings.
+func DefaultTenantStorageConfig(tenantId uint64) *TenantStorageConfig {
+	return &TenantStorageConfig{
+		TenantId:           tenantId,
+		PartitionDuration:  time.Hour * 24,
+		TimestampPrecision: SecondsPrecision,
+		WriteTimeout:       time.Second * 60,
+		Retention:          time.Hour * 24 * 14,
+		CreatedTime:        time.Now(),
+		ModifiedTime:       time.Now(),
+	}
+}
+
+type TenantStorageConfigRepository interface {
+	InsertOrUpdateByTenantId(ctx context.Context, m *TenantStorageConfig) error
+	GetByTenantId(ctx context.Context, tenantId uint64) (*TenantStorageConfig, error)
+}
@@ -14,33 +14,56 @@ var (
 )
 
 type User struct {
-	Id                uint64    `json:"id,omitempty"`
-	Uuid              string    `json:"uuid,omitempty"`
-	TenantId          uint64    `json:"tenant_id,omitempty"`
-	Email             string    `json:"email,omitempty"`
-	FirstName         string    `json:"first_name,omitempty"`
-	LastName          string    `json:"last_name,omitempty"`
-	PasswordAlgorithm string    `json:"password_algorithm,omitempty"`
-	PasswordHash      string    `json:"password_hash,omitempty"`
-	State             int8      `json:"state,omitempty"`
-	RoleId            int8      `json:"role_id,omitempty"`
-	Timezone          string    `json:"timezone,omitempty"`
-	CreatedTime       time.Time `json:"created_time,omitempty"`
-	ModifiedTime      time.Time `json:"modified_time,omitempty"`
-	Salt              string    `json:"salt,omitempty"`
-	WasEmailActivated bool      `json:"was_email_activated,omitempty"`
-	PrAccessCode      string    `json:"pr_access_code,omitempty"`
-	PrExpiryTime      time.Time `json:"pr_expiry_time,omitempty"`
+	Id                          uint64    `json:"id,omitempty"`
+	Uuid                        string    `json:"uuid,omitempty"`
+	TenantId                    uint64    `json:"tenant_id,omitempty"`
+	Email                       string    `json:"email,omitempty"`
+	FirstName                   string    `json:"first_name,omitempty"`
+	LastName                    string    `json:"last_name,omitempty"`
+	PasswordAlgorithm           string    `json:"password_algorithm,omitempty"`
+	PasswordHash                string    `json:"password_hash,omitempty"`
+	State                       int8      `json:"state,omitempty"`
+	RoleId                      int8      `json:"role_id,omitempty"`
+	Timezone                    string    `json:"timezone,omitempty"`
+	CreatedTime                 time.Time `json:"created_time,omitempty"`
+	ModifiedTime                time.Time `json:"modified_time,omitempty"`
+	Salt                        string    `json:"salt,omitempty"`
+	WasEmailActivated           bool      `json:"was_email_activated,omitempty"`
+	PrAccessCode                string    `json:"pr_access_code,omitempty"`
+	PrExpiryTime                time.Time `json:"pr_expiry_time,omitempty"`
+	EmailVerificationCode       string    `json:"email_verification_code,omitempty"`
+	EmailVerificationExpiryTime time.Time `json:"email_verification_expiry_time,omitempty"`
+	TotpSecret                  string    `json:"totp_secret,omitempty"`
+	TotpEnabled                 bool      `json:"totp_enabled,omitempty"`
+	TotpRecoveryCodes           []string  `json:"totp_recovery_codes,omitempty"`
+	TotpLastUsedStep            int64     `json:"totp_last_used_step,omitempty"`
 	// AccessToken       string    `json:"pr_access_code,omitempty"`
 	// RefreshToken      string    `json:"pr_access_code,omitempty"`
 }
 
+// WithoutSecrets returns a copy of the user without the password, the TOTP
+// secret and the codes which prove who the user is, so the copy is safe to
+// keep outside of the database.
+func (u *User) WithoutSecrets() *User {
+	cp := *u
+	cp.PasswordHash = ""
+	cp.Salt = ""
+	cp.PrAccessCode = ""
+	cp.EmailVerificationCode = ""
+	cp.TotpSecret = ""
+	cp.TotpRecoveryCodes = nil
+	return &cp
+}
+
 type UserRepository interface {
 	Insert(ctx context.Context, u *User) error
 	UpdateById(ctx context.Context, u *User) error
 	UpdateByEmail(ctx context.Context, u *User) error
 	GetById(ctx context.Context, id uint64) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetByUuid(ctx context.Context, uuid string) (*User, error)
+	ListByTenantId(ctx context.Context, tenantId uint64) ([]*User, error)
+	CountByTenantId(ctx context.Context, tenantId uint64) (int64, error)
 	CheckIfExistsById(ctx context.Context, id uint64) (bool, error)
 	CheckIfExistsByEmail(ctx context.Context, email string) (bool, error)
 	InsertOrUpdateById(ctx context.Context, u *User) error
@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes the messages to the standard logger.
+type LogNotifier struct{}
+
+func NewLog() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, m *Message) error {
+	log.Printf("Notification - To:%s\tSubject:%s\n%s\n", m.To, m.Subject, m.Body)
+	return nil
+}
+
+// FileNotifier appends the messages to a file.
+type FileNotifier struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewFile(filePath string) *FileNotifier {
+	return &FileNotifier{
+		filePath: filePath,
+	}
+}
+
+func (n *FileNotifier) Send(ctx context.Context, m *Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), m.To, m.Subject, m.Body)
+	return err
+}
@@ -0,0 +1,19 @@
+package notifier
+
+import (
+	"context"
+)
+
+// Message is a notification addressed to a user of the system.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to our users. Use `NewSMTP` to send emails in
+// production or `NewLog`/`NewFile` for local development where the messages
+// get written somewhere the developer can read them.
+type Notifier interface {
+	Send(ctx context.Context, m *Message) error
+}
@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPOptions are the settings of the mail server we send through.
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier sends the messages as plain text emails.
+type SMTPNotifier struct {
+	opts *SMTPOptions
+}
+
+func NewSMTP(opts *SMTPOptions) *SMTPNotifier {
+	return &SMTPNotifier{
+		opts: opts,
+	}
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, m *Message) error {
+	// Protect against header injection.
+	if strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return errors.New("Message recipient or subject contains a line break")
+	}
+
+	addr := net.JoinHostPort(n.opts.Host, strconv.Itoa(n.opts.Port))
+
+	var auth smtp.Auth
+	if n.opts.Username != "" {
+		auth = smtp.PlainAuth("", n.opts.Username, n.opts.Password, n.opts.Host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.opts.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", m.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	fmt.Fprintf(&msg, "\r\n%s\r\n", m.Body)
+
+	// DEVELOPERS NOTE:
+	// `SendMail` will upgrade the connection with STARTTLS if the server
+	// supports it.
+	return smtp.SendMail(addr, auth, n.opts.From, []string{m.To}, msg.Bytes())
+}
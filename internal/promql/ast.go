@@ -0,0 +1,85 @@
+package promql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/tsdb"
+)
+
+// Expr is a node of a parsed query.
+type Expr interface {
+	// Type returns the type of the value the expression evaluates to.
+	Type() ValueType
+}
+
+// NumberLiteral is a number such as `2` or `1.5e3`.
+type NumberLiteral struct {
+	Value float64
+}
+
+// VectorSelector selects the latest value of every matching series, for
+// example `temperature{room="kitchen"}`.
+type VectorSelector struct {
+	Name     string
+	Matchers []*tsdb.Matcher
+	Offset   time.Duration
+}
+
+// MatrixSelector selects the values of every matching series over the range,
+// for example `temperature[5m]`.
+type MatrixSelector struct {
+	Vector *VectorSelector
+	Range  time.Duration
+}
+
+// Call is a function call such as `rate(requests[5m])`.
+type Call struct {
+	Func string
+	Args []Expr
+}
+
+// AggregateExpr combines the samples of a vector into groups, for example
+// `sum by (room) (temperature)`.
+type AggregateExpr struct {
+	Op       string
+	Expr     Expr
+	Grouping []string
+	Without  bool
+}
+
+// BinaryExpr is arithmetic between two expressions, for example `a / b`.
+type BinaryExpr struct {
+	Op  string
+	LHS Expr
+	RHS Expr
+}
+
+// UnaryExpr is a negated expression, for example `-a`.
+type UnaryExpr struct {
+	Expr Expr
+}
+
+func (e *NumberLiteral) Type() ValueType  { return ValueTypeScalar }
+func (e *VectorSelector) Type() ValueType { return ValueTypeVector }
+func (e *MatrixSelector) Type() ValueType { return ValueTypeMatrix }
+func (e *Call) Type() ValueType           { return ValueTypeVector }
+func (e *AggregateExpr) Type() ValueType  { return ValueTypeVector }
+func (e *UnaryExpr) Type() ValueType      { return e.Expr.Type() }
+
+func (e *BinaryExpr) Type() ValueType {
+	if e.LHS.Type() == ValueTypeScalar && e.RHS.Type() == ValueTypeScalar {
+		return ValueTypeScalar
+	}
+	return ValueTypeVector
+}
+
+// ParseError is returned when the query cannot be parsed.
+type ParseError struct {
+	Pos int
+	Err string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse error at char %d: %s", e.Pos+1, e.Err)
+}
@@ -0,0 +1,395 @@
+package promql
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/tsdb"
+)
+
+// LookbackDelta is how far back a vector selector looks for the latest value
+// of a series. Series without a value in this long are left out.
+const LookbackDelta = 5 * time.Minute
+
+// MaxSteps is the most steps a range query may be evaluated at.
+const MaxSteps = 11000
+
+// Queryable is where a query reads its series from.
+type Queryable interface {
+	// Select returns every series matching all the matchers along with their
+	// points between start and end (both inclusive and in Unix nanoseconds).
+	// The labels of the series must include the metric name as `__name__`.
+	Select(matchers []*tsdb.Matcher, start int64, end int64) ([]*Series, error)
+}
+
+// Query evaluates the query at the time. The result is a scalar, a vector or,
+// if the query is a range selector, a matrix.
+func Query(q Queryable, query string, t time.Time) (*Result, error) {
+	expr, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &evaluator{start: t.UnixNano(), end: t.UnixNano(), step: 1}
+	if err := ev.preload(q, expr); err != nil {
+		return nil, err
+	}
+	v, err := ev.eval(expr, ev.start)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case float64:
+		return &Result{Type: ValueTypeScalar, Scalar: Point{T: ev.start, V: v}}, nil
+	case Vector:
+		sortVector(v)
+		return &Result{Type: ValueTypeVector, Vector: v}, nil
+	default:
+		m := v.(Matrix)
+		sortMatrix(m)
+		return &Result{Type: ValueTypeMatrix, Matrix: m}, nil
+	}
+}
+
+// QueryRange evaluates the query at every step between start and end and
+// returns the values of every series as a matrix. A scalar query is returned
+// as a single series without labels.
+func QueryRange(q Queryable, query string, start time.Time, end time.Time, step time.Duration) (*Result, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be greater than 0")
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end must not be before start")
+	}
+	if steps := int64(end.Sub(start)/step) + 1; steps > MaxSteps {
+		return nil, fmt.Errorf("query would be evaluated at %v steps, at most %v are allowed", steps, MaxSteps)
+	}
+
+	expr, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Type() == ValueTypeMatrix {
+		return nil, fmt.Errorf("range queries must return a scalar or an instant vector, got %v", expr.Type())
+	}
+
+	ev := &evaluator{start: start.UnixNano(), end: end.UnixNano(), step: int64(step)}
+	if err := ev.preload(q, expr); err != nil {
+		return nil, err
+	}
+
+	seriesByLabels := map[string]*Series{}
+	for t := ev.start; t <= ev.end; t += ev.step {
+		v, err := ev.eval(expr, t)
+		if err != nil {
+			return nil, err
+		}
+
+		var vector Vector
+		switch v := v.(type) {
+		case float64:
+			vector = Vector{{Labels: Labels{}, Point: Point{T: t, V: v}}}
+		case Vector:
+			vector = v
+		}
+		for _, sample := range vector {
+			key := sample.Labels.String()
+			series, ok := seriesByLabels[key]
+			if !ok {
+				series = &Series{Labels: sample.Labels}
+				seriesByLabels[key] = series
+			}
+			series.Points = append(series.Points, sample.Point)
+		}
+	}
+
+	m := Matrix{}
+	for _, series := range seriesByLabels {
+		m = append(m, series)
+	}
+	sortMatrix(m)
+	return &Result{Type: ValueTypeMatrix, Matrix: m}, nil
+}
+
+// evaluator evaluates an expression at every step between start and end.
+type evaluator struct {
+	start int64
+	end   int64
+	step  int64
+
+	// The series of every selector of the expression, which are selected
+	// once for every step.
+	selected map[*VectorSelector][]*Series
+}
+
+// Utility function which selects the series of every selector of the
+// expression over the whole range of the evaluation.
+func (ev *evaluator) preload(q Queryable, expr Expr) error {
+	ev.selected = map[*VectorSelector][]*Series{}
+
+	var walk func(expr Expr) error
+	walk = func(expr Expr) error {
+		switch e := expr.(type) {
+		case *VectorSelector:
+			return ev.selectSeries(q, e, LookbackDelta)
+		case *MatrixSelector:
+			return ev.selectSeries(q, e.Vector, e.Range)
+		case *Call:
+			for _, arg := range e.Args {
+				if err := walk(arg); err != nil {
+					return err
+				}
+			}
+		case *AggregateExpr:
+			return walk(e.Expr)
+		case *BinaryExpr:
+			if err := walk(e.LHS); err != nil {
+				return err
+			}
+			return walk(e.RHS)
+		case *UnaryExpr:
+			return walk(e.Expr)
+		}
+		return nil
+	}
+	return walk(expr)
+}
+
+func (ev *evaluator) selectSeries(q Queryable, vs *VectorSelector, r time.Duration) error {
+	start := ev.start - int64(vs.Offset) - int64(r)
+	end := ev.end - int64(vs.Offset)
+	series, err := q.Select(vs.Matchers, start, end)
+	if err != nil {
+		return err
+	}
+	ev.selected[vs] = series
+	return nil
+}
+
+// eval evaluates the expression at the time and returns either a float64
+// (scalar), a Vector or a Matrix.
+func (ev *evaluator) eval(expr Expr, t int64) (interface{}, error) {
+	switch e := expr.(type) {
+	case *NumberLiteral:
+		return e.Value, nil
+
+	case *VectorSelector:
+		return ev.evalVectorSelector(e, t), nil
+
+	case *MatrixSelector:
+		return ev.evalMatrixSelector(e, t), nil
+
+	case *Call:
+		ms := e.Args[0].(*MatrixSelector)
+		end := t - int64(ms.Vector.Offset)
+		f := functions[e.Func]
+		res := Vector{}
+		for _, series := range ev.evalMatrixSelector(ms, t) {
+			if v, ok := f(series.Points, end, ms.Range); ok {
+				res = append(res, Sample{Labels: series.Labels.withoutName(), Point: Point{T: t, V: v}})
+			}
+		}
+		return res, checkUniqueLabels(res)
+
+	case *AggregateExpr:
+		v, err := ev.eval(e.Expr, t)
+		if err != nil {
+			return nil, err
+		}
+		return aggregate(e, v.(Vector), t), nil
+
+	case *BinaryExpr:
+		lhs, err := ev.eval(e.LHS, t)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := ev.eval(e.RHS, t)
+		if err != nil {
+			return nil, err
+		}
+		return binaryOp(e.Op, lhs, rhs, t)
+
+	case *UnaryExpr:
+		v, err := ev.eval(e.Expr, t)
+		if err != nil {
+			return nil, err
+		}
+		return binaryOp("*", v, float64(-1), t)
+	}
+	return nil, fmt.Errorf("unexpected expression %T", expr)
+}
+
+// Utility function which returns the latest value of every series of the
+// selector within the lookback delta of the time.
+func (ev *evaluator) evalVectorSelector(vs *VectorSelector, t int64) Vector {
+	end := t - int64(vs.Offset)
+	res := Vector{}
+	for _, series := range ev.selected[vs] {
+		points := pointsBetween(series.Points, end-int64(LookbackDelta), end)
+		if len(points) == 0 {
+			continue
+		}
+		res = append(res, Sample{Labels: series.Labels, Point: Point{T: t, V: points[len(points)-1].V}})
+	}
+	return res
+}
+
+// Utility function which returns the values of every series of the selector
+// within the range ending at the time.
+func (ev *evaluator) evalMatrixSelector(ms *MatrixSelector, t int64) Matrix {
+	end := t - int64(ms.Vector.Offset)
+	res := Matrix{}
+	for _, series := range ev.selected[ms.Vector] {
+		points := pointsBetween(series.Points, end-int64(ms.Range), end)
+		if len(points) == 0 {
+			continue
+		}
+		res = append(res, &Series{Labels: series.Labels, Points: points})
+	}
+	return res
+}
+
+// Utility function which returns the points after start up to and including
+// end. The points must be sorted by time.
+func pointsBetween(points []Point, start int64, end int64) []Point {
+	from := sort.Search(len(points), func(i int) bool { return points[i].T > start })
+	to := sort.Search(len(points), func(i int) bool { return points[i].T > end })
+	return points[from:to]
+}
+
+// Utility function which combines the samples of the vector into a sample for
+// every group.
+func aggregate(e *AggregateExpr, v Vector, t int64) Vector {
+	type group struct {
+		labels Labels
+		value  float64
+		count  int
+	}
+	groups := map[string]*group{}
+	order := []string{}
+
+	for _, sample := range v {
+		labels := sample.Labels.grouping(e.Grouping, e.Without)
+		key := labels.String()
+		g, ok := groups[key]
+		if !ok {
+			groups[key] = &group{labels: labels, value: sample.V, count: 1}
+			order = append(order, key)
+			continue
+		}
+		g.count++
+		switch e.Op {
+		case "sum", "avg":
+			g.value += sample.V
+		case "min":
+			if sample.V < g.value || math.IsNaN(g.value) {
+				g.value = sample.V
+			}
+		case "max":
+			if sample.V > g.value || math.IsNaN(g.value) {
+				g.value = sample.V
+			}
+		}
+	}
+
+	res := Vector{}
+	for _, key := range order {
+		g := groups[key]
+		value := g.value
+		switch e.Op {
+		case "avg":
+			value = g.value / float64(g.count)
+		case "count":
+			value = float64(g.count)
+		}
+		res = append(res, Sample{Labels: g.labels, Point: Point{T: t, V: value}})
+	}
+	return res
+}
+
+// Utility function which applies the arithmetic operator to the operands.
+// Vectors are matched by their labels without the metric name and every
+// label set may only be on each side once.
+func binaryOp(op string, lhs interface{}, rhs interface{}, t int64) (interface{}, error) {
+	l, lok := lhs.(float64)
+	r, rok := rhs.(float64)
+	switch {
+	case lok && rok:
+		return arithmetic(op, l, r), nil
+
+	case rok:
+		res := Vector{}
+		for _, sample := range lhs.(Vector) {
+			res = append(res, Sample{Labels: sample.Labels.withoutName(), Point: Point{T: t, V: arithmetic(op, sample.V, r)}})
+		}
+		return res, checkUniqueLabels(res)
+
+	case lok:
+		res := Vector{}
+		for _, sample := range rhs.(Vector) {
+			res = append(res, Sample{Labels: sample.Labels.withoutName(), Point: Point{T: t, V: arithmetic(op, l, sample.V)}})
+		}
+		return res, checkUniqueLabels(res)
+	}
+
+	rhsByLabels := map[string]Sample{}
+	for _, sample := range rhs.(Vector) {
+		key := sample.Labels.withoutName().String()
+		if _, ok := rhsByLabels[key]; ok {
+			return nil, fmt.Errorf("found duplicate series for the match group %v on the right hand-side of the operation", key)
+		}
+		rhsByLabels[key] = sample
+	}
+
+	res := Vector{}
+	seen := map[string]bool{}
+	for _, sample := range lhs.(Vector) {
+		labels := sample.Labels.withoutName()
+		key := labels.String()
+		other, ok := rhsByLabels[key]
+		if !ok {
+			continue
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("found duplicate series for the match group %v on the left hand-side of the operation", key)
+		}
+		seen[key] = true
+		res = append(res, Sample{Labels: labels, Point: Point{T: t, V: arithmetic(op, sample.V, other.V)}})
+	}
+	return res, nil
+}
+
+func arithmetic(op string, l float64, r float64) float64 {
+	switch op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	case "%":
+		return math.Mod(l, r)
+	case "^":
+		return math.Pow(l, r)
+	}
+	return math.NaN()
+}
+
+// Utility function which returns an error if two samples of the vector have
+// the same labels, which happens when the metric name was dropped.
+func checkUniqueLabels(v Vector) error {
+	seen := map[string]bool{}
+	for _, sample := range v {
+		key := sample.Labels.String()
+		if seen[key] {
+			return fmt.Errorf("vector cannot contain metrics with the same labelset %v", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package promql
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/tsdb"
+)
+
+// memoryQueryable keeps the series in memory for the tests.
+type memoryQueryable struct {
+	series []*Series
+	err    error
+}
+
+func (q *memoryQueryable) Select(matchers []*tsdb.Matcher, start int64, end int64) ([]*Series, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	arr := []*Series{}
+	for _, s := range q.series {
+		matches := true
+		for _, m := range matchers {
+			if !m.Matches(s.Labels.Get(m.Name)) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		points := []Point{}
+		for _, p := range s.Points {
+			if p.T >= start && p.T <= end {
+				points = append(points, p)
+			}
+		}
+		arr = append(arr, &Series{Labels: s.Labels, Points: points})
+	}
+	return arr, nil
+}
+
+// The time the tests evaluate their queries at.
+var testTime = time.Unix(1600000000, 0)
+
+// Utility function which returns the series with the labels, written as
+// name and value pairs, and the values at the offsets from `testTime`.
+func testSeries(labels []string, values map[time.Duration]float64) *Series {
+	arr := []Label{}
+	for i := 0; i < len(labels); i += 2 {
+		arr = append(arr, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	s := &Series{Labels: NewLabels(arr...)}
+	for offset := -time.Hour; offset <= 0; offset += time.Second {
+		if v, ok := values[offset]; ok {
+			s.Points = append(s.Points, Point{T: testTime.Add(offset).UnixNano(), V: v})
+		}
+	}
+	return s
+}
+
+// Utility function which returns the series of a counter going up by one
+// every second with a value every 15 seconds during the last 10 minutes.
+func testCounter(labels ...string) *Series {
+	values := map[time.Duration]float64{}
+	for offset := -10 * time.Minute; offset <= 0; offset += 15 * time.Second {
+		values[offset] = (offset + 10*time.Minute).Seconds()
+	}
+	return testSeries(labels, values)
+}
+
+func newTestQueryable() *memoryQueryable {
+	return &memoryQueryable{series: []*Series{
+		testSeries([]string{"__name__", "temperature", "room", "kitchen", "floor", "1"}, map[time.Duration]float64{-time.Minute: 20, 0: 22}),
+		testSeries([]string{"__name__", "temperature", "room", "garage", "floor", "0"}, map[time.Duration]float64{-30 * time.Second: 10}),
+		// Older than the lookback delta so it is left out of instant vectors.
+		testSeries([]string{"__name__", "temperature", "room", "attic", "floor", "2"}, map[time.Duration]float64{-10 * time.Minute: 30}),
+		testSeries([]string{"__name__", "humidity", "room", "kitchen", "floor", "1"}, map[time.Duration]float64{0: 50}),
+		testSeries([]string{"__name__", "humidity", "room", "garage", "floor", "0"}, map[time.Duration]float64{0: 80}),
+		testCounter("__name__", "requests_total", "job", "api"),
+	}}
+}
+
+// Utility function which returns the value of every sample by its labels.
+func vectorValues(v Vector) map[string]float64 {
+	values := map[string]float64{}
+	for _, sample := range v {
+		values[sample.Labels.String()] = sample.V
+	}
+	return values
+}
+
+func equalValues(got map[string]float64, want map[string]float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for k, v := range want {
+		if math.Abs(got[k]-v) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  map[string]float64
+	}{
+		{query: "temperature", want: map[string]float64{
+			`{__name__="temperature", floor="0", room="garage"}`:  10,
+			`{__name__="temperature", floor="1", room="kitchen"}`: 22,
+		}},
+		{query: `temperature{room="kitchen"}`, want: map[string]float64{
+			`{__name__="temperature", floor="1", room="kitchen"}`: 22,
+		}},
+		{query: `temperature{room=~"gar.*"}`, want: map[string]float64{
+			`{__name__="temperature", floor="0", room="garage"}`: 10,
+		}},
+		{query: "temperature offset 1m", want: map[string]float64{
+			`{__name__="temperature", floor="1", room="kitchen"}`: 20,
+		}},
+		{query: "missing", want: map[string]float64{}},
+
+		// Functions drop the metric name.
+		{query: "rate(requests_total[5m])", want: map[string]float64{`{job="api"}`: 1}},
+		{query: "increase(requests_total[5m])", want: map[string]float64{`{job="api"}`: 300}},
+		{query: "delta(requests_total[5m])", want: map[string]float64{`{job="api"}`: 300}},
+		{query: "irate(requests_total[5m])", want: map[string]float64{`{job="api"}`: 1}},
+		{query: "max_over_time(temperature[5m])", want: map[string]float64{
+			`{floor="0", room="garage"}`:  10,
+			`{floor="1", room="kitchen"}`: 22,
+		}},
+		{query: "avg_over_time(temperature[5m])", want: map[string]float64{
+			`{floor="0", room="garage"}`:  10,
+			`{floor="1", room="kitchen"}`: 21,
+		}},
+		{query: "count_over_time(requests_total[1m])", want: map[string]float64{`{job="api"}`: 4}},
+
+		// Aggregations.
+		{query: "sum(temperature)", want: map[string]float64{`{}`: 32}},
+		{query: "avg by (floor) (temperature)", want: map[string]float64{`{floor="0"}`: 10, `{floor="1"}`: 22}},
+		{query: "sum(temperature) without (room)", want: map[string]float64{`{floor="0"}`: 10, `{floor="1"}`: 22}},
+		{query: "count(temperature)", want: map[string]float64{`{}`: 2}},
+		{query: "min(temperature)", want: map[string]float64{`{}`: 10}},
+		{query: "max(temperature)", want: map[string]float64{`{}`: 22}},
+
+		// Arithmetic matches the series by their labels without the name.
+		{query: "temperature * 2", want: map[string]float64{
+			`{floor="0", room="garage"}`:  20,
+			`{floor="1", room="kitchen"}`: 44,
+		}},
+		{query: "100 - humidity", want: map[string]float64{
+			`{floor="0", room="garage"}`:  20,
+			`{floor="1", room="kitchen"}`: 50,
+		}},
+		{query: "humidity / temperature", want: map[string]float64{
+			`{floor="0", room="garage"}`:  8,
+			`{floor="1", room="kitchen"}`: 50.0 / 22,
+		}},
+		{query: `humidity - temperature{room="kitchen"}`, want: map[string]float64{
+			`{floor="1", room="kitchen"}`: 28,
+		}},
+		{query: "-temperature", want: map[string]float64{
+			`{floor="0", room="garage"}`:  -10,
+			`{floor="1", room="kitchen"}`: -22,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res, err := Query(newTestQueryable(), tt.query, testTime)
+			if err != nil {
+				t.Fatalf("Query(%q) failed: %v", tt.query, err)
+			}
+			if res.Type != ValueTypeVector {
+				t.Fatalf("Query(%q) returned a %v, want a vector", tt.query, res.Type)
+			}
+			if got := vectorValues(res.Vector); !equalValues(got, tt.want) {
+				t.Errorf("Query(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for _, sample := range res.Vector {
+				if sample.T != testTime.UnixNano() {
+					t.Errorf("Query(%q) returned a sample at %v, want %v", tt.query, sample.T, testTime.UnixNano())
+				}
+			}
+		})
+	}
+}
+
+func TestQueryScalar(t *testing.T) {
+	tests := []struct {
+		query string
+		want  float64
+	}{
+		{query: "1 + 2 * 3", want: 7},
+		{query: "2 ^ 3 ^ 2", want: 512},
+		{query: "-2 ^ 2", want: -4},
+		{query: "7 % 4", want: 3},
+		{query: "(1 + 2) / 4", want: 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res, err := Query(newTestQueryable(), tt.query, testTime)
+			if err != nil {
+				t.Fatalf("Query(%q) failed: %v", tt.query, err)
+			}
+			if res.Type != ValueTypeScalar || res.Scalar.V != tt.want || res.Scalar.T != testTime.UnixNano() {
+				t.Errorf("Query(%q) = %v %v, want scalar %v", tt.query, res.Type, res.Scalar, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryMatrix(t *testing.T) {
+	res, err := Query(newTestQueryable(), "temperature[2m]", testTime)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if res.Type != ValueTypeMatrix || len(res.Matrix) != 2 {
+		t.Fatalf("Query = %v with %v series, want a matrix with 2 series", res.Type, len(res.Matrix))
+	}
+
+	// The series are sorted by their labels.
+	kitchen := res.Matrix[1]
+	if kitchen.Labels.Get("room") != "kitchen" {
+		t.Fatalf("second series is %v, want the kitchen", kitchen.Labels)
+	}
+	want := []Point{
+		{T: testTime.Add(-time.Minute).UnixNano(), V: 20},
+		{T: testTime.UnixNano(), V: 22},
+	}
+	if len(kitchen.Points) != len(want) || kitchen.Points[0] != want[0] || kitchen.Points[1] != want[1] {
+		t.Errorf("kitchen points = %v, want %v", kitchen.Points, want)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "sum(", want: "parse error"},
+		{query: `{room="kitchen"} * 2`, want: "vector cannot contain metrics with the same labelset"},
+		{query: `temperature + {room=~"kitchen|garage"}`, want: "duplicate series for the match group"},
+		{query: `{room=~"kitchen|garage"} + temperature`, want: "duplicate series for the match group"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Query(newTestQueryable(), tt.query, testTime)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Query(%q) failed with %v, want it to contain %q", tt.query, err, tt.want)
+			}
+		})
+	}
+
+	selectErr := errors.New("storage is closed")
+	if _, err := Query(&memoryQueryable{err: selectErr}, "temperature", testTime); !errors.Is(err, selectErr) {
+		t.Errorf("Query failed with %v, want %v", err, selectErr)
+	}
+}
+
+func TestQueryRange(t *testing.T) {
+	start := testTime.Add(-2 * time.Minute)
+	tests := []struct {
+		query string
+		want  map[string][]float64
+	}{
+		{query: "requests_total", want: map[string][]float64{
+			`{__name__="requests_total", job="api"}`: {480, 540, 600},
+		}},
+		{query: "rate(requests_total[5m])", want: map[string][]float64{
+			`{job="api"}`: {1, 1, 1},
+		}},
+		{query: "1 + 1", want: map[string][]float64{
+			`{}`: {2, 2, 2},
+		}},
+		// The garage has no value before the last step.
+		{query: `temperature{room="garage"}`, want: map[string][]float64{
+			`{__name__="temperature", floor="0", room="garage"}`: {10},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res, err := QueryRange(newTestQueryable(), tt.query, start, testTime, time.Minute)
+			if err != nil {
+				t.Fatalf("QueryRange(%q) failed: %v", tt.query, err)
+			}
+			if res.Type != ValueTypeMatrix || len(res.Matrix) != len(tt.want) {
+				t.Fatalf("QueryRange(%q) = %v with %v series, want a matrix with %v series", tt.query, res.Type, len(res.Matrix), len(tt.want))
+			}
+			for _, series := range res.Matrix {
+				want, ok := tt.want[series.Labels.String()]
+				if !ok || len(series.Points) != len(want) {
+					t.Fatalf("QueryRange(%q) returned %v with %v", tt.query, series.Labels, series.Points)
+				}
+				// The points are at the last steps.
+				offset := 3 - len(want)
+				for i, p := range series.Points {
+					wantT := start.Add(time.Duration(offset+i) * time.Minute).UnixNano()
+					if p.T != wantT || math.Abs(p.V-want[i]) > 1e-9 {
+						t.Errorf("QueryRange(%q) point %v = %v, want %v at %v", tt.query, i, p, want[i], wantT)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQueryRangeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		start time.Time
+		end   time.Time
+		step  time.Duration
+		want  string
+	}{
+		{name: "no step", query: "temperature", start: testTime, end: testTime, step: 0, want: "step must be greater than 0"},
+		{name: "end before start", query: "temperature", start: testTime, end: testTime.Add(-time.Second), step: time.Second, want: "end must not be before start"},
+		{name: "too many steps", query: "temperature", start: testTime.Add(-MaxSteps * time.Second), end: testTime, step: time.Second, want: "at most"},
+		{name: "range selector", query: "temperature[5m]", start: testTime, end: testTime, step: time.Second, want: "range queries must return a scalar or an instant vector"},
+		{name: "parse error", query: "temperature[", start: testTime, end: testTime, step: time.Second, want: "parse error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := QueryRange(newTestQueryable(), tt.query, tt.start, tt.end, tt.step)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("QueryRange(%q) failed with %v, want it to contain %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package promql
+
+import (
+	"math"
+	"time"
+)
+
+// rangeFunction calculates the value of a series from its points within the
+// range which ends at `end`. Returns false if there is no value.
+type rangeFunction func(points []Point, end int64, r time.Duration) (float64, bool)
+
+// The functions which can be called in a query. Each takes a range of every
+// series, for example `rate(requests[5m])`.
+var functions = map[string]rangeFunction{
+	"rate": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		return extrapolatedRate(points, end, r, true, true)
+	},
+	"increase": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		return extrapolatedRate(points, end, r, true, false)
+	},
+	"delta": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		return extrapolatedRate(points, end, r, false, false)
+	},
+	"irate": instantRate,
+	"avg_over_time": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		var sum float64
+		for _, p := range points {
+			sum += p.V
+		}
+		return sum / float64(len(points)), len(points) > 0
+	},
+	"sum_over_time": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		var sum float64
+		for _, p := range points {
+			sum += p.V
+		}
+		return sum, len(points) > 0
+	},
+	"min_over_time": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		if len(points) == 0 {
+			return 0, false
+		}
+		m := points[0].V
+		for _, p := range points[1:] {
+			m = math.Min(m, p.V)
+		}
+		return m, true
+	},
+	"max_over_time": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		if len(points) == 0 {
+			return 0, false
+		}
+		m := points[0].V
+		for _, p := range points[1:] {
+			m = math.Max(m, p.V)
+		}
+		return m, true
+	},
+	"count_over_time": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		return float64(len(points)), len(points) > 0
+	},
+	"last_over_time": func(points []Point, end int64, r time.Duration) (float64, bool) {
+		if len(points) == 0 {
+			return 0, false
+		}
+		return points[len(points)-1].V, true
+	},
+}
+
+// The aggregation operators which can be used in a query, for example
+// `sum by (room) (temperature)`.
+var aggregations = map[string]bool{
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+	"count": true,
+}
+
+// extrapolatedRate function calculates the rate, increase or delta the same
+// way Prometheus does: the change between the first and last points is
+// extrapolated to the edges of the range unless the series starts or ends
+// inside the range. Counters are allowed to reset to zero.
+func extrapolatedRate(points []Point, end int64, r time.Duration, isCounter bool, isRate bool) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	first, last := points[0], points[len(points)-1]
+	if first.T == last.T {
+		return 0, false
+	}
+	start := end - int64(r)
+
+	result := last.V - first.V
+	if isCounter {
+		prev := first.V
+		for _, p := range points[1:] {
+			if p.V < prev {
+				result += prev
+			}
+			prev = p.V
+		}
+	}
+
+	durationToStart := float64(first.T-start) / 1e9
+	durationToEnd := float64(end-last.T) / 1e9
+	sampledInterval := float64(last.T-first.T) / 1e9
+	averageDurationBetweenSamples := sampledInterval / float64(len(points)-1)
+
+	// Counters cannot go below zero so do not extrapolate past that.
+	if isCounter && result > 0 && first.V >= 0 {
+		durationToZero := sampledInterval * (first.V / result)
+		if durationToZero < durationToStart {
+			durationToStart = durationToZero
+		}
+	}
+
+	// Only extrapolate to the edge of the range if the series likely
+	// continues there, otherwise only half an interval.
+	extrapolationThreshold := averageDurationBetweenSamples * 1.1
+	extrapolateToInterval := sampledInterval
+	if durationToStart < extrapolationThreshold {
+		extrapolateToInterval += durationToStart
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+	if durationToEnd < extrapolationThreshold {
+		extrapolateToInterval += durationToEnd
+	} else {
+		extrapolateToInterval += averageDurationBetweenSamples / 2
+	}
+
+	result = result * (extrapolateToInterval / sampledInterval)
+	if isRate {
+		result = result / r.Seconds()
+	}
+	return result, true
+}
+
+// instantRate function calculates the per second rate from the last two
+// points of the range.
+func instantRate(points []Point, end int64, r time.Duration) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	prev, last := points[len(points)-2], points[len(points)-1]
+	if last.T == prev.T {
+		return 0, false
+	}
+
+	result := last.V - prev.V
+	if last.V < prev.V {
+		// The counter was reset.
+		result = last.V
+	}
+	return result / (float64(last.T-prev.T) / 1e9), true
+}
@@ -0,0 +1,233 @@
+package promql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdentifier
+	tokenNumber
+	tokenString
+	tokenDuration
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBrace
+	tokenRightBrace
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+	tokenEqual
+	tokenNotEqual
+	tokenRegexp
+	tokenNotRegexp
+	tokenAdd
+	tokenSub
+	tokenMul
+	tokenDiv
+	tokenMod
+	tokenPow
+)
+
+// token is a piece of the query along with where it starts in the query.
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// lexer splits the query into tokens.
+type lexer struct {
+	input string
+	pos   int
+	// Durations are only expected right after `[` or `offset`.
+	expectDuration bool
+}
+
+// Utility function which returns every token of the query.
+func lex(input string) ([]token, error) {
+	l := &lexer{input: input}
+	tokens := []token{}
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.typ == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	t, err := l.scan()
+	l.expectDuration = t.typ == tokenLeftBracket || (t.typ == tokenIdentifier && t.value == "offset")
+	return t, err
+}
+
+func (l *lexer) scan() (token, error) {
+	// Skip whitespace and comments.
+	for l.pos < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if r == '#' {
+			for l.pos < len(l.input) && l.input[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		}
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += size
+	}
+	if l.pos >= len(l.input) {
+		return token{typ: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	emit := func(typ tokenType, size int) (token, error) {
+		l.pos += size
+		return token{typ: typ, value: l.input[start:l.pos], pos: start}, nil
+	}
+
+	c := l.input[l.pos]
+	switch {
+	case c == '(':
+		return emit(tokenLeftParen, 1)
+	case c == ')':
+		return emit(tokenRightParen, 1)
+	case c == '{':
+		return emit(tokenLeftBrace, 1)
+	case c == '}':
+		return emit(tokenRightBrace, 1)
+	case c == '[':
+		return emit(tokenLeftBracket, 1)
+	case c == ']':
+		return emit(tokenRightBracket, 1)
+	case c == ',':
+		return emit(tokenComma, 1)
+	case c == '+':
+		return emit(tokenAdd, 1)
+	case c == '-':
+		return emit(tokenSub, 1)
+	case c == '*':
+		return emit(tokenMul, 1)
+	case c == '/':
+		return emit(tokenDiv, 1)
+	case c == '%':
+		return emit(tokenMod, 1)
+	case c == '^':
+		return emit(tokenPow, 1)
+	case strings.HasPrefix(l.input[l.pos:], "=~"):
+		return emit(tokenRegexp, 2)
+	case strings.HasPrefix(l.input[l.pos:], "!~"):
+		return emit(tokenNotRegexp, 2)
+	case strings.HasPrefix(l.input[l.pos:], "!="):
+		return emit(tokenNotEqual, 2)
+	case c == '=':
+		return emit(tokenEqual, 1)
+	case c == '"' || c == '\'' || c == '`':
+		return l.lexString(c)
+	case l.expectDuration && isDigit(c):
+		return l.lexDuration()
+	case isDigit(c) || (c == '.' && l.pos+1 < len(l.input) && isDigit(l.input[l.pos+1])):
+		return l.lexNumber()
+	case isIdentifierStart(c):
+		for l.pos < len(l.input) && isIdentifierChar(l.input[l.pos]) {
+			l.pos++
+		}
+		value := l.input[start:l.pos]
+		// A number may also be written as `Inf` or `NaN`.
+		if strings.EqualFold(value, "inf") || strings.EqualFold(value, "nan") {
+			return token{typ: tokenNumber, value: value, pos: start}, nil
+		}
+		return token{typ: tokenIdentifier, value: value, pos: start}, nil
+	}
+	return token{}, &ParseError{Pos: start, Err: fmt.Sprintf("unexpected character %q", c)}
+}
+
+func (l *lexer) lexString(quote byte) (token, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		if c == quote {
+			l.pos++
+			return token{typ: tokenString, value: b.String(), pos: start}, nil
+		}
+		if c == '\\' && quote != '`' && l.pos+1 < len(l.input) {
+			l.pos++
+			switch e := l.input[l.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(e)
+			}
+			l.pos++
+			continue
+		}
+		b.WriteByte(c)
+		l.pos++
+	}
+	return token{}, &ParseError{Pos: start, Err: "unterminated string"}
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	// Exponents such as `1e3` or `2.5e-3`.
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		l.pos++
+		if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
+			l.pos++
+		}
+		for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+			l.pos++
+		}
+	}
+	return token{typ: tokenNumber, value: l.input[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) lexDuration() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || isLetter(l.input[l.pos])) {
+		l.pos++
+	}
+	return token{typ: tokenDuration, value: l.input[start:l.pos], pos: start}, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierStart(c byte) bool {
+	return isLetter(c) || c == '_' || c == ':'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c)
+}
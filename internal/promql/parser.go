@@ -0,0 +1,401 @@
+package promql
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/tsdb"
+)
+
+// Parse parses the query into its expression. Only a subset of PromQL is
+// supported: vector and range selectors with `offset`, numbers, the
+// `functions` taking a range, the `aggregations` with `by` or `without` and
+// the `+`, `-`, `*`, `/`, `%` and `^` operators.
+func Parse(query string) (Expr, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseExpr(1)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, p.errorf(t, "unexpected %v", t)
+	}
+	return expr, nil
+}
+
+// ParseMetricSelector parses a vector selector such as
+// `temperature{room="kitchen"}` into its matchers.
+func ParseMetricSelector(selector string) ([]*tsdb.Matcher, error) {
+	expr, err := Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+	vs, ok := expr.(*VectorSelector)
+	if !ok || vs.Offset != 0 {
+		return nil, &ParseError{Pos: 0, Err: "expected a vector selector"}
+	}
+	return vs.Matchers, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(typ tokenType, context string) (token, error) {
+	t := p.next()
+	if t.typ != typ {
+		return t, p.errorf(t, "unexpected %v in %v", t, context)
+	}
+	return t, nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &ParseError{Pos: t.pos, Err: fmt.Sprintf(format, args...)}
+}
+
+// The binary operators ordered by how tightly they bind.
+var precedences = map[tokenType]int{
+	tokenAdd: 1,
+	tokenSub: 1,
+	tokenMul: 2,
+	tokenDiv: 2,
+	tokenMod: 2,
+	tokenPow: 3,
+}
+
+// parseExpr parses the binary operators which bind at least as tightly as
+// `minPrecedence` using precedence climbing.
+func (p *parser) parseExpr(minPrecedence int) (Expr, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		precedence, ok := precedences[op.typ]
+		if !ok || precedence < minPrecedence {
+			return lhs, nil
+		}
+		p.next()
+
+		// The power operator is right associative.
+		nextPrecedence := precedence + 1
+		if op.typ == tokenPow {
+			nextPrecedence = precedence
+		}
+		rhs, err := p.parseExpr(nextPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		for _, operand := range []Expr{lhs, rhs} {
+			if operand.Type() == ValueTypeMatrix {
+				return nil, p.errorf(op, "binary expression must contain only scalar and instant vector types")
+			}
+		}
+		lhs = &BinaryExpr{Op: op.value, LHS: lhs, RHS: rhs}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	t := p.peek()
+	if t.typ != tokenSub && t.typ != tokenAdd {
+		return p.parsePostfix()
+	}
+	p.next()
+
+	// Only the power operator binds more tightly than the sign.
+	expr, err := p.parseExpr(precedences[tokenPow])
+	if err != nil {
+		return nil, err
+	}
+	if expr.Type() == ValueTypeMatrix {
+		return nil, p.errorf(t, "unary expression only allowed on expressions of type scalar or instant vector")
+	}
+	if t.typ == tokenAdd {
+		return expr, nil
+	}
+	if n, ok := expr.(*NumberLiteral); ok {
+		return &NumberLiteral{Value: -n.Value}, nil
+	}
+	return &UnaryExpr{Expr: expr}, nil
+}
+
+// parsePostfix parses the range and offset which may follow a selector.
+func (p *parser) parsePostfix() (Expr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.typ == tokenLeftBracket {
+		p.next()
+		vs, ok := expr.(*VectorSelector)
+		if !ok || vs.Offset != 0 {
+			return nil, p.errorf(t, "ranges are only allowed for vector selectors")
+		}
+		r, err := p.parseDuration()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightBracket, "range"); err != nil {
+			return nil, err
+		}
+		expr = &MatrixSelector{Vector: vs, Range: r}
+	}
+
+	if t := p.peek(); t.typ == tokenIdentifier && t.value == "offset" {
+		p.next()
+		offset, err := p.parseDuration()
+		if err != nil {
+			return nil, err
+		}
+		switch e := expr.(type) {
+		case *VectorSelector:
+			e.Offset = offset
+		case *MatrixSelector:
+			e.Vector.Offset = offset
+		default:
+			return nil, p.errorf(t, "offset modifier must be preceded by a selector")
+		}
+	}
+	return expr, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.peek()
+	switch t.typ {
+	case tokenNumber:
+		p.next()
+		v, err := parseNumber(t.value)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %v", t)
+		}
+		return &NumberLiteral{Value: v}, nil
+
+	case tokenLeftParen:
+		p.next()
+		expr, err := p.parseExpr(1)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen, "parenthesized expression"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+
+	case tokenLeftBrace:
+		return p.parseSelector("")
+
+	case tokenIdentifier:
+		p.next()
+		next := p.peek()
+		if aggregations[t.value] && (next.typ == tokenLeftParen || next.value == "by" || next.value == "without") {
+			return p.parseAggregation(t)
+		}
+		if next.typ == tokenLeftParen {
+			return p.parseCall(t)
+		}
+		return p.parseSelector(t.value)
+	}
+	return nil, p.errorf(t, "unexpected %v", t)
+}
+
+func (p *parser) parseCall(name token) (Expr, error) {
+	if _, ok := functions[name.value]; !ok {
+		return nil, p.errorf(name, "unknown function with name %q", name.value)
+	}
+	p.next() // (
+
+	arg, err := p.parseExpr(1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenRightParen, "function call"); err != nil {
+		return nil, err
+	}
+	if arg.Type() != ValueTypeMatrix {
+		return nil, p.errorf(name, "expected type range vector in call to function %q, got %v", name.value, arg.Type())
+	}
+	return &Call{Func: name.value, Args: []Expr{arg}}, nil
+}
+
+func (p *parser) parseAggregation(op token) (Expr, error) {
+	agg := &AggregateExpr{Op: op.value}
+
+	// The grouping may come before or after the expression.
+	parsedGrouping := false
+	if t := p.peek(); t.value == "by" || t.value == "without" {
+		if err := p.parseGrouping(agg); err != nil {
+			return nil, err
+		}
+		parsedGrouping = true
+	}
+
+	if _, err := p.expect(tokenLeftParen, "aggregation"); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseExpr(1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenRightParen, "aggregation"); err != nil {
+		return nil, err
+	}
+	if expr.Type() != ValueTypeVector {
+		return nil, p.errorf(op, "expected type instant vector in aggregation expression, got %v", expr.Type())
+	}
+	agg.Expr = expr
+
+	if t := p.peek(); !parsedGrouping && (t.value == "by" || t.value == "without") {
+		if err := p.parseGrouping(agg); err != nil {
+			return nil, err
+		}
+	}
+	return agg, nil
+}
+
+func (p *parser) parseGrouping(agg *AggregateExpr) error {
+	agg.Without = p.next().value == "without"
+	if _, err := p.expect(tokenLeftParen, "grouping"); err != nil {
+		return err
+	}
+	agg.Grouping = []string{}
+	for p.peek().typ != tokenRightParen {
+		t, err := p.expect(tokenIdentifier, "grouping")
+		if err != nil {
+			return err
+		}
+		agg.Grouping = append(agg.Grouping, t.value)
+		if p.peek().typ != tokenComma {
+			break
+		}
+		p.next()
+	}
+	_, err := p.expect(tokenRightParen, "grouping")
+	return err
+}
+
+func (p *parser) parseSelector(name string) (Expr, error) {
+	vs := &VectorSelector{Name: name, Matchers: []*tsdb.Matcher{}}
+	if name != "" {
+		m, _ := tsdb.NewMatcher(tsdb.MatchEqual, tsdb.MetricNameLabel, name)
+		vs.Matchers = append(vs.Matchers, m)
+	}
+
+	start := p.peek()
+	if start.typ == tokenLeftBrace {
+		p.next()
+		for p.peek().typ != tokenRightBrace {
+			label, err := p.expect(tokenIdentifier, "label matching")
+			if err != nil {
+				return nil, err
+			}
+			op := p.next()
+			switch op.typ {
+			case tokenEqual, tokenNotEqual, tokenRegexp, tokenNotRegexp:
+			default:
+				return nil, p.errorf(op, "unexpected %v in label matching, expected one of \"=\", \"!=\", \"=~\" or \"!~\"", op)
+			}
+			value, err := p.expect(tokenString, "label matching")
+			if err != nil {
+				return nil, err
+			}
+			m, err := tsdb.NewMatcher(op.value, label.value, value.value)
+			if err != nil {
+				return nil, p.errorf(value, "invalid regular expression %q: %v", value.value, err)
+			}
+			vs.Matchers = append(vs.Matchers, m)
+
+			if p.peek().typ != tokenComma {
+				break
+			}
+			p.next()
+		}
+		if _, err := p.expect(tokenRightBrace, "label matching"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Selecting every series by accident would be very expensive.
+	for _, m := range vs.Matchers {
+		if !m.Matches("") {
+			return vs, nil
+		}
+	}
+	return nil, p.errorf(start, "vector selector must contain at least one non-empty matcher")
+}
+
+// The units a duration can be written in, such as `5m` or `1h30m`.
+var durationRegexp = regexp.MustCompile(`^(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?$`)
+
+func (p *parser) parseDuration() (time.Duration, error) {
+	t, err := p.expect(tokenDuration, "duration")
+	if err != nil {
+		return 0, err
+	}
+	d, err := ParseDuration(t.value)
+	if err != nil {
+		return 0, p.errorf(t, err.Error())
+	}
+	return d, nil
+}
+
+// ParseDuration parses a duration the way it is written in a query, for
+// example `5m` or `1h30m`.
+func ParseDuration(value string) (time.Duration, error) {
+	matches := durationRegexp.FindStringSubmatch(value)
+	if matches == nil || value == "" {
+		return 0, fmt.Errorf("not a valid duration %q", value)
+	}
+	units := []time.Duration{
+		365 * 24 * time.Hour,
+		7 * 24 * time.Hour,
+		24 * time.Hour,
+		time.Hour,
+		time.Minute,
+		time.Second,
+		time.Millisecond,
+	}
+	var d time.Duration
+	for i, unit := range units {
+		if n := matches[2+i*2]; n != "" {
+			v, _ := strconv.ParseInt(n, 10, 64)
+			d += time.Duration(v) * unit
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be greater than 0")
+	}
+	return d, nil
+}
+
+func parseNumber(value string) (float64, error) {
+	switch strings.ToLower(value) {
+	case "inf":
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
@@ -0,0 +1,212 @@
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Utility function which writes the expression with every binary and unary
+// expression in parentheses so the tests can tell how it was grouped.
+func exprString(expr Expr) string {
+	switch e := expr.(type) {
+	case *NumberLiteral:
+		return fmt.Sprint(e.Value)
+	case *VectorSelector:
+		matchers := make([]string, 0, len(e.Matchers))
+		for _, m := range e.Matchers {
+			matchers = append(matchers, m.String())
+		}
+		s := "{" + strings.Join(matchers, ",") + "}"
+		if e.Offset != 0 {
+			s += " offset " + e.Offset.String()
+		}
+		return s
+	case *MatrixSelector:
+		s := exprString(&VectorSelector{Matchers: e.Vector.Matchers}) + "[" + e.Range.String() + "]"
+		if e.Vector.Offset != 0 {
+			s += " offset " + e.Vector.Offset.String()
+		}
+		return s
+	case *Call:
+		return e.Func + "(" + exprString(e.Args[0]) + ")"
+	case *AggregateExpr:
+		grouping := "by"
+		if e.Without {
+			grouping = "without"
+		}
+		return e.Op + " " + grouping + " (" + strings.Join(e.Grouping, ",") + ") (" + exprString(e.Expr) + ")"
+	case *BinaryExpr:
+		return "(" + exprString(e.LHS) + " " + e.Op + " " + exprString(e.RHS) + ")"
+	case *UnaryExpr:
+		return "-" + exprString(e.Expr)
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// Precedence and associativity.
+		{query: "1 + 2 * 3", want: "(1 + (2 * 3))"},
+		{query: "1 * 2 + 3", want: "((1 * 2) + 3)"},
+		{query: "1 - 2 - 3", want: "((1 - 2) - 3)"},
+		{query: "8 / 4 % 3", want: "((8 / 4) % 3)"},
+		{query: "2 ^ 3 ^ 2", want: "(2 ^ (3 ^ 2))"},
+		{query: "2 * 3 ^ 2", want: "(2 * (3 ^ 2))"},
+		{query: "(1 + 2) * 3", want: "((1 + 2) * 3)"},
+
+		// Unary minus binds less tightly than the power operator only.
+		{query: "-2", want: "-2"},
+		{query: "+2", want: "2"},
+		{query: "-2 ^ 2", want: "-(2 ^ 2)"},
+		{query: "-2 * 3", want: "(-2 * 3)"},
+		{query: "1 - -2", want: "(1 - -2)"},
+		{query: "-a", want: `-{__name__="a"}`},
+		{query: "-a ^ 2", want: `-({__name__="a"} ^ 2)`},
+
+		// Selectors.
+		{query: "a", want: `{__name__="a"}`},
+		{query: `a{room="kitchen",floor!="1"}`, want: `{__name__="a",room="kitchen",floor!="1"}`},
+		{query: `{room=~"kitchen|garage"}`, want: `{room=~"kitchen|garage"}`},
+		{query: "a offset 5m", want: `{__name__="a"} offset 5m0s`},
+		{query: "a[5m]", want: `{__name__="a"}[5m0s]`},
+		{query: "a[1h30m] offset 1d", want: `{__name__="a"}[1h30m0s] offset 24h0m0s`},
+
+		// Functions and aggregations.
+		{query: "rate(a[5m])", want: `rate({__name__="a"}[5m0s])`},
+		{query: "sum(a)", want: `sum by () ({__name__="a"})`},
+		{query: "sum by (room) (a)", want: `sum by (room) ({__name__="a"})`},
+		{query: "sum(a) by (room)", want: `sum by (room) ({__name__="a"})`},
+		{query: "avg without (room, floor) (a)", want: `avg without (room,floor) ({__name__="a"})`},
+		{query: "max(a) without (room)", want: `max without (room) ({__name__="a"})`},
+		{query: "sum by (room) (rate(a[5m])) / 2", want: `(sum by (room) (rate({__name__="a"}[5m0s])) / 2)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.query, err)
+			}
+			if got := exprString(expr); got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "", want: "unexpected"},
+		{query: "1 +", want: "unexpected"},
+		{query: "(1 + 2", want: "parenthesized expression"},
+		{query: "1 2", want: "unexpected"},
+
+		// A selector must not select every series.
+		{query: "{}", want: "at least one non-empty matcher"},
+		{query: `{room=""}`, want: "at least one non-empty matcher"},
+		{query: `{room=~".*"}`, want: "at least one non-empty matcher"},
+		{query: `{room!="kitchen"}`, want: "at least one non-empty matcher"},
+		{query: `a{room="kitchen"`, want: "label matching"},
+		{query: `a{room~"kitchen"}`, want: "unexpected character"},
+		{query: `a{room=~"("}`, want: "invalid regular expression"},
+
+		// Ranges and offsets.
+		{query: "a[5m", want: "range"},
+		{query: "a[5]", want: "duration"},
+		{query: "(a offset 5m)[5m]", want: "ranges are only allowed for vector selectors"},
+		{query: "(a)[5m] offset", want: "duration"},
+		{query: "1 offset 5m", want: "offset modifier must be preceded by a selector"},
+		{query: "a[5m] + 1", want: "binary expression must contain only scalar and instant vector types"},
+		{query: "-a[5m]", want: "unary expression only allowed"},
+
+		// Functions.
+		{query: "rate(a)", want: `expected type range vector in call to function "rate"`},
+		{query: "unknown(a[5m])", want: `unknown function with name "unknown"`},
+
+		// Aggregations and their grouping.
+		{query: "sum(a[5m])", want: "expected type instant vector in aggregation expression"},
+		{query: "sum by (room)", want: "aggregation"},
+		{query: "sum by (room (a)", want: "grouping"},
+		{query: "sum by (room) (a) by (floor)", want: "unexpected"},
+		{query: "sum without (room) (a) without (floor)", want: "unexpected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("Parse(%q) did not fail", tt.query)
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%q) failed with %T, want *ParseError", tt.query, err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) failed with %q, want it to contain %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "5m", want: 5 * time.Minute},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "1d", want: 24 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "1y", want: 365 * 24 * time.Hour},
+		{value: "1s500ms", want: 1500 * time.Millisecond},
+		{value: "1y1w1d1h1m1s1ms", want: 365*24*time.Hour + 7*24*time.Hour + 24*time.Hour + time.Hour + time.Minute + time.Second + time.Millisecond},
+		{value: "", wantErr: true},
+		{value: "0s", wantErr: true},
+		{value: "5", wantErr: true},
+		{value: "1.5h", wantErr: true},
+		{value: "-5m", wantErr: true},
+		{value: "5m1h", wantErr: true},
+		{value: "5M", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDuration(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseDuration(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) failed: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMetricSelector(t *testing.T) {
+	matchers, err := ParseMetricSelector(`a{room="kitchen"}`)
+	if err != nil {
+		t.Fatalf("ParseMetricSelector failed: %v", err)
+	}
+	if got := exprString(&VectorSelector{Matchers: matchers}); got != `{__name__="a",room="kitchen"}` {
+		t.Errorf("ParseMetricSelector = %v", got)
+	}
+
+	for _, selector := range []string{"a[5m]", "a offset 5m", "sum(a)", "1"} {
+		if _, err := ParseMetricSelector(selector); err == nil {
+			t.Errorf("ParseMetricSelector(%q) did not fail", selector)
+		}
+	}
+}
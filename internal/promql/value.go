@@ -0,0 +1,137 @@
+package promql
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bartmika/mothership-server/internal/tsdb"
+)
+
+// ValueType is the type of the value an expression evaluates to.
+type ValueType string
+
+const (
+	ValueTypeScalar ValueType = "scalar"
+	ValueTypeVector ValueType = "vector"
+	ValueTypeMatrix ValueType = "matrix"
+)
+
+// Label is a label of a series, including the metric name which is saved as
+// the `__name__` label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Labels identify a series. They are always kept sorted by name.
+type Labels []Label
+
+// NewLabels returns the labels sorted by name.
+func NewLabels(arr ...Label) Labels {
+	labels := append(Labels{}, arr...)
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return labels
+}
+
+// Get returns the value of the label or an empty value if there is no label
+// with the name.
+func (ls Labels) Get(name string) string {
+	for _, l := range ls {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+// String returns the labels in the same format Prometheus uses, for example
+// `{__name__="temperature", room="kitchen"}`.
+func (ls Labels) String() string {
+	parts := make([]string, 0, len(ls))
+	for _, l := range ls {
+		parts = append(parts, l.Name+"=\""+l.Value+"\"")
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Utility function which returns the labels without the metric name.
+func (ls Labels) withoutName() Labels {
+	res := make(Labels, 0, len(ls))
+	for _, l := range ls {
+		if l.Name != tsdb.MetricNameLabel {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// Utility function which returns only the labels with the names, or every
+// label except the ones with the names (and the metric name) if `without`.
+func (ls Labels) grouping(names []string, without bool) Labels {
+	res := Labels{}
+	for _, l := range ls {
+		listed := false
+		for _, name := range names {
+			if l.Name == name {
+				listed = true
+				break
+			}
+		}
+		if without && !listed && l.Name != tsdb.MetricNameLabel {
+			res = append(res, l)
+		}
+		if !without && listed {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// Point is a value at a time, in Unix nanoseconds.
+type Point struct {
+	T int64
+	V float64
+}
+
+// Sample is the value of a series at a time.
+type Sample struct {
+	Labels Labels
+	Point
+}
+
+// Series is the values of a series over time.
+type Series struct {
+	Labels Labels
+	Points []Point
+}
+
+// Vector is the value of every series at the same time.
+type Vector []Sample
+
+// Matrix is the values of every series over time.
+type Matrix []*Series
+
+// Result is the value of a query: a scalar, a vector or a matrix, depending
+// on the type.
+type Result struct {
+	Type   ValueType
+	Scalar Point
+	Vector Vector
+	Matrix Matrix
+}
+
+// Utility function which orders the samples by their labels.
+func sortVector(v Vector) {
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].Labels.String() < v[j].Labels.String()
+	})
+}
+
+// Utility function which orders the series by their labels.
+func sortMatrix(m Matrix) {
+	sort.Slice(m, func(i, j int) bool {
+		return m[i].Labels.String() < m[j].Labels.String()
+	})
+}
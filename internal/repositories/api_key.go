@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+type APIKeyRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewAPIKeyRepo(dbpool *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *APIKeyRepo) Insert(ctx context.Context, m *models.APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    INSERT INTO api_keys (
+        uuid, tenant_id, user_id, name, prefix, key_hash, scopes, state,
+		expiry_time, last_used_time, created_time, modified_time
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+    )`
+
+	_, err := r.dbpool.Exec(ctx, query, m.Uuid, m.TenantId, m.UserId, m.Name, m.Prefix, m.KeyHash, m.Scopes, m.State, m.ExpiryTime, m.LastUsedTime, m.CreatedTime, m.ModifiedTime)
+	if err != nil {
+		log.Println("APIKeyRepo|Insert|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *APIKeyRepo) UpdateById(ctx context.Context, m *models.APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    UPDATE
+        api_keys
+    SET
+        name = $1, scopes = $2, state = $3, expiry_time = $4, last_used_time = $5, modified_time = $6
+    WHERE
+        id = $7`
+
+	_, err := r.dbpool.Exec(ctx, query, m.Name, m.Scopes, m.State, m.ExpiryTime, m.LastUsedTime, m.ModifiedTime, m.Id)
+	if err != nil {
+		log.Println("APIKeyRepo|UpdateById|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *APIKeyRepo) UpdateLastUsedTimeById(ctx context.Context, id uint64, t time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE api_keys SET last_used_time = $1 WHERE id = $2`
+
+	_, err := r.dbpool.Exec(ctx, query, t, id)
+	if err != nil {
+		log.Println("APIKeyRepo|UpdateLastUsedTimeById|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *APIKeyRepo) GetByUuid(ctx context.Context, uid string) (*models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, user_id, name, prefix, key_hash, scopes, state,
+		expiry_time, last_used_time, created_time, modified_time
+    FROM
+        api_keys
+    WHERE
+        uuid = $1`
+
+	m, err := scanAPIKey(r.dbpool.QueryRow(ctx, query, uid))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("APIKeyRepo|GetByUuid|err", err)
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (r *APIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, user_id, name, prefix, key_hash, scopes, state,
+		expiry_time, last_used_time, created_time, modified_time
+    FROM
+        api_keys
+    WHERE
+        prefix = $1`
+
+	m, err := scanAPIKey(r.dbpool.QueryRow(ctx, query, prefix))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("APIKeyRepo|GetByPrefix|err", err)
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (r *APIKeyRepo) ListByTenantId(ctx context.Context, tenantId uint64) ([]*models.APIKey, error) {
+	var arr []*models.APIKey
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, user_id, name, prefix, key_hash, scopes, state,
+		expiry_time, last_used_time, created_time, modified_time
+    FROM
+        api_keys
+    WHERE
+        tenant_id = $1
+    ORDER BY (id) ASC`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.dbpool.Query(ctx, query, tenantId)
+	if err != nil {
+		return arr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanAPIKey(rows)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, m)
+	}
+
+	// Any errors encountered by rows.Next or rows.Scan will be returned here
+	if rows.Err() != nil {
+		return arr, rows.Err()
+	}
+
+	return arr, nil
+}
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	m := new(models.APIKey)
+	err := row.Scan(
+		&m.Id, &m.Uuid, &m.TenantId, &m.UserId, &m.Name, &m.Prefix, &m.KeyHash,
+		&m.Scopes, &m.State, &m.ExpiryTime, &m.LastUsedTime, &m.CreatedTime,
+		&m.ModifiedTime)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
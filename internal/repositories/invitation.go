@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+type InvitationRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewInvitationRepo(dbpool *pgxpool.Pool) *InvitationRepo {
+	return &InvitationRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *InvitationRepo) Insert(ctx context.Context, m *models.Invitation) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    INSERT INTO invitations (
+        uuid, tenant_id, invited_by_user_id, email, role_id, token_hash, state,
+		expiry_time, created_time, modified_time
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    )`
+
+	_, err := r.dbpool.Exec(ctx, query, m.Uuid, m.TenantId, m.InvitedByUserId, m.Email, m.RoleId, m.TokenHash, m.State, m.ExpiryTime, m.CreatedTime, m.ModifiedTime)
+	if err != nil {
+		log.Println("InvitationRepo|Insert|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *InvitationRepo) UpdateById(ctx context.Context, m *models.Invitation) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    UPDATE
+        invitations
+    SET
+        role_id = $1, state = $2, expiry_time = $3, modified_time = $4
+    WHERE
+        id = $5`
+
+	_, err := r.dbpool.Exec(ctx, query, m.RoleId, m.State, m.ExpiryTime, m.ModifiedTime, m.Id)
+	if err != nil {
+		log.Println("InvitationRepo|UpdateById|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *InvitationRepo) GetByUuid(ctx context.Context, uid string) (*models.Invitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, invited_by_user_id, email, role_id, token_hash, state,
+		expiry_time, created_time, modified_time
+    FROM
+        invitations
+    WHERE
+        uuid = $1`
+
+	m, err := scanInvitation(r.dbpool.QueryRow(ctx, query, uid))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("InvitationRepo|GetByUuid|err", err)
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (r *InvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, invited_by_user_id, email, role_id, token_hash, state,
+		expiry_time, created_time, modified_time
+    FROM
+        invitations
+    WHERE
+        token_hash = $1`
+
+	m, err := scanInvitation(r.dbpool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("InvitationRepo|GetByTokenHash|err", err)
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (r *InvitationRepo) ListByTenantId(ctx context.Context, tenantId uint64) ([]*models.Invitation, error) {
+	var arr []*models.Invitation
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, invited_by_user_id, email, role_id, token_hash, state,
+		expiry_time, created_time, modified_time
+    FROM
+        invitations
+    WHERE
+        tenant_id = $1
+    ORDER BY (id) ASC`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.dbpool.Query(ctx, query, tenantId)
+	if err != nil {
+		return arr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanInvitation(rows)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, m)
+	}
+
+	// Any errors encountered by rows.Next or rows.Scan will be returned here
+	if rows.Err() != nil {
+		return arr, rows.Err()
+	}
+
+	return arr, nil
+}
+
+func (r *InvitationRepo) RevokePendingByTenantIdAndEmail(ctx context.Context, tenantId uint64, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    UPDATE
+        invitations
+    SET
+        state = $1, modified_time = $2
+    WHERE
+        tenant_id = $3 AND email = $4 AND state = $5`
+
+	_, err := r.dbpool.Exec(ctx, query, models.InvitationRevokedState, time.Now(), tenantId, email, models.InvitationPendingState)
+	if err != nil {
+		log.Println("InvitationRepo|RevokePendingByTenantIdAndEmail|err", err)
+		return err
+	}
+	return nil
+}
+
+func scanInvitation(row pgx.Row) (*models.Invitation, error) {
+	m := new(models.Invitation)
+	err := row.Scan(
+		&m.Id, &m.Uuid, &m.TenantId, &m.InvitedByUserId, &m.Email, &m.RoleId,
+		&m.TokenHash, &m.State, &m.ExpiryTime, &m.CreatedTime, &m.ModifiedTime)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+type MetricRetentionPolicyRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewMetricRetentionPolicyRepo(dbpool *pgxpool.Pool) *MetricRetentionPolicyRepo {
+	return &MetricRetentionPolicyRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *MetricRetentionPolicyRepo) InsertOrUpdateByTenantIdAndMetric(ctx context.Context, m *models.MetricRetentionPolicy) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    INSERT INTO metric_retention_policies (
+        tenant_id, metric, retention, created_time, modified_time
+    ) VALUES (
+        $1, $2, $3, $4, $5
+    ) ON CONFLICT (tenant_id, metric) DO UPDATE SET
+        retention = EXCLUDED.retention,
+		modified_time = EXCLUDED.modified_time`
+
+	_, err := r.dbpool.Exec(ctx, query, m.TenantId, m.Metric, int64(m.Retention.Seconds()), m.CreatedTime, m.ModifiedTime)
+	if err != nil {
+		log.Println("MetricRetentionPolicyRepo|InsertOrUpdateByTenantIdAndMetric|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MetricRetentionPolicyRepo) DeleteByTenantIdAndMetric(ctx context.Context, tenantId uint64, metric string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `DELETE FROM metric_retention_policies WHERE tenant_id = $1 AND metric = $2`
+
+	_, err := r.dbpool.Exec(ctx, query, tenantId, metric)
+	if err != nil {
+		log.Println("MetricRetentionPolicyRepo|DeleteByTenantIdAndMetric|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *MetricRetentionPolicyRepo) ListByTenantId(ctx context.Context, tenantId uint64) ([]*models.MetricRetentionPolicy, error) {
+	var arr []*models.MetricRetentionPolicy
+
+	query := `
+    SELECT
+        tenant_id, metric, retention, created_time, modified_time
+    FROM
+        metric_retention_policies
+    WHERE
+        tenant_id = $1
+    ORDER BY (metric) ASC`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.dbpool.Query(ctx, query, tenantId)
+	if err != nil {
+		log.Println("MetricRetentionPolicyRepo|ListByTenantId|err", err)
+		return arr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanMetricRetentionPolicy(rows)
+		if err != nil {
+			log.Println("MetricRetentionPolicyRepo|ListByTenantId|err", err)
+			return arr, err
+		}
+		arr = append(arr, m)
+	}
+
+	// Any errors encountered by rows.Next or rows.Scan will be returned here
+	if rows.Err() != nil {
+		return arr, rows.Err()
+	}
+
+	return arr, nil
+}
+
+// Utility function which reads the policy from the row and converts the
+// retention saved in seconds.
+func scanMetricRetentionPolicy(row pgx.Row) (*models.MetricRetentionPolicy, error) {
+	m := new(models.MetricRetentionPolicy)
+	var retention int64
+	err := row.Scan(&m.TenantId, &m.Metric, &retention, &m.CreatedTime, &m.ModifiedTime)
+	if err != nil {
+		return nil, err
+	}
+	m.Retention = time.Duration(retention) * time.Second
+	return m, nil
+}
@@ -22,20 +22,20 @@ func NewTenantRepo(dbpool *pgxpool.Pool) *TenantRepo {
 	}
 }
 
-func (r *TenantRepo) Insert(ctx context.Context, m *models.Tenant) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	query := `
+const insertTenantQuery = `
     INSERT INTO tenants (
-        uuid, name, state, timezone, created_time, modified_time
+        uuid, name, state, timezone, is_root, created_time, modified_time
 
     ) VALUES (
-        $1, $2, $3, $4, $5, $6
+        $1, $2, $3, $4, $5, $6, $7
     )
     `
 
-	_, err := r.dbpool.Exec(ctx, query, m.Uuid, m.Name, m.State, m.Timezone, m.CreatedTime, m.ModifiedTime)
+func (r *TenantRepo) Insert(ctx context.Context, m *models.Tenant) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.dbpool.Exec(ctx, insertTenantQuery, m.Uuid, m.Name, m.State, m.Timezone, m.IsRoot, m.CreatedTime, m.ModifiedTime)
 	if err != nil {
 		log.Println("TenantRepo|Insert|err", err)
 		return err
@@ -43,6 +43,38 @@ func (r *TenantRepo) Insert(ctx context.Context, m *models.Tenant) error {
 	return nil
 }
 
+// InsertWithUser inserts the tenant along with its first user in a single
+// transaction and sets the ids of both.
+func (r *TenantRepo) InsertWithUser(ctx context.Context, m *models.Tenant, u *models.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		log.Println("TenantRepo|InsertWithUser|err", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, insertTenantQuery+" RETURNING id", m.Uuid, m.Name, m.State, m.Timezone, m.IsRoot, m.CreatedTime, m.ModifiedTime).Scan(&m.Id)
+	if err != nil {
+		log.Println("TenantRepo|InsertWithUser|err", err)
+		return err
+	}
+	u.TenantId = m.Id
+	err = tx.QueryRow(ctx, insertUserQuery+" RETURNING id", insertUserArgs(u)...).Scan(&u.Id)
+	if err != nil {
+		log.Println("TenantRepo|InsertWithUser|err", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("TenantRepo|InsertWithUser|err", err)
+		return err
+	}
+	return nil
+}
+
 func (r *TenantRepo) UpdateById(ctx context.Context, m *models.Tenant) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -54,9 +86,12 @@ func (r *TenantRepo) UpdateById(ctx context.Context, m *models.Tenant) error {
         name = $1, state = $2, timezone = $3, created_time = $4, modified_time = $5
     WHERE
         id = $6
+    RETURNING
+        id, uuid, name, state, timezone, is_root, created_time, modified_time
     `
 
-	err := r.dbpool.QueryRow(ctx, query).Scan(&m)
+	// Load the row as it was saved so the caller has what is in the database.
+	err := r.dbpool.QueryRow(ctx, query, m.Name, m.State, m.Timezone, m.CreatedTime, m.ModifiedTime, m.Id).Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.IsRoot, &m.CreatedTime, &m.ModifiedTime)
 	if err != nil {
 		log.Println("TenantRepo|UpdateById|err", err)
 		return err
@@ -64,6 +99,56 @@ func (r *TenantRepo) UpdateById(ctx context.Context, m *models.Tenant) error {
 	return nil
 }
 
+func (r *TenantRepo) UpdateStateById(ctx context.Context, id uint64, state int8) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `UPDATE tenants SET state = $1, modified_time = $2 WHERE id = $3`
+
+	_, err := r.dbpool.Exec(ctx, query, state, time.Now(), id)
+	if err != nil {
+		log.Println("TenantRepo|UpdateStateById|err", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteById deletes the tenant along with its users, invitations and API
+// keys in a single transaction.
+func (r *TenantRepo) DeleteById(ctx context.Context, id uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.dbpool.Begin(ctx)
+	if err != nil {
+		log.Println("TenantRepo|DeleteById|err", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The order matters because of the foreign keys.
+	queries := []string{
+		`DELETE FROM api_keys WHERE tenant_id = $1`,
+		`DELETE FROM invitations WHERE tenant_id = $1`,
+		`DELETE FROM tenant_storage_configs WHERE tenant_id = $1`,
+		`DELETE FROM metric_retention_policies WHERE tenant_id = $1`,
+		`DELETE FROM users WHERE tenant_id = $1`,
+		`DELETE FROM tenants WHERE id = $1`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query, id); err != nil {
+			log.Println("TenantRepo|DeleteById|err", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Println("TenantRepo|DeleteById|err", err)
+		return err
+	}
+	return nil
+}
+
 func (r *TenantRepo) GetById(ctx context.Context, id uint64) (*models.Tenant, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -72,16 +157,20 @@ func (r *TenantRepo) GetById(ctx context.Context, id uint64) (*models.Tenant, er
 
 	query := `
     SELECT
-        id, uuid, name, state, timezone, created_time, modified_time
+        id, uuid, name, state, timezone, is_root, created_time, modified_time
     FROM
         tenants
     WHERE
         id = $1
     `
-	err := r.dbpool.QueryRow(ctx, query, id).Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.CreatedTime, &m.ModifiedTime)
+	err := r.dbpool.QueryRow(ctx, query, id).Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.IsRoot, &m.CreatedTime, &m.ModifiedTime)
 	if err != nil {
-		log.Println("TenantRepo|GetById|err", err)
-		return nil, err
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("TenantRepo|GetById|err", err)
+			return nil, err
+		}
 	}
 	return m, nil
 }
@@ -94,16 +183,48 @@ func (r *TenantRepo) GetByUuid(ctx context.Context, uid string) (*models.Tenant,
 
 	query := `
     SELECT
-        id, uuid, name, state, timezone, created_time, modified_time
+        id, uuid, name, state, timezone, is_root, created_time, modified_time
     FROM
         tenants
     WHERE
         uuid = $1
     `
-	err := r.dbpool.QueryRow(ctx, query, uid).Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.CreatedTime, &m.ModifiedTime)
+	err := r.dbpool.QueryRow(ctx, query, uid).Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.IsRoot, &m.CreatedTime, &m.ModifiedTime)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("TenantRepo|GetByUuid|err", err)
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// GetRoot returns the tenant our root users belong to or nil if it was not
+// created yet.
+func (r *TenantRepo) GetRoot(ctx context.Context) (*models.Tenant, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	m := new(models.Tenant)
+
+	query := `
+    SELECT
+        id, uuid, name, state, timezone, is_root, created_time, modified_time
+    FROM
+        tenants
+    WHERE
+        is_root = TRUE
+    `
+	err := r.dbpool.QueryRow(ctx, query).Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.IsRoot, &m.CreatedTime, &m.ModifiedTime)
 	if err != nil {
-		log.Println("TenantRepo|GetByUuid|err", err)
-		return nil, err
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("TenantRepo|GetRoot|err", err)
+			return nil, err
+		}
 	}
 	return m, nil
 }
@@ -239,3 +360,39 @@ func (r *TenantRepo) ListAllIds(ctx context.Context) ([]uint64, error) {
 
 	return ids, nil
 }
+
+func (r *TenantRepo) ListAll(ctx context.Context) ([]*models.Tenant, error) {
+	var arr []*models.Tenant
+
+	query := `
+    SELECT
+        id, uuid, name, state, timezone, is_root, created_time, modified_time
+    FROM
+        tenants
+    ORDER BY (id) ASC`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.dbpool.Query(ctx, query)
+	if err != nil {
+		return arr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := new(models.Tenant)
+		err = rows.Scan(&m.Id, &m.Uuid, &m.Name, &m.State, &m.Timezone, &m.IsRoot, &m.CreatedTime, &m.ModifiedTime)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, m)
+	}
+
+	// Any errors encountered by rows.Next or rows.Scan will be returned here
+	if rows.Err() != nil {
+		return arr, rows.Err()
+	}
+
+	return arr, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+type TenantDeletionRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewTenantDeletionRepo(dbpool *pgxpool.Pool) *TenantDeletionRepo {
+	return &TenantDeletionRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *TenantDeletionRepo) Insert(ctx context.Context, m *models.TenantDeletion) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    INSERT INTO tenant_deletions (
+        tenant_id, tenant_uuid, tenant_name, requested_by, users_count,
+		api_keys_count, data_size, created_time
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7, $8
+    )`
+
+	_, err := r.dbpool.Exec(ctx, query, m.TenantId, m.TenantUuid, m.TenantName, m.RequestedBy, m.UsersCount, m.APIKeysCount, m.DataSize, m.CreatedTime)
+	if err != nil {
+		log.Println("TenantDeletionRepo|Insert|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TenantDeletionRepo) ListAll(ctx context.Context) ([]*models.TenantDeletion, error) {
+	var arr []*models.TenantDeletion
+
+	query := `
+    SELECT
+        id, tenant_id, tenant_uuid, tenant_name, requested_by, users_count,
+		api_keys_count, data_size, created_time
+    FROM
+        tenant_deletions
+    ORDER BY (id) ASC`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.dbpool.Query(ctx, query)
+	if err != nil {
+		return arr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := new(models.TenantDeletion)
+		err = rows.Scan(&m.Id, &m.TenantId, &m.TenantUuid, &m.TenantName, &m.RequestedBy, &m.UsersCount, &m.APIKeysCount, &m.DataSize, &m.CreatedTime)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, m)
+	}
+
+	// Any errors encountered by rows.Next or rows.Scan will be returned here
+	if rows.Err() != nil {
+		return arr, rows.Err()
+	}
+
+	return arr, nil
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+type TenantStorageConfigRepo struct {
+	dbpool *pgxpool.Pool
+}
+
+func NewTenantStorageConfigRepo(dbpool *pgxpool.Pool) *TenantStorageConfigRepo {
+	return &TenantStorageConfigRepo{
+		dbpool: dbpool,
+	}
+}
+
+func (r *TenantStorageConfigRepo) InsertOrUpdateByTenantId(ctx context.Context, m *models.TenantStorageConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    INSERT INTO tenant_storage_configs (
+        tenant_id, partition_duration, timestamp_precision, write_timeout,
+		retention, created_time, modified_time
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7
+    ) ON CONFLICT (tenant_id) DO UPDATE SET
+        partition_duration = EXCLUDED.partition_duration,
+		timestamp_precision = EXCLUDED.timestamp_precision,
+		write_timeout = EXCLUDED.write_timeout,
+		retention = EXCLUDED.retention,
+		modified_time = EXCLUDED.modified_time`
+
+	_, err := r.dbpool.Exec(ctx, query, m.TenantId, int64(m.PartitionDuration.Seconds()), m.TimestampPrecision, int64(m.WriteTimeout.Seconds()), int64(m.Retention.Seconds()), m.CreatedTime, m.ModifiedTime)
+	if err != nil {
+		log.Println("TenantStorageConfigRepo|InsertOrUpdateByTenantId|err", err)
+		return err
+	}
+	return nil
+}
+
+func (r *TenantStorageConfigRepo) GetByTenantId(ctx context.Context, tenantId uint64) (*models.TenantStorageConfig, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT
+        tenant_id, partition_duration, timestamp_precision, write_timeout,
+		retention, created_time, modified_time
+    FROM
+        tenant_storage_configs
+    WHERE
+        tenant_id = $1`
+
+	m := new(models.TenantStorageConfig)
+	var partitionDuration, writeTimeout, retention int64
+	err := r.dbpool.QueryRow(ctx, query, tenantId).Scan(&m.TenantId, &partitionDuration, &m.TimestampPrecision, &writeTimeout, &retention, &m.CreatedTime, &m.ModifiedTime)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			log.Println("TenantStorageConfigRepo|GetByTenantId|err", err)
+			return nil, err
+		}
+	}
+	m.PartitionDuration = time.Duration(partitionDuration) * time.Second
+	m.WriteTimeout = time.Duration(writeTimeout) * time.Second
+	m.Retention = time.Duration(retention) * time.Second
+	return m, nil
+}
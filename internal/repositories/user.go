@@ -22,20 +22,27 @@ func NewUserRepo(dbpool *pgxpool.Pool) *UserRepo {
 	}
 }
 
-func (r *UserRepo) Insert(ctx context.Context, m *models.User) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	query := `
+// The query inserting a user, which `TenantRepo` also uses.
+const insertUserQuery = `
     INSERT INTO users (
         uuid, tenant_id, email, first_name, last_name, password_algorithm, password_hash, state,
 		role_id, timezone, created_time, modified_time, salt, was_email_activated,
-		pr_access_code, pr_expiry_time
+		pr_access_code, pr_expiry_time, email_verification_code, email_verification_expiry_time,
+		totp_secret, totp_enabled, totp_recovery_codes, totp_last_used_step
     ) VALUES (
-        $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+        $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
     )`
 
-	_, err := r.dbpool.Exec(ctx, query, m.Uuid, m.TenantId, m.Email, m.FirstName, m.LastName, m.PasswordAlgorithm, m.PasswordHash, m.State, m.RoleId, m.Timezone, m.CreatedTime, m.ModifiedTime, m.Salt, m.WasEmailActivated, m.PrAccessCode, m.PrExpiryTime)
+// Utility function which returns the arguments of `insertUserQuery`.
+func insertUserArgs(m *models.User) []interface{} {
+	return []interface{}{m.Uuid, m.TenantId, m.Email, m.FirstName, m.LastName, m.PasswordAlgorithm, m.PasswordHash, m.State, m.RoleId, m.Timezone, m.CreatedTime, m.ModifiedTime, m.Salt, m.WasEmailActivated, m.PrAccessCode, m.PrExpiryTime, m.EmailVerificationCode, m.EmailVerificationExpiryTime, m.TotpSecret, m.TotpEnabled, recoveryCodes(m), m.TotpLastUsedStep}
+}
+
+func (r *UserRepo) Insert(ctx context.Context, m *models.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.dbpool.Exec(ctx, insertUserQuery, insertUserArgs(m)...)
 	if err != nil {
 		log.Println("UserRepo|Insert|err", err)
 		return err
@@ -53,11 +60,12 @@ func (r *UserRepo) UpdateById(ctx context.Context, m *models.User) error {
     SET
         tenant_id = $1, email = $2, first_name = $3, last_name = $4, password_algorithm = $5, password_hash = $6, state = $7,
 		role_id = $8, timezone = $9, created_time = $10, modified_time = $11, salt = $12, was_email_activated = $13,
-		pr_access_code = $14, pr_expiry_time = $15
+		pr_access_code = $14, pr_expiry_time = $15, email_verification_code = $16, email_verification_expiry_time = $17,
+		totp_secret = $18, totp_enabled = $19, totp_recovery_codes = $20, totp_last_used_step = $21
     WHERE
-        id = $16`
+        id = $22`
 
-	err := r.dbpool.QueryRow(ctx, query).Scan(&m)
+	_, err := r.dbpool.Exec(ctx, query, m.TenantId, m.Email, m.FirstName, m.LastName, m.PasswordAlgorithm, m.PasswordHash, m.State, m.RoleId, m.Timezone, m.CreatedTime, m.ModifiedTime, m.Salt, m.WasEmailActivated, m.PrAccessCode, m.PrExpiryTime, m.EmailVerificationCode, m.EmailVerificationExpiryTime, m.TotpSecret, m.TotpEnabled, recoveryCodes(m), m.TotpLastUsedStep, m.Id)
 	if err != nil {
 		log.Println("UserRepo|UpdateById|err", err)
 		return err
@@ -65,7 +73,6 @@ func (r *UserRepo) UpdateById(ctx context.Context, m *models.User) error {
 	return nil
 }
 
-//
 func (r *UserRepo) UpdateByEmail(ctx context.Context, m *models.User) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -74,13 +81,14 @@ func (r *UserRepo) UpdateByEmail(ctx context.Context, m *models.User) error {
     UPDATE
         users
     SET
-        tenant_id = $1, email = $2, first_name = $3, last_name = $4, password_algorithm = $5, password_hash = $6, state = $7,
-		role_id = $8, timezone = $9, created_time = $10, modified_time = $11, salt = $12, was_email_activated = $13,
-		pr_access_code = $14, pr_expiry_time = $15
+        tenant_id = $1, first_name = $2, last_name = $3, password_algorithm = $4, password_hash = $5, state = $6,
+		role_id = $7, timezone = $8, created_time = $9, modified_time = $10, salt = $11, was_email_activated = $12,
+		pr_access_code = $13, pr_expiry_time = $14, email_verification_code = $15, email_verification_expiry_time = $16,
+		totp_secret = $17, totp_enabled = $18, totp_recovery_codes = $19, totp_last_used_step = $20
     WHERE
-        email = $2`
+        email = $21`
 
-	err := r.dbpool.QueryRow(ctx, query).Scan(&m)
+	_, err := r.dbpool.Exec(ctx, query, m.TenantId, m.FirstName, m.LastName, m.PasswordAlgorithm, m.PasswordHash, m.State, m.RoleId, m.Timezone, m.CreatedTime, m.ModifiedTime, m.Salt, m.WasEmailActivated, m.PrAccessCode, m.PrExpiryTime, m.EmailVerificationCode, m.EmailVerificationExpiryTime, m.TotpSecret, m.TotpEnabled, recoveryCodes(m), m.TotpLastUsedStep, m.Email)
 	if err != nil {
 		log.Println("UserRepo|UpdateByEmail|err", err)
 		return err
@@ -92,18 +100,18 @@ func (r *UserRepo) GetById(ctx context.Context, id uint64) (*models.User, error)
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	m := new(models.User)
-
 	query := `
     SELECT
         id, uuid, tenant_id, email, first_name, last_name, password_algorithm, password_hash, state,
-		role_id, timezone, created_time, modified_time, salt, was_email_activated, pr_access_code, pr_expiry_time
+		role_id, timezone, created_time, modified_time, salt, was_email_activated, pr_access_code, pr_expiry_time,
+		email_verification_code, email_verification_expiry_time, totp_secret, totp_enabled, totp_recovery_codes,
+		totp_last_used_step
     FROM
         users
     WHERE
         id = $1`
 
-	err := r.dbpool.QueryRow(ctx, query, id).Scan(&m)
+	m, err := scanUser(r.dbpool.QueryRow(ctx, query, id))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -119,33 +127,114 @@ func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*models.User,
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	m := new(models.User)
-
 	query := `
     SELECT
         id, uuid, tenant_id, email, first_name, last_name, password_algorithm,
 		password_hash, state, role_id, timezone, created_time, modified_time,
-		salt, was_email_activated, pr_access_code, pr_expiry_time
+		salt, was_email_activated, pr_access_code, pr_expiry_time,
+		email_verification_code, email_verification_expiry_time, totp_secret,
+		totp_enabled, totp_recovery_codes, totp_last_used_step
     FROM
         users
     WHERE
         email = $1`
 
-	err := r.dbpool.QueryRow(ctx, query, email).Scan(
-		&m.Id, &m.Uuid, &m.TenantId, &m.Email, &m.FirstName, &m.LastName,
-		&m.PasswordAlgorithm, &m.PasswordHash, &m.State, &m.RoleId, &m.Timezone,
-		&m.CreatedTime, &m.ModifiedTime, &m.Salt, &m.WasEmailActivated,
-		&m.PrAccessCode, &m.PrExpiryTime)
+	m, err := scanUser(r.dbpool.QueryRow(ctx, query, email))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		} else {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (r *UserRepo) GetByUuid(ctx context.Context, uid string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, email, first_name, last_name, password_algorithm,
+		password_hash, state, role_id, timezone, created_time, modified_time,
+		salt, was_email_activated, pr_access_code, pr_expiry_time,
+		email_verification_code, email_verification_expiry_time, totp_secret,
+		totp_enabled, totp_recovery_codes, totp_last_used_step
+    FROM
+        users
+    WHERE
+        uuid = $1`
+
+	m, err := scanUser(r.dbpool.QueryRow(ctx, query, uid))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
 		} else {
+			log.Println("UserRepo|GetByUuid|err", err)
 			return nil, err
 		}
 	}
 	return m, nil
 }
 
+func (r *UserRepo) ListByTenantId(ctx context.Context, tenantId uint64) ([]*models.User, error) {
+	var arr []*models.User
+
+	query := `
+    SELECT
+        id, uuid, tenant_id, email, first_name, last_name, password_algorithm,
+		password_hash, state, role_id, timezone, created_time, modified_time,
+		salt, was_email_activated, pr_access_code, pr_expiry_time,
+		email_verification_code, email_verification_expiry_time, totp_secret,
+		totp_enabled, totp_recovery_codes, totp_last_used_step
+    FROM
+        users
+    WHERE
+        tenant_id = $1
+    ORDER BY (id) ASC`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.dbpool.Query(ctx, query, tenantId)
+	if err != nil {
+		return arr, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanUser(rows)
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, m)
+	}
+
+	// Any errors encountered by rows.Next or rows.Scan will be returned here
+	if rows.Err() != nil {
+		return arr, rows.Err()
+	}
+
+	return arr, nil
+}
+
+func (r *UserRepo) CountByTenantId(ctx context.Context, tenantId uint64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var count int64
+
+	query := `SELECT COUNT(*) FROM users WHERE tenant_id = $1`
+
+	err := r.dbpool.QueryRow(ctx, query, tenantId).Scan(&count)
+	if err != nil {
+		log.Println("UserRepo|CountByTenantId|err", err)
+		return 0, err
+	}
+	return count, nil
+}
+
 func (r *UserRepo) CheckIfExistsById(ctx context.Context, id uint64) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -229,3 +318,27 @@ func (r *UserRepo) InsertOrUpdateByEmail(ctx context.Context, m *models.User) er
 	}
 	return r.UpdateByEmail(ctx, m)
 }
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	m := new(models.User)
+	err := row.Scan(
+		&m.Id, &m.Uuid, &m.TenantId, &m.Email, &m.FirstName, &m.LastName,
+		&m.PasswordAlgorithm, &m.PasswordHash, &m.State, &m.RoleId, &m.Timezone,
+		&m.CreatedTime, &m.ModifiedTime, &m.Salt, &m.WasEmailActivated,
+		&m.PrAccessCode, &m.PrExpiryTime, &m.EmailVerificationCode,
+		&m.EmailVerificationExpiryTime, &m.TotpSecret, &m.TotpEnabled,
+		&m.TotpRecoveryCodes, &m.TotpLastUsedStep)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// The `totp_recovery_codes` column cannot be null so make sure we never send
+// a nil slice.
+func recoveryCodes(m *models.User) []string {
+	if m.TotpRecoveryCodes == nil {
+		return []string{}
+	}
+	return m.TotpRecoveryCodes
+}
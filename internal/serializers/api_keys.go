@@ -0,0 +1,27 @@
+package serializers
+
+import (
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func ToAPIKeyRes(m *models.APIKey) *pb.APIKeyRes {
+	return &pb.APIKeyRes{
+		Uuid:         m.Uuid,
+		Name:         m.Name,
+		Prefix:       m.Prefix,
+		Scopes:       m.Scopes,
+		Revoked:      m.State == models.APIKeyRevokedState,
+		ExpiryTime:   ToOptionalTimestamp(m.ExpiryTime),
+		LastUsedTime: ToOptionalTimestamp(m.LastUsedTime),
+		CreatedTime:  ToTimestamp(m.CreatedTime),
+	}
+}
+
+func ToAPIKeyResList(arr []*models.APIKey) []*pb.APIKeyRes {
+	res := make([]*pb.APIKeyRes, 0, len(arr))
+	for _, m := range arr {
+		res = append(res, ToAPIKeyRes(m))
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package serializers
+
+import (
+	"github.com/nakabonne/tstorage"
+
+	"github.com/bartmika/mothership-server/internal/tsdb"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// ToDataPointRes converts the data point which was saved with the timestamp
+// precision into the response.
+func ToDataPointRes(point *tstorage.DataPoint, precision string) *pb.DataPointRes {
+	return &pb.DataPointRes{
+		Value:     point.Value,
+		Timestamp: ToTimestamp(tsdb.FromStorageTime(point.Timestamp, precision)),
+	}
+}
+
+func ToDataPointResList(points []*tstorage.DataPoint, precision string) []*pb.DataPointRes {
+	arr := make([]*pb.DataPointRes, 0, len(points))
+	for _, point := range points {
+		arr = append(arr, ToDataPointRes(point, precision))
+	}
+	return arr
+}
+
+func ToLabelResList(labels []tstorage.Label) []*pb.LabelRes {
+	arr := make([]*pb.LabelRes, 0, len(labels))
+	for _, l := range labels {
+		arr = append(arr, &pb.LabelRes{Name: l.Name, Value: l.Value})
+	}
+	return arr
+}
+
+// ToSeriesRes converts the series along with its data points which were saved
+// with the timestamp precision into the response.
+func ToSeriesRes(s *tsdb.Series, points []*tstorage.DataPoint, precision string) *pb.SeriesRes {
+	return &pb.SeriesRes{
+		Metric:     s.Metric,
+		Labels:     ToLabelResList(s.Labels),
+		DataPoints: ToDataPointResList(points, precision),
+	}
+}
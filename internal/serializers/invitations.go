@@ -0,0 +1,26 @@
+package serializers
+
+import (
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func ToInvitationRes(m *models.Invitation) *pb.InvitationRes {
+	return &pb.InvitationRes{
+		Uuid:        m.Uuid,
+		Email:       m.Email,
+		RoleId:      int32(m.RoleId),
+		Pending:     m.State == models.InvitationPendingState && !m.IsExpired(),
+		Accepted:    m.State == models.InvitationAcceptedState,
+		ExpiryTime:  ToTimestamp(m.ExpiryTime),
+		CreatedTime: ToTimestamp(m.CreatedTime),
+	}
+}
+
+func ToInvitationResList(arr []*models.Invitation) []*pb.InvitationRes {
+	res := make([]*pb.InvitationRes, 0, len(arr))
+	for _, m := range arr {
+		res = append(res, ToInvitationRes(m))
+	}
+	return res
+}
@@ -0,0 +1,55 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/promql"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+// ToQueryRes converts the result of a query into the response. Every sample
+// of a vector is returned as a series with a single data point.
+func ToQueryRes(r *promql.Result) *pb.QueryRes {
+	res := &pb.QueryRes{
+		ResultType: string(r.Type),
+		Result:     []*pb.QueryResultSeriesRes{},
+	}
+	switch r.Type {
+	case promql.ValueTypeScalar:
+		res.Scalar = toQueryPointRes(r.Scalar)
+	case promql.ValueTypeVector:
+		for _, sample := range r.Vector {
+			res.Result = append(res.Result, &pb.QueryResultSeriesRes{
+				Labels:     toQueryLabelResList(sample.Labels),
+				DataPoints: []*pb.DataPointRes{toQueryPointRes(sample.Point)},
+			})
+		}
+	case promql.ValueTypeMatrix:
+		for _, series := range r.Matrix {
+			points := make([]*pb.DataPointRes, 0, len(series.Points))
+			for _, p := range series.Points {
+				points = append(points, toQueryPointRes(p))
+			}
+			res.Result = append(res.Result, &pb.QueryResultSeriesRes{
+				Labels:     toQueryLabelResList(series.Labels),
+				DataPoints: points,
+			})
+		}
+	}
+	return res
+}
+
+func toQueryPointRes(p promql.Point) *pb.DataPointRes {
+	return &pb.DataPointRes{
+		Value:     p.V,
+		Timestamp: ToTimestamp(time.Unix(0, p.T).UTC()),
+	}
+}
+
+func toQueryLabelResList(labels promql.Labels) []*pb.LabelRes {
+	arr := make([]*pb.LabelRes, 0, len(labels))
+	for _, l := range labels {
+		arr = append(arr, &pb.LabelRes{Name: l.Name, Value: l.Value})
+	}
+	return arr
+}
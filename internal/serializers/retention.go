@@ -0,0 +1,39 @@
+package serializers
+
+import (
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func ToMetricRetentionRes(m *models.MetricRetentionPolicy) *pb.MetricRetentionRes {
+	return &pb.MetricRetentionRes{
+		Metric:           m.Metric,
+		RetentionSeconds: int64(m.Retention.Seconds()),
+	}
+}
+
+func ToMetricRetentionResList(arr []*models.MetricRetentionPolicy) []*pb.MetricRetentionRes {
+	res := make([]*pb.MetricRetentionRes, 0, len(arr))
+	for _, m := range arr {
+		res = append(res, ToMetricRetentionRes(m))
+	}
+	return res
+}
+
+// ToRetentionRes converts the retention of the tenant along with how much the
+// retention janitor removed since the server started. The `lastRunTime` is
+// left out if the janitor did not run yet.
+func ToRetentionRes(cfg *models.TenantStorageConfig, policies []*models.MetricRetentionPolicy, removedPartitions int64, reclaimedBytes int64, lastRunTime time.Time) *pb.RetentionRes {
+	res := &pb.RetentionRes{
+		RetentionSeconds:  int64(cfg.Retention.Seconds()),
+		Metrics:           ToMetricRetentionResList(policies),
+		RemovedPartitions: removedPartitions,
+		ReclaimedBytes:    reclaimedBytes,
+	}
+	if !lastRunTime.IsZero() {
+		res.LastRunTime = ToTimestamp(lastRunTime)
+	}
+	return res
+}
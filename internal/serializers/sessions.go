@@ -0,0 +1,25 @@
+package serializers
+
+import (
+	"github.com/bartmika/mothership-server/internal/session"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func ToSessionRes(s *session.Session, currentSessionUuid string) *pb.SessionRes {
+	return &pb.SessionRes{
+		Uuid:        s.Uuid,
+		ClientIp:    s.ClientIp,
+		UserAgent:   s.UserAgent,
+		CreatedTime: ToTimestamp(s.CreatedTime),
+		ExpiryTime:  ToTimestamp(s.ExpiryTime),
+		Current:     s.Uuid == currentSessionUuid,
+	}
+}
+
+func ToSessionResList(arr []*session.Session, currentSessionUuid string) []*pb.SessionRes {
+	res := make([]*pb.SessionRes, 0, len(arr))
+	for _, s := range arr {
+		res = append(res, ToSessionRes(s, currentSessionUuid))
+	}
+	return res
+}
@@ -0,0 +1,16 @@
+package serializers
+
+import (
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func ToStorageConfigRes(m *models.TenantStorageConfig) *pb.StorageConfigRes {
+	return &pb.StorageConfigRes{
+		PartitionDurationSeconds: int64(m.PartitionDuration.Seconds()),
+		TimestampPrecision:       m.TimestampPrecision,
+		WriteTimeoutSeconds:      int64(m.WriteTimeout.Seconds()),
+		RetentionSeconds:         int64(m.Retention.Seconds()),
+		ModifiedTime:             ToTimestamp(m.ModifiedTime),
+	}
+}
@@ -0,0 +1,18 @@
+package serializers
+
+import (
+	"github.com/bartmika/mothership-server/internal/models"
+	pb "github.com/bartmika/mothership-server/proto"
+)
+
+func ToTenantRes(m *models.Tenant) *pb.TenantRes {
+	return &pb.TenantRes{
+		Id:           m.Id,
+		Uuid:         m.Uuid,
+		Name:         m.Name,
+		Active:       m.State == models.TenantActiveState,
+		Timezone:     m.Timezone,
+		CreatedTime:  ToTimestamp(m.CreatedTime),
+		ModifiedTime: ToTimestamp(m.ModifiedTime),
+	}
+}
@@ -0,0 +1,39 @@
+package serializers
+
+import (
+	"time"
+
+	tspb "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// ToTimestamp converts the time into the protocol buffer timestamp.
+func ToTimestamp(t time.Time) *tspb.Timestamp {
+	return &tspb.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
+
+// ToOptionalTimestamp converts the time into the protocol buffer timestamp or
+// returns nil if there is no time.
+func ToOptionalTimestamp(t *time.Time) *tspb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return ToTimestamp(*t)
+}
+
+// FromOptionalTimestamp converts the protocol buffer timestamp into a time or
+// returns nil if the timestamp was not set.
+func FromOptionalTimestamp(ts *tspb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := FromTimestamp(ts)
+	return &t
+}
+
+// FromTimestamp converts the protocol buffer timestamp into a time.
+func FromTimestamp(ts *tspb.Timestamp) time.Time {
+	return time.Unix(ts.Seconds, int64(ts.Nanos)).UTC()
+}
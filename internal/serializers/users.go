@@ -5,4 +5,26 @@ import (
 	pb "github.com/bartmika/mothership-server/proto"
 )
 
-func ToRegisterModel()
+func ToUserRes(m *models.User) *pb.UserRes {
+	return &pb.UserRes{
+		Uuid:              m.Uuid,
+		Email:             m.Email,
+		FirstName:         m.FirstName,
+		LastName:          m.LastName,
+		RoleId:            int32(m.RoleId),
+		Active:            m.State == models.UserActiveState,
+		Timezone:          m.Timezone,
+		WasEmailActivated: m.WasEmailActivated,
+		TotpEnabled:       m.TotpEnabled,
+		CreatedTime:       ToTimestamp(m.CreatedTime),
+		ModifiedTime:      ToTimestamp(m.ModifiedTime),
+	}
+}
+
+func ToUserResList(arr []*models.User) []*pb.UserRes {
+	res := make([]*pb.UserRes, 0, len(arr))
+	for _, m := range arr {
+		res = append(res, ToUserRes(m))
+	}
+	return res
+}
@@ -0,0 +1,231 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// How often the expired sessions and token families get removed from memory.
+const memoryCleanupInterval = time.Minute
+
+type memorySession struct {
+	session    Session
+	expiryTime time.Time
+}
+
+type memoryTokenFamily struct {
+	family     TokenFamily
+	expiryTime time.Time
+}
+
+// MemorySessionManager keeps the sessions in the memory of the process. The
+// sessions are lost when the server restarts and are not shared between
+// servers, so only use this for single-node deployments and tests.
+type MemorySessionManager struct {
+	mu           sync.Mutex
+	sessions     map[string]*memorySession
+	families     map[string]*memoryTokenFamily
+	userSessions map[uint64]map[string]struct{}
+	doneCh       chan struct{}
+	closeOnce    sync.Once
+}
+
+func NewMemory() *MemorySessionManager {
+	sm := &MemorySessionManager{
+		sessions:     make(map[string]*memorySession),
+		families:     make(map[string]*memoryTokenFamily),
+		userSessions: make(map[uint64]map[string]struct{}),
+		doneCh:       make(chan struct{}),
+	}
+
+	// Periodically remove the expired entries so we do not grow forever.
+	go func() {
+		ticker := time.NewTicker(memoryCleanupInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sm.doneCh:
+				return
+			case <-ticker.C:
+				sm.removeExpired()
+			}
+		}
+	}()
+
+	return sm
+}
+
+func (sm *MemorySessionManager) SaveSession(ctx context.Context, s *Session, d time.Duration) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.sessions[s.Uuid] = &memorySession{session: copySession(s), expiryTime: time.Now().Add(d)}
+	index, ok := sm.userSessions[s.User.Id]
+	if !ok {
+		index = make(map[string]struct{})
+		sm.userSessions[s.User.Id] = index
+	}
+	index[s.Uuid] = struct{}{}
+	return nil
+}
+
+func (sm *MemorySessionManager) GetSession(ctx context.Context, sessionUuid string) (*Session, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	s := sm.getSession(sessionUuid)
+	if s == nil {
+		return nil, nil
+	}
+	cp := copySession(s)
+	return &cp, nil
+}
+
+func (sm *MemorySessionManager) DeleteSession(ctx context.Context, sessionUuid string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.deleteSession(sessionUuid)
+	return nil
+}
+
+func (sm *MemorySessionManager) ListSessionsByUserId(ctx context.Context, userId uint64) ([]*Session, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sessions := []*Session{}
+	for sessionUuid := range sm.userSessions[userId] {
+		s := sm.getSession(sessionUuid)
+		if s == nil {
+			continue
+		}
+		cp := copySession(s)
+		sessions = append(sessions, &cp)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedTime.Before(sessions[j].CreatedTime)
+	})
+	return sessions, nil
+}
+
+func (sm *MemorySessionManager) SaveTokenFamily(ctx context.Context, familyUuid string, family *TokenFamily, d time.Duration) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.families[familyUuid] = &memoryTokenFamily{family: *family, expiryTime: time.Now().Add(d)}
+	return nil
+}
+
+func (sm *MemorySessionManager) GetTokenFamily(ctx context.Context, familyUuid string) (*TokenFamily, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	family := sm.getTokenFamily(familyUuid)
+	if family == nil {
+		return nil, nil
+	}
+	cp := *family
+	return &cp, nil
+}
+
+func (sm *MemorySessionManager) RotateTokenFamily(ctx context.Context, familyUuid string, expectedSessionUuid string, family *TokenFamily, d time.Duration) (bool, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	current := sm.getTokenFamily(familyUuid)
+	if current == nil || current.SessionUuid != expectedSessionUuid {
+		return false, nil
+	}
+	sm.families[familyUuid] = &memoryTokenFamily{family: *family, expiryTime: time.Now().Add(d)}
+	return true, nil
+}
+
+func (sm *MemorySessionManager) DeleteTokenFamily(ctx context.Context, familyUuid string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	delete(sm.families, familyUuid)
+	return nil
+}
+
+func (sm *MemorySessionManager) Close() error {
+	sm.closeOnce.Do(func() {
+		close(sm.doneCh)
+	})
+	return nil
+}
+
+// getSession returns the session or nil if it does not exist or expired. The
+// lock must be held by the caller.
+func (sm *MemorySessionManager) getSession(sessionUuid string) *Session {
+	item, ok := sm.sessions[sessionUuid]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(item.expiryTime) {
+		sm.deleteSession(sessionUuid)
+		return nil
+	}
+	return &item.session
+}
+
+// deleteSession removes the session and its entry in the user index. The
+// lock must be held by the caller.
+func (sm *MemorySessionManager) deleteSession(sessionUuid string) {
+	item, ok := sm.sessions[sessionUuid]
+	if !ok {
+		return
+	}
+	delete(sm.sessions, sessionUuid)
+
+	userId := item.session.User.Id
+	delete(sm.userSessions[userId], sessionUuid)
+	if len(sm.userSessions[userId]) == 0 {
+		delete(sm.userSessions, userId)
+	}
+}
+
+// getTokenFamily returns the token family or nil if it does not exist or
+// expired. The lock must be held by the caller.
+func (sm *MemorySessionManager) getTokenFamily(familyUuid string) *TokenFamily {
+	item, ok := sm.families[familyUuid]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(item.expiryTime) {
+		delete(sm.families, familyUuid)
+		return nil
+	}
+	return &item.family
+}
+
+func (sm *MemorySessionManager) removeExpired() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	now := time.Now()
+	for sessionUuid, item := range sm.sessions {
+		if now.After(item.expiryTime) {
+			sm.deleteSession(sessionUuid)
+		}
+	}
+	for familyUuid, item := range sm.families {
+		if now.After(item.expiryTime) {
+			delete(sm.families, familyUuid)
+		}
+	}
+}
+
+// copySession returns a copy of the session (including the user) so callers
+// cannot change what we have stored.
+func copySession(s *Session) Session {
+	cp := *s
+	if s.User != nil {
+		user := *s.User
+		cp.User = &user
+	}
+	return cp
+}
@@ -0,0 +1,219 @@
+package session
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisOptions are the connection settings for our Redis server.
+type RedisOptions struct {
+	// Comma separated list of `host:port` addresses. More then one address
+	// will connect to a Redis cluster.
+	Address  string
+	Password string
+	DB       int
+	TLS      bool
+}
+
+// Sets the expiry of the key to the milliseconds unless the key already lives
+// longer than that.
+var extendExpiryScript = redis.NewScript(`
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < tonumber(ARGV[1]) then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+type RedisSessionManager struct {
+	rdb redis.UniversalClient
+}
+
+func NewRedis(opts *RedisOptions) *RedisSessionManager {
+	addrs := strings.Split(opts.Address, ",")
+	for i := range addrs {
+		addrs[i] = strings.TrimSpace(addrs[i])
+	}
+
+	var tlsConfig *tls.Config
+	if opts.TLS {
+		host, _, err := net.SplitHostPort(addrs[0])
+		if err != nil {
+			host = addrs[0]
+		}
+		tlsConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: host,
+		}
+	}
+
+	rdb := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:     addrs,
+		Password:  opts.Password,
+		DB:        opts.DB,
+		TLSConfig: tlsConfig,
+	})
+	return &RedisSessionManager{
+		rdb: rdb,
+	}
+}
+
+func (sm *RedisSessionManager) SaveSession(ctx context.Context, s *Session, d time.Duration) error {
+	sessionBin, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	// DEVELOPERS NOTE:
+	// Sessions live for different durations (impersonation sessions are
+	// shorter than the user's own) so the expiry of the index is only ever
+	// extended, otherwise a short session would drop the longer ones from it.
+	indexKey := userSessionsKey(s.User.Id)
+	pipe := sm.rdb.Pipeline()
+	pipe.Set(ctx, sessionKey(s.Uuid), sessionBin, d)
+	pipe.SAdd(ctx, indexKey, s.Uuid)
+	extendExpiryScript.Eval(ctx, pipe, []string{indexKey}, d.Milliseconds())
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (sm *RedisSessionManager) GetSession(ctx context.Context, sessionUuid string) (*Session, error) {
+	sessionString, err := sm.rdb.Get(ctx, sessionKey(sessionUuid)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s := &Session{}
+	err = json.Unmarshal([]byte(sessionString), s)
+	if err != nil {
+		return nil, err
+	}
+	if s.User == nil || s.User.Id == 0 {
+		return nil, nil
+	}
+	return s, nil
+}
+
+func (sm *RedisSessionManager) DeleteSession(ctx context.Context, sessionUuid string) error {
+	s, err := sm.GetSession(ctx, sessionUuid)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+	pipe := sm.rdb.Pipeline()
+	pipe.Del(ctx, sessionKey(sessionUuid))
+	pipe.SRem(ctx, userSessionsKey(s.User.Id), sessionUuid)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (sm *RedisSessionManager) ListSessionsByUserId(ctx context.Context, userId uint64) ([]*Session, error) {
+	indexKey := userSessionsKey(userId)
+	sessionUuids, err := sm.rdb.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []*Session{}
+	for _, sessionUuid := range sessionUuids {
+		s, err := sm.GetSession(ctx, sessionUuid)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			// The session expired so remove it from the index.
+			if err := sm.rdb.SRem(ctx, indexKey, sessionUuid).Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedTime.Before(sessions[j].CreatedTime)
+	})
+	return sessions, nil
+}
+
+func (sm *RedisSessionManager) SaveTokenFamily(ctx context.Context, familyUuid string, family *TokenFamily, d time.Duration) error {
+	familyBin, err := json.Marshal(family)
+	if err != nil {
+		return err
+	}
+	return sm.rdb.Set(ctx, tokenFamilyKey(familyUuid), familyBin, d).Err()
+}
+
+func (sm *RedisSessionManager) GetTokenFamily(ctx context.Context, familyUuid string) (*TokenFamily, error) {
+	familyString, err := sm.rdb.Get(ctx, tokenFamilyKey(familyUuid)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	family := &TokenFamily{}
+	err = json.Unmarshal([]byte(familyString), family)
+	if err != nil {
+		return nil, err
+	}
+	return family, nil
+}
+
+// rotateTokenFamilyScript swaps the session of a token family only if the
+// family still points to the expected session; this makes sure two callers
+// racing with the same `refresh token` cannot both succeed.
+var rotateTokenFamilyScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+local family = cjson.decode(current)
+if family.session_uuid ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+func (sm *RedisSessionManager) RotateTokenFamily(ctx context.Context, familyUuid string, expectedSessionUuid string, family *TokenFamily, d time.Duration) (bool, error) {
+	familyBin, err := json.Marshal(family)
+	if err != nil {
+		return false, err
+	}
+	res, err := rotateTokenFamilyScript.Run(ctx, sm.rdb, []string{tokenFamilyKey(familyUuid)}, expectedSessionUuid, familyBin, d.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func (sm *RedisSessionManager) DeleteTokenFamily(ctx context.Context, familyUuid string) error {
+	return sm.rdb.Del(ctx, tokenFamilyKey(familyUuid)).Err()
+}
+
+func (sm *RedisSessionManager) Close() error {
+	return sm.rdb.Close()
+}
+
+func sessionKey(sessionUuid string) string {
+	return "session:" + sessionUuid
+}
+
+func userSessionsKey(userId uint64) string {
+	return "user_sessions:" + strconv.FormatUint(userId, 10)
+}
+
+func tokenFamilyKey(familyUuid string) string {
+	return "token_family:" + familyUuid
+}
@@ -1,56 +1,60 @@
 package session
 
 import (
-	// "fmt"
 	"context"
-	"encoding/json"
-	"github.com/go-redis/redis/v8"
 	"time"
 
 	"github.com/bartmika/mothership-server/internal/models"
 )
 
-type SessionManager struct {
-	rdb *redis.Client
-}
+// Session is what we keep for every successful login. The `User` is cached,
+// without their secrets, so we do not need to hit the database on every
+// request and the remaining fields let the user see where they are logged in.
+type Session struct {
+	Uuid        string       `json:"uuid"`
+	FamilyUuid  string       `json:"family_uuid"`
+	User        *models.User `json:"user"`
+	ClientIp    string       `json:"client_ip"`
+	UserAgent   string       `json:"user_agent"`
+	CreatedTime time.Time    `json:"created_time"`
+	ExpiryTime  time.Time    `json:"expiry_time"`
 
-func New() *SessionManager {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379", // Default
-		Password: "",               // no password set
-		DB:       0,                // use default DB
-	})
-	return &SessionManager{
-		rdb: rdb,
-	}
+	// ReadOnly is set for the sessions root users get when they impersonate
+	// a tenant; these may only call the RPCs which do not change anything.
+	ReadOnly bool `json:"read_only,omitempty"`
 }
 
-func (sm *SessionManager) SaveUser(ctx context.Context, sessionUuid string, user *models.User, d time.Duration) error {
-	userBin, err := json.Marshal(user)
-	if err != nil {
-		return err
-	}
-	err = sm.rdb.Set(ctx, sessionUuid, userBin, d).Err()
-	if err != nil {
-		return err
-	}
-	return nil
+// TokenFamily tracks the chain of `refresh tokens` issued from a single login.
+// Only the `refresh token` belonging to the current session may be exchanged;
+// presenting any older one means the token was stolen and the whole family
+// gets revoked.
+type TokenFamily struct {
+	SessionUuid string `json:"session_uuid"`
+	UserId      uint64 `json:"user_id"`
 }
 
-func (sm *SessionManager) GetUser(ctx context.Context, sessionUuid string) (*models.User, error) {
-	userString, err := sm.rdb.Get(ctx, sessionUuid).Result()
-	if err == redis.Nil {
-		// fmt.Println("key2 does not exist")
-		return nil, nil
-	} else if err != nil {
-		return nil, err
-	} else {
-		userBin := []byte(userString)
-		user := &models.User{}
-		err = json.Unmarshal(userBin, user)
-		if user.Id == 0 {
-			return nil, err
-		}
-		return user, err
-	}
+// SessionManager stores our sessions and token families which expire after a
+// duration. Use `NewRedis` when running more than one server or `NewMemory`
+// for single-node deployments and tests.
+type SessionManager interface {
+	// SaveSession saves the session for the duration and adds it to the
+	// index of sessions belonging to the user.
+	SaveSession(ctx context.Context, s *Session, d time.Duration) error
+	// GetSession returns the session or nil if it does not exist or expired.
+	GetSession(ctx context.Context, sessionUuid string) (*Session, error)
+	// DeleteSession deletes the session and removes it from the index of the
+	// user it belongs to.
+	DeleteSession(ctx context.Context, sessionUuid string) error
+	// ListSessionsByUserId returns all the sessions of the user which have
+	// not expired, oldest first.
+	ListSessionsByUserId(ctx context.Context, userId uint64) ([]*Session, error)
+	SaveTokenFamily(ctx context.Context, familyUuid string, family *TokenFamily, d time.Duration) error
+	GetTokenFamily(ctx context.Context, familyUuid string) (*TokenFamily, error)
+	// RotateTokenFamily replaces the family's current session with the one
+	// in `family` if the family is still on `expectedSessionUuid`. Returns
+	// false if the family does not exist or has already been rotated.
+	RotateTokenFamily(ctx context.Context, familyUuid string, expectedSessionUuid string, family *TokenFamily, d time.Duration) (bool, error)
+	DeleteTokenFamily(ctx context.Context, familyUuid string) error
+	// Close releases the resources held by the session manager.
+	Close() error
 }
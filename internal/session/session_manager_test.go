@@ -0,0 +1,192 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// The Redis tests only run when this environment variable holds the address
+// of a Redis server they may write to.
+const testRedisAddressEnv = "MOTHERSHIP_TEST_REDIS_ADDRESS"
+
+// Utility function which returns every session manager the tests run against.
+func testSessionManagers(t *testing.T) map[string]SessionManager {
+	managers := map[string]SessionManager{"memory": NewMemory()}
+	if address := os.Getenv(testRedisAddressEnv); address != "" {
+		managers["redis"] = NewRedis(&RedisOptions{Address: address})
+	} else {
+		t.Logf("Set %v to also test the Redis session manager", testRedisAddressEnv)
+	}
+	t.Cleanup(func() {
+		for _, sm := range managers {
+			sm.Close()
+		}
+	})
+	return managers
+}
+
+// Utility function which returns a session of the user with ids which do
+// not clash with other tests using the same Redis server.
+func testSession(userId uint64, createdTime time.Time) *Session {
+	return &Session{
+		Uuid:        fmt.Sprintf("test-session-%d", rand.Int63()),
+		FamilyUuid:  fmt.Sprintf("test-family-%d", rand.Int63()),
+		User:        &models.User{Id: userId, TenantId: 1, Email: "alice@example.com"},
+		ClientIp:    "127.0.0.1",
+		UserAgent:   "test",
+		CreatedTime: createdTime.UTC().Truncate(time.Second),
+		ExpiryTime:  createdTime.UTC().Truncate(time.Second).Add(time.Hour),
+	}
+}
+
+// Utility function which returns a user id no other test uses.
+func testUserId() uint64 {
+	return uint64(rand.Int63())
+}
+
+func TestSessions(t *testing.T) {
+	for name, sm := range testSessionManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			userId := testUserId()
+			now := time.Now()
+			first := testSession(userId, now.Add(-time.Minute))
+			second := testSession(userId, now)
+			other := testSession(testUserId(), now)
+			for _, s := range []*Session{second, first, other} {
+				if err := sm.SaveSession(ctx, s, time.Hour); err != nil {
+					t.Fatalf("SaveSession failed: %v", err)
+				}
+			}
+
+			got, err := sm.GetSession(ctx, first.Uuid)
+			if err != nil {
+				t.Fatalf("GetSession failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, first) {
+				t.Errorf("GetSession = %+v, want %+v", got, first)
+			}
+
+			// Changing the session returned must not change the saved one.
+			got.User.Email = "mallory@example.com"
+			if again, _ := sm.GetSession(ctx, first.Uuid); again.User.Email != "alice@example.com" {
+				t.Errorf("GetSession returned the saved session instead of a copy")
+			}
+
+			sessions, err := sm.ListSessionsByUserId(ctx, userId)
+			if err != nil {
+				t.Fatalf("ListSessionsByUserId failed: %v", err)
+			}
+			if want := []*Session{first, second}; !reflect.DeepEqual(sessions, want) {
+				t.Errorf("ListSessionsByUserId = %+v, want oldest first %+v", sessions, want)
+			}
+
+			if err := sm.DeleteSession(ctx, first.Uuid); err != nil {
+				t.Fatalf("DeleteSession failed: %v", err)
+			}
+			if got, err := sm.GetSession(ctx, first.Uuid); err != nil || got != nil {
+				t.Errorf("GetSession of a deleted session = %+v, %v, want nil", got, err)
+			}
+			sessions, err = sm.ListSessionsByUserId(ctx, userId)
+			if err != nil || len(sessions) != 1 || sessions[0].Uuid != second.Uuid {
+				t.Errorf("ListSessionsByUserId after DeleteSession = %+v, %v, want only the second session", sessions, err)
+			}
+
+			// Deleting a session which does not exist does nothing.
+			if err := sm.DeleteSession(ctx, "test-missing"); err != nil {
+				t.Errorf("DeleteSession of a missing session failed: %v", err)
+			}
+			if got, err := sm.GetSession(ctx, "test-missing"); err != nil || got != nil {
+				t.Errorf("GetSession of a missing session = %+v, %v, want nil", got, err)
+			}
+		})
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	for name, sm := range testSessionManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			userId := testUserId()
+			short := testSession(userId, time.Now())
+			long := testSession(userId, time.Now())
+			if err := sm.SaveSession(ctx, short, 100*time.Millisecond); err != nil {
+				t.Fatalf("SaveSession failed: %v", err)
+			}
+			if err := sm.SaveSession(ctx, long, time.Hour); err != nil {
+				t.Fatalf("SaveSession failed: %v", err)
+			}
+			time.Sleep(200 * time.Millisecond)
+
+			if got, err := sm.GetSession(ctx, short.Uuid); err != nil || got != nil {
+				t.Errorf("GetSession of an expired session = %+v, %v, want nil", got, err)
+			}
+			sessions, err := sm.ListSessionsByUserId(ctx, userId)
+			if err != nil || len(sessions) != 1 || sessions[0].Uuid != long.Uuid {
+				t.Errorf("ListSessionsByUserId = %+v, %v, want only the session which did not expire", sessions, err)
+			}
+		})
+	}
+}
+
+func TestTokenFamilies(t *testing.T) {
+	for name, sm := range testSessionManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			familyUuid := fmt.Sprintf("test-family-%d", rand.Int63())
+			family := &TokenFamily{SessionUuid: "first", UserId: 1}
+			if err := sm.SaveTokenFamily(ctx, familyUuid, family, time.Hour); err != nil {
+				t.Fatalf("SaveTokenFamily failed: %v", err)
+			}
+			if got, err := sm.GetTokenFamily(ctx, familyUuid); err != nil || !reflect.DeepEqual(got, family) {
+				t.Errorf("GetTokenFamily = %+v, %v, want %+v", got, err, family)
+			}
+
+			// Only the first of two rotations from the same session wins.
+			rotated := &TokenFamily{SessionUuid: "second", UserId: 1}
+			if ok, err := sm.RotateTokenFamily(ctx, familyUuid, "first", rotated, time.Hour); err != nil || !ok {
+				t.Errorf("RotateTokenFamily = %v, %v, want true", ok, err)
+			}
+			if ok, err := sm.RotateTokenFamily(ctx, familyUuid, "first", &TokenFamily{SessionUuid: "third", UserId: 1}, time.Hour); err != nil || ok {
+				t.Errorf("RotateTokenFamily from a rotated session = %v, %v, want false", ok, err)
+			}
+			if got, err := sm.GetTokenFamily(ctx, familyUuid); err != nil || !reflect.DeepEqual(got, rotated) {
+				t.Errorf("GetTokenFamily after RotateTokenFamily = %+v, %v, want %+v", got, err, rotated)
+			}
+
+			if err := sm.DeleteTokenFamily(ctx, familyUuid); err != nil {
+				t.Fatalf("DeleteTokenFamily failed: %v", err)
+			}
+			if got, err := sm.GetTokenFamily(ctx, familyUuid); err != nil || got != nil {
+				t.Errorf("GetTokenFamily of a deleted family = %+v, %v, want nil", got, err)
+			}
+			if ok, err := sm.RotateTokenFamily(ctx, familyUuid, "second", rotated, time.Hour); err != nil || ok {
+				t.Errorf("RotateTokenFamily of a deleted family = %v, %v, want false", ok, err)
+			}
+		})
+	}
+}
+
+func TestTokenFamilyExpiry(t *testing.T) {
+	for name, sm := range testSessionManagers(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			familyUuid := fmt.Sprintf("test-family-%d", rand.Int63())
+			if err := sm.SaveTokenFamily(ctx, familyUuid, &TokenFamily{SessionUuid: "first", UserId: 1}, 100*time.Millisecond); err != nil {
+				t.Fatalf("SaveTokenFamily failed: %v", err)
+			}
+			time.Sleep(200 * time.Millisecond)
+
+			if got, err := sm.GetTokenFamily(ctx, familyUuid); err != nil || got != nil {
+				t.Errorf("GetTokenFamily of an expired family = %+v, %v, want nil", got, err)
+			}
+		})
+	}
+}
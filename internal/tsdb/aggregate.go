@@ -0,0 +1,191 @@
+package tsdb
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+)
+
+const (
+	AvgAggregation        = "avg"
+	MinAggregation        = "min"
+	MaxAggregation        = "max"
+	SumAggregation        = "sum"
+	CountAggregation      = "count"
+	FirstAggregation      = "first"
+	LastAggregation       = "last"
+	StddevAggregation     = "stddev"
+	PercentileAggregation = "percentile"
+)
+
+// Aggregations are all the ways the data points of a bucket can be combined
+// into a single value.
+var Aggregations = []string{AvgAggregation, MinAggregation, MaxAggregation, SumAggregation, CountAggregation, FirstAggregation, LastAggregation, StddevAggregation, PercentileAggregation}
+
+// Downsample groups the data points into buckets of the step and combines the
+// data points of every bucket with the aggregation. The returned data points
+// are timestamped with the start of their bucket and buckets without data
+// points are left out. The `percentile` (between 0 and 100) is only used by
+// the percentile aggregation.
+//
+// Buckets start at multiples of the step since the Unix epoch unless a
+// location is given, in which case they start at midnight of the location
+// (steps of whole days follow the calendar days of the location, including
+// the ones which are shorter or longer because of daylight saving time).
+func Downsample(points []*tstorage.DataPoint, precision string, step time.Duration, loc *time.Location, aggregation string, percentile float64) ([]*tstorage.DataPoint, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("step must be greater than zero")
+	}
+	if loc != nil && !IsAlignableStep(step) {
+		return nil, fmt.Errorf("step longer than a day must be whole days to align it to a timezone")
+	}
+	aggregate, err := aggregator(aggregation, percentile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first and last aggregations need the points in order.
+	sorted := make([]*tstorage.DataPoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	arr := []*tstorage.DataPoint{}
+	var bucket int64
+	values := []float64{}
+	for _, point := range sorted {
+		start := ToStorageTime(bucketStart(FromStorageTime(point.Timestamp, precision), step, loc), precision)
+		if len(values) > 0 && start != bucket {
+			arr = append(arr, &tstorage.DataPoint{Timestamp: bucket, Value: aggregate(values)})
+			values = values[:0]
+		}
+		bucket = start
+		values = append(values, point.Value)
+	}
+	if len(values) > 0 {
+		arr = append(arr, &tstorage.DataPoint{Timestamp: bucket, Value: aggregate(values)})
+	}
+	return arr, nil
+}
+
+// IsAlignableStep returns true if buckets of the step can be aligned to the
+// midnight of a timezone: the step is either shorter than a day or whole days.
+func IsAlignableStep(step time.Duration) bool {
+	return step < 24*time.Hour || step%(24*time.Hour) == 0
+}
+
+// Utility function which returns when the bucket the time falls in starts.
+// Steps aligned to the location must be alignable (see `IsAlignableStep`).
+func bucketStart(t time.Time, step time.Duration, loc *time.Location) time.Time {
+	if loc == nil {
+		ns := t.UnixNano()
+		offset := ns % int64(step)
+		if offset < 0 {
+			offset += int64(step)
+		}
+		return time.Unix(0, ns-offset).UTC()
+	}
+
+	t = t.In(loc)
+	day := 24 * time.Hour
+	if step%day == 0 {
+		// Count the calendar days since the epoch so the buckets follow the
+		// days of the location no matter how long they are.
+		days := int64(step / day)
+		n := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+		n -= ((n % days) + days) % days
+		return time.Date(1970, time.January, 1+int(n), 0, 0, 0, 0, loc)
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	since := t.Sub(midnight)
+	return midnight.Add(since - since%step)
+}
+
+// Utility function which returns the function combining the values of a
+// bucket for the aggregation.
+func aggregator(aggregation string, percentile float64) (func([]float64) float64, error) {
+	switch aggregation {
+	case AvgAggregation:
+		return func(values []float64) float64 {
+			return sum(values) / float64(len(values))
+		}, nil
+	case MinAggregation:
+		return func(values []float64) float64 {
+			m := values[0]
+			for _, v := range values[1:] {
+				m = math.Min(m, v)
+			}
+			return m
+		}, nil
+	case MaxAggregation:
+		return func(values []float64) float64 {
+			m := values[0]
+			for _, v := range values[1:] {
+				m = math.Max(m, v)
+			}
+			return m
+		}, nil
+	case SumAggregation:
+		return sum, nil
+	case CountAggregation:
+		return func(values []float64) float64 {
+			return float64(len(values))
+		}, nil
+	case FirstAggregation:
+		return func(values []float64) float64 {
+			return values[0]
+		}, nil
+	case LastAggregation:
+		return func(values []float64) float64 {
+			return values[len(values)-1]
+		}, nil
+	case StddevAggregation:
+		return stddev, nil
+	case PercentileAggregation:
+		if percentile < 0 || percentile > 100 {
+			return nil, fmt.Errorf("percentile must be between 0 and 100")
+		}
+		return func(values []float64) float64 {
+			return quantile(values, percentile/100)
+		}, nil
+	default:
+		return nil, fmt.Errorf("aggregation %q is not supported", aggregation)
+	}
+}
+
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// Utility function which returns the population standard deviation.
+func stddev(values []float64) float64 {
+	mean := sum(values) / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
+
+// Utility function which returns the quantile (between 0 and 1) of the values
+// by interpolating between the closest two values, the same way Prometheus
+// does.
+func quantile(values []float64, q float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := q * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
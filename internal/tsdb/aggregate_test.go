@@ -0,0 +1,163 @@
+package tsdb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+func TestBucketStart(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	tokyo := time.FixedZone("Tokyo", 9*60*60)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		step time.Duration
+		loc  *time.Location
+		want time.Time
+	}{
+		// Without a location buckets start at multiples of the step.
+		{name: "minute", t: time.Date(2021, 3, 14, 10, 42, 30, 0, time.UTC), step: time.Minute, want: time.Date(2021, 3, 14, 10, 42, 0, 0, time.UTC)},
+		{name: "15 minutes", t: time.Date(2021, 3, 14, 10, 42, 30, 0, time.UTC), step: 15 * time.Minute, want: time.Date(2021, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{name: "on the bucket", t: time.Date(2021, 3, 14, 10, 30, 0, 0, time.UTC), step: 15 * time.Minute, want: time.Date(2021, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{name: "day", t: time.Date(2021, 3, 14, 10, 42, 30, 0, time.UTC), step: 24 * time.Hour, want: time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "before the epoch", t: time.Date(1969, 12, 31, 23, 59, 30, 0, time.UTC), step: time.Minute, want: time.Date(1969, 12, 31, 23, 59, 0, 0, time.UTC)},
+		{name: "sub-second", t: time.Unix(100, 750000000), step: 500 * time.Millisecond, want: time.Unix(100, 500000000).UTC()},
+
+		// With a location buckets start at midnight of the location.
+		{name: "hour in Tokyo", t: time.Date(2021, 3, 14, 10, 42, 0, 0, time.UTC), step: time.Hour, loc: tokyo, want: time.Date(2021, 3, 14, 19, 0, 0, 0, tokyo)},
+		{name: "day in Tokyo", t: time.Date(2021, 3, 14, 16, 0, 0, 0, time.UTC), step: 24 * time.Hour, loc: tokyo, want: time.Date(2021, 3, 15, 0, 0, 0, 0, tokyo)},
+		{name: "6 hours in Toronto", t: time.Date(2021, 1, 14, 10, 0, 0, 0, toronto), step: 6 * time.Hour, loc: toronto, want: time.Date(2021, 1, 14, 6, 0, 0, 0, toronto)},
+		{name: "2 days in Toronto", t: time.Date(2021, 1, 2, 10, 0, 0, 0, toronto), step: 48 * time.Hour, loc: toronto, want: time.Date(2021, 1, 1, 0, 0, 0, 0, toronto)},
+
+		// Days follow the calendar when daylight saving time starts or ends.
+		{name: "day DST starts in Toronto", t: time.Date(2021, 3, 14, 23, 30, 0, 0, toronto), step: 24 * time.Hour, loc: toronto, want: time.Date(2021, 3, 14, 0, 0, 0, 0, toronto)},
+		{name: "day after DST starts in Toronto", t: time.Date(2021, 3, 15, 0, 30, 0, 0, toronto), step: 24 * time.Hour, loc: toronto, want: time.Date(2021, 3, 15, 0, 0, 0, 0, toronto)},
+		{name: "day DST ends in Toronto", t: time.Date(2021, 11, 7, 23, 30, 0, 0, toronto), step: 24 * time.Hour, loc: toronto, want: time.Date(2021, 11, 7, 0, 0, 0, 0, toronto)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bucketStart(tt.t, tt.step, tt.loc)
+			if !got.Equal(tt.want) {
+				t.Errorf("bucketStart(%v, %v) = %v, want %v", tt.t, tt.step, got, tt.want)
+			}
+		})
+	}
+}
+
+// Utility function which returns the data points with the values at the
+// timestamps, in seconds.
+func testPoints(pairs ...float64) []*tstorage.DataPoint {
+	arr := []*tstorage.DataPoint{}
+	for i := 0; i < len(pairs); i += 2 {
+		arr = append(arr, &tstorage.DataPoint{Timestamp: int64(pairs[i]), Value: pairs[i+1]})
+	}
+	return arr
+}
+
+func TestDownsample(t *testing.T) {
+	// Two buckets of a minute, the points of the first are out of order.
+	points := testPoints(
+		30, 4,
+		0, 1,
+		10, 2,
+		20, 3,
+		60, 10,
+		90, 20,
+	)
+	tests := []struct {
+		aggregation string
+		percentile  float64
+		want        []*tstorage.DataPoint
+	}{
+		{aggregation: AvgAggregation, want: testPoints(0, 2.5, 60, 15)},
+		{aggregation: MinAggregation, want: testPoints(0, 1, 60, 10)},
+		{aggregation: MaxAggregation, want: testPoints(0, 4, 60, 20)},
+		{aggregation: SumAggregation, want: testPoints(0, 10, 60, 30)},
+		{aggregation: CountAggregation, want: testPoints(0, 4, 60, 2)},
+		{aggregation: FirstAggregation, want: testPoints(0, 1, 60, 10)},
+		{aggregation: LastAggregation, want: testPoints(0, 4, 60, 20)},
+		{aggregation: StddevAggregation, want: testPoints(0, math.Sqrt(1.25), 60, 5)},
+		{aggregation: PercentileAggregation, percentile: 50, want: testPoints(0, 2.5, 60, 15)},
+		{aggregation: PercentileAggregation, percentile: 100, want: testPoints(0, 4, 60, 20)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			got, err := Downsample(points, models.SecondsPrecision, time.Minute, nil, tt.aggregation, tt.percentile)
+			if err != nil {
+				t.Fatalf("Downsample failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Downsample = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The data points given are left as they were.
+	if points[0].Timestamp != 30 {
+		t.Errorf("Downsample sorted the data points it was given")
+	}
+}
+
+func TestDownsamplePrecision(t *testing.T) {
+	points := []*tstorage.DataPoint{{Timestamp: 1250, Value: 1}, {Timestamp: 1750, Value: 3}, {Timestamp: 2100, Value: 5}}
+	got, err := Downsample(points, models.MillisecondsPrecision, time.Second, nil, AvgAggregation, 0)
+	if err != nil {
+		t.Fatalf("Downsample failed: %v", err)
+	}
+	if want := []*tstorage.DataPoint{{Timestamp: 1000, Value: 2}, {Timestamp: 2000, Value: 5}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Downsample = %v, want %v", got, want)
+	}
+}
+
+func TestIsAlignableStep(t *testing.T) {
+	tests := []struct {
+		step time.Duration
+		want bool
+	}{
+		{step: time.Minute, want: true},
+		{step: 7 * time.Hour, want: true},
+		{step: 24 * time.Hour, want: true},
+		{step: 48 * time.Hour, want: true},
+		{step: 36 * time.Hour, want: false},
+		{step: 24*time.Hour + time.Second, want: false},
+	}
+	for _, tt := range tests {
+		if got := IsAlignableStep(tt.step); got != tt.want {
+			t.Errorf("IsAlignableStep(%v) = %v, want %v", tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestDownsampleErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        time.Duration
+		loc         *time.Location
+		aggregation string
+		percentile  float64
+	}{
+		{name: "zero step", step: 0, aggregation: AvgAggregation},
+		{name: "negative step", step: -time.Minute, aggregation: AvgAggregation},
+		{name: "unknown aggregation", step: time.Minute, aggregation: "median"},
+		{name: "percentile below 0", step: time.Minute, aggregation: PercentileAggregation, percentile: -1},
+		{name: "percentile above 100", step: time.Minute, aggregation: PercentileAggregation, percentile: 101},
+		{name: "day and a half in a timezone", step: 36 * time.Hour, loc: time.UTC, aggregation: AvgAggregation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Downsample(testPoints(0, 1), models.SecondsPrecision, tt.step, tt.loc, tt.aggregation, tt.percentile); err == nil {
+				t.Errorf("Downsample did not fail")
+			}
+		})
+	}
+}
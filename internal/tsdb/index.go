@@ -0,0 +1,278 @@
+package tsdb
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nakabonne/tstorage"
+)
+
+// The file in the storage directory every new series is appended to.
+const indexLogFile = "series.log"
+
+// The same limits `tstorage` applies to the labels it saves.
+const (
+	maxLabelNameLen  = 256
+	maxLabelValueLen = 16 * 1024
+)
+
+// IndexEntry is a series known to the index along with the range of the
+// timestamps of its data points.
+type IndexEntry struct {
+	Series
+	MinTimestamp int64
+	MaxTimestamp int64
+}
+
+// Index keeps track of every series saved in a storage, which `tstorage` does
+// not tell us.
+//
+// DEVELOPERS NOTE:
+// The index is rebuilt whenever the storage is opened from the meta data of
+// the partitions saved on disk. Data points which were not written to disk
+// yet (and are recovered by `tstorage` from its write ahead log) are not in
+// the meta data, therefore every new series is also appended to a log file
+// which is read back when the index is opened.
+type Index struct {
+	mu      sync.RWMutex
+	entries map[string]*IndexEntry
+	log     *os.File
+}
+
+// indexLogEntry is a line of the index log file.
+type indexLogEntry struct {
+	Metric    string           `json:"metric"`
+	Labels    []tstorage.Label `json:"labels"`
+	Timestamp int64            `json:"timestamp"`
+}
+
+// OpenIndex builds the index of the storage saved in the directory.
+func OpenIndex(dataPath string) (*Index, error) {
+	ix := &Index{entries: make(map[string]*IndexEntry)}
+
+	err := walkPartitions(dataPath, func(path string, meta *partitionMeta) {
+		for name, m := range meta.Metrics {
+			s := ParseMetricName(name)
+			ix.add(s.Metric, s.Labels, m.MinTimestamp)
+			ix.add(s.Metric, s.Labels, m.MaxTimestamp)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logPath := filepath.Join(dataPath, indexLogFile)
+	if err := ix.readLog(logPath); err != nil {
+		return nil, err
+	}
+
+	// Rewrite the log with what we know so it does not grow forever.
+	if err := os.MkdirAll(dataPath, 0755); err != nil {
+		return nil, err
+	}
+	if err := ix.writeLog(logPath); err != nil {
+		return nil, err
+	}
+	ix.log, err = os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// ResetIndex forgets the series which are only known from the index log file
+// of the storage saved in the directory. Use this when every data point is
+// known to be saved to disk, for example after partitions were removed, so
+// the series which no longer have data points are dropped from the index.
+func ResetIndex(dataPath string) error {
+	err := os.Remove(filepath.Join(dataPath, indexLogFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Add records the series of the rows and the timestamps of their data points.
+func (ix *Index) Add(rows []tstorage.Row) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	w := bufio.NewWriter(ix.log)
+	for _, row := range rows {
+		labels := normalizeLabels(row.Labels)
+		if !ix.add(row.Metric, labels, row.Timestamp) {
+			continue
+		}
+		b, err := json.Marshal(&indexLogEntry{Metric: row.Metric, Labels: labels, Timestamp: row.Timestamp})
+		if err != nil {
+			return err
+		}
+		w.Write(b)
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+// Find returns the series which have data points between the `start`
+// (inclusive) and `end` (exclusive) and for which the function returns true,
+// ordered by series.
+func (ix *Index) Find(start int64, end int64, match func(e *IndexEntry) bool) []*IndexEntry {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	arr := []*IndexEntry{}
+	for _, e := range ix.entries {
+		if e.MaxTimestamp < start || e.MinTimestamp >= end {
+			continue
+		}
+		if match != nil && !match(e) {
+			continue
+		}
+		// Copy the labels as `tstorage` sorts the labels it is given.
+		c := *e
+		c.Labels = append([]tstorage.Label{}, e.Labels...)
+		arr = append(arr, &c)
+	}
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].String() < arr[j].String()
+	})
+	return arr
+}
+
+// Get returns the series with exactly the metric and labels or nil if the
+// series has no data points.
+func (ix *Index) Get(metric string, labels []tstorage.Label) *IndexEntry {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	e, ok := ix.entries[seriesKey(metric, normalizeLabels(labels))]
+	if !ok {
+		return nil
+	}
+	c := *e
+	c.Labels = append([]tstorage.Label{}, e.Labels...)
+	return &c
+}
+
+// Close closes the index log file.
+func (ix *Index) Close() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	return ix.log.Close()
+}
+
+// Utility function which records the timestamp of the series and returns
+// true if the series was not known before. The labels must be normalized.
+func (ix *Index) add(metric string, labels []tstorage.Label, timestamp int64) bool {
+	key := seriesKey(metric, labels)
+	e, ok := ix.entries[key]
+	if !ok {
+		ix.entries[key] = &IndexEntry{
+			Series:       Series{Metric: metric, Labels: labels},
+			MinTimestamp: timestamp,
+			MaxTimestamp: timestamp,
+		}
+		return true
+	}
+	if timestamp < e.MinTimestamp {
+		e.MinTimestamp = timestamp
+	}
+	if timestamp > e.MaxTimestamp {
+		e.MaxTimestamp = timestamp
+	}
+	return false
+}
+
+func (ix *Index) readLog(logPath string) error {
+	f, err := os.Open(logPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		e := &indexLogEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			// The last line is cut short if the server crashed while
+			// writing it.
+			continue
+		}
+		ix.add(e.Metric, normalizeLabels(e.Labels), e.Timestamp)
+	}
+	return scanner.Err()
+}
+
+// Utility function which saves every series of the index into the log file.
+func (ix *Index) writeLog(logPath string) error {
+	tmpPath := logPath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range ix.entries {
+		b, err := json.Marshal(&indexLogEntry{Metric: e.Metric, Labels: e.Labels, Timestamp: e.MaxTimestamp})
+		if err != nil {
+			f.Close()
+			return err
+		}
+		w.Write(b)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, logPath)
+}
+
+// Utility function which returns the labels the same way `tstorage` saves
+// them: sorted by name, without labels missing their name or value and cut to
+// the longest name and value allowed.
+func normalizeLabels(labels []tstorage.Label) []tstorage.Label {
+	arr := make([]tstorage.Label, 0, len(labels))
+	for _, l := range labels {
+		if l.Name == "" || l.Value == "" {
+			continue
+		}
+		if len(l.Name) > maxLabelNameLen {
+			l.Name = l.Name[:maxLabelNameLen]
+		}
+		if len(l.Value) > maxLabelValueLen {
+			l.Value = l.Value[:maxLabelValueLen]
+		}
+		arr = append(arr, l)
+	}
+	sort.SliceStable(arr, func(i, j int) bool {
+		return arr[i].Name < arr[j].Name
+	})
+	return arr
+}
+
+// Utility function which returns the key identifying the series. The labels
+// must be normalized.
+func seriesKey(metric string, labels []tstorage.Label) string {
+	var b strings.Builder
+	b.WriteString(metric)
+	for _, l := range labels {
+		b.WriteByte(0)
+		b.WriteString(l.Name)
+		b.WriteByte(0)
+		b.WriteString(l.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package tsdb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked is returned by `Lock` if another process holds the lock of the
+// data directory.
+var ErrLocked = errors.New("data directory is used by another process")
+
+// The file in the data directory the lock is taken on.
+const lockFileName = ".lock"
+
+// DirLock is the lock of a data directory held by this process.
+type DirLock struct {
+	f *os.File
+}
+
+// Lock locks the data directory, creating it if it does not exist, so no
+// other process can use it until it is unlocked or this process exits.
+// Returns `ErrLocked` if another process holds the lock.
+func Lock(dataPath string) (*DirLock, error) {
+	if err := os.MkdirAll(dataPath, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dataPath, lockFileName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &DirLock{f: f}, nil
+}
+
+// Unlock releases the lock so other processes may use the data directory.
+func (l *DirLock) Unlock() error {
+	return l.f.Close()
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package tsdb
+
+import (
+	"os"
+	"syscall"
+)
+
+// Utility function which takes an exclusive lock on the file without waiting
+// for it. The lock is released when the file is closed.
+func lockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return ErrLocked
+	}
+	return err
+}
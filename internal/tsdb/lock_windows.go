@@ -0,0 +1,9 @@
+package tsdb
+
+import "os"
+
+// DEVELOPERS NOTE:
+// Windows does not support `flock` so the data directory is not locked.
+func lockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package tsdb
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// The label name which matches the metric name of a series, the same as in
+// Prometheus.
+const MetricNameLabel = "__name__"
+
+const (
+	MatchEqual     = "="
+	MatchNotEqual  = "!="
+	MatchRegexp    = "=~"
+	MatchNotRegexp = "!~"
+)
+
+// Matcher selects the series by the value of one of their labels. A series
+// without the label is treated as having the label with an empty value.
+type Matcher struct {
+	Name  string
+	Type  string
+	Value string
+	re    *regexp.Regexp
+}
+
+// NewMatcher returns the matcher of the type. Regular expressions have to
+// match the whole value, the same as in Prometheus.
+func NewMatcher(matchType string, name string, value string) (*Matcher, error) {
+	m := &Matcher{Name: name, Type: matchType, Value: value}
+	switch matchType {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, err
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("match type %q is not supported", matchType)
+	}
+	return m, nil
+}
+
+// Matches returns true if the value of the label matches.
+func (m *Matcher) Matches(value string) bool {
+	switch m.Type {
+	case MatchEqual:
+		return value == m.Value
+	case MatchNotEqual:
+		return value != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(value)
+	case MatchNotRegexp:
+		return !m.re.MatchString(value)
+	}
+	return false
+}
+
+// String returns the matcher in the same format Prometheus uses, for example
+// `room=~"kitchen|garage"`.
+func (m *Matcher) String() string {
+	return fmt.Sprintf("%s%s%q", m.Name, m.Type, m.Value)
+}
+
+// MatchSeries returns true if the series matches every matcher.
+func MatchSeries(s *Series, matchers []*Matcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(s.labelValue(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Utility function which returns the value of the label of the series or an
+// empty value if the series does not have it.
+func (s *Series) labelValue(name string) string {
+	if name == MetricNameLabel {
+		return s.Metric
+	}
+	for _, l := range s.Labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package tsdb
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/nakabonne/tstorage"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// MigratePrecision copies every data point saved in the `dataPath` directory
+// with the `from` settings into a new storage in the `newPath` directory which
+// is opened with the `to` settings, converting the timestamps to the new
+// precision. The storage in `dataPath` must not be open by anybody else.
+// Returns the number of data points copied.
+func MigratePrecision(dataPath string, newPath string, from *models.TenantStorageConfig, to *models.TenantStorageConfig) (int, error) {
+	// Open and close the old storage once so any data points left in its
+	// write ahead log are flushed to disk where we can find them.
+	src, err := NewStorage(dataPath, from)
+	if err != nil {
+		return 0, err
+	}
+	if err := src.Close(); err != nil {
+		return 0, err
+	}
+
+	series, err := ListSeries(dataPath)
+	if err != nil {
+		return 0, err
+	}
+	partitions, err := ListPartitions(dataPath)
+	if err != nil {
+		return 0, err
+	}
+
+	src, err = NewStorage(dataPath, from)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	dst, err := NewStorage(newPath, to)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := copyPoints(src, dst, series, partitions, from, to)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	return count, err
+}
+
+// copyPoints function copies the data points one partition duration at a time.
+//
+// DEVELOPERS NOTE:
+// `tstorage` drops any data point which is older than the partitions it is
+// still writing to, therefore the data points must be inserted oldest first
+// across all the series and not one series after the other.
+func copyPoints(src tstorage.Storage, dst tstorage.Storage, series []*Series, partitions []*Partition, from *models.TenantStorageConfig, to *models.TenantStorageConfig) (int, error) {
+	if len(series) == 0 || len(partitions) == 0 {
+		return 0, nil
+	}
+
+	minTimestamp, maxTimestamp := partitions[0].MinTimestamp, partitions[0].MaxTimestamp
+	for _, p := range partitions {
+		if p.MinTimestamp < minTimestamp {
+			minTimestamp = p.MinTimestamp
+		}
+		if p.MaxTimestamp > maxTimestamp {
+			maxTimestamp = p.MaxTimestamp
+		}
+	}
+	window := ToStorageDuration(from.PartitionDuration, from.TimestampPrecision)
+
+	count := 0
+	for start := minTimestamp; start <= maxTimestamp; start += window {
+		rows := []tstorage.Row{}
+		for _, s := range series {
+			points, err := src.Select(s.Metric, s.Labels, start, start+window)
+			if errors.Is(err, tstorage.ErrNoDataPoints) {
+				continue
+			}
+			if err != nil {
+				return count, err
+			}
+			for _, point := range points {
+				t := FromStorageTime(point.Timestamp, from.TimestampPrecision)
+				rows = append(rows, NewRow(s.Metric, s.Labels, t, point.Value, to.TimestampPrecision))
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].Timestamp < rows[j].Timestamp
+		})
+		if err := dst.InsertRows(rows); err != nil {
+			return count, err
+		}
+		count += len(rows)
+	}
+	return count, nil
+}
@@ -0,0 +1,107 @@
+package tsdb
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// Utility function which returns the storage settings with the precision.
+func testStorageConfig(precision string) *models.TenantStorageConfig {
+	cfg := models.DefaultTenantStorageConfig(1)
+	cfg.PartitionDuration = time.Hour
+	cfg.TimestampPrecision = precision
+	return cfg
+}
+
+func TestMigratePrecision(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		// The timestamps of the data points after the migration.
+		want []time.Time
+	}{
+		{
+			from: models.SecondsPrecision,
+			to:   models.MillisecondsPrecision,
+			want: []time.Time{time.Unix(1600000000, 0), time.Unix(1600003600, 0), time.Unix(1600007200, 0)},
+		},
+		{
+			from: models.MillisecondsPrecision,
+			to:   models.SecondsPrecision,
+			want: []time.Time{time.Unix(1600000000, 0), time.Unix(1600003600, 0), time.Unix(1600007200, 0)},
+		},
+		{
+			from: models.MillisecondsPrecision,
+			to:   models.NanosecondsPrecision,
+			want: []time.Time{time.Unix(1600000000, 500000000), time.Unix(1600003600, 500000000), time.Unix(1600007200, 500000000)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			dataPath := filepath.Join(t.TempDir(), "data")
+			newPath := filepath.Join(t.TempDir(), "new")
+			from, to := testStorageConfig(tt.from), testStorageConfig(tt.to)
+
+			// Save a data point of two series in three partitions.
+			src, err := NewStorage(dataPath, from)
+			if err != nil {
+				t.Fatalf("NewStorage failed: %v", err)
+			}
+			labels := []tstorage.Label{{Name: "room", Value: "kitchen"}}
+			for i := 0; i < 3; i++ {
+				at := time.Unix(1600000000, 500000000).Add(time.Duration(i) * time.Hour)
+				rows := []tstorage.Row{
+					NewRow("temperature", labels, at, float64(i), from.TimestampPrecision),
+					NewRow("humidity", nil, at, float64(10+i), from.TimestampPrecision),
+				}
+				if err := src.InsertRows(rows); err != nil {
+					t.Fatalf("InsertRows failed: %v", err)
+				}
+			}
+			if err := src.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			count, err := MigratePrecision(dataPath, newPath, from, to)
+			if err != nil {
+				t.Fatalf("MigratePrecision failed: %v", err)
+			}
+			if count != 6 {
+				t.Errorf("MigratePrecision copied %v data points, want 6", count)
+			}
+
+			dst, err := NewStorage(newPath, to)
+			if err != nil {
+				t.Fatalf("NewStorage failed: %v", err)
+			}
+			defer dst.Close()
+			points, err := dst.Select("temperature", labels, 0, ToStorageTime(time.Unix(1700000000, 0), tt.to))
+			if err != nil {
+				t.Fatalf("Select failed: %v", err)
+			}
+			got := []time.Time{}
+			for i, p := range points {
+				got = append(got, FromStorageTime(p.Timestamp, tt.to).Local())
+				if p.Value != float64(i) {
+					t.Errorf("data point #%v = %v, want %v", i, p.Value, i)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("timestamps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratePrecisionEmpty(t *testing.T) {
+	count, err := MigratePrecision(t.TempDir(), filepath.Join(t.TempDir(), "new"), testStorageConfig(models.SecondsPrecision), testStorageConfig(models.MillisecondsPrecision))
+	if err != nil || count != 0 {
+		t.Errorf("MigratePrecision of an empty storage = %v, %v, want 0, nil", count, err)
+	}
+}
@@ -0,0 +1,76 @@
+package tsdb
+
+import (
+	"math"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// ToStorageTime converts the time into the number `tstorage` saves for the
+// precision. Anything finer than the precision is truncated. Use
+// `IsStorageTime` first for times which may not fit.
+func ToStorageTime(t time.Time, precision string) int64 {
+	switch precision {
+	case models.MillisecondsPrecision:
+		return t.Unix()*1e3 + int64(t.Nanosecond())/1e6
+	case models.MicrosecondsPrecision:
+		return t.Unix()*1e6 + int64(t.Nanosecond())/1e3
+	case models.NanosecondsPrecision:
+		return t.UnixNano()
+	default:
+		return t.Unix()
+	}
+}
+
+// FromStorageTime converts the number `tstorage` saved for the precision back
+// into the time.
+func FromStorageTime(v int64, precision string) time.Time {
+	switch precision {
+	case models.MillisecondsPrecision:
+		return time.Unix(v/1e3, v%1e3*1e6).UTC()
+	case models.MicrosecondsPrecision:
+		return time.Unix(v/1e6, v%1e6*1e3).UTC()
+	case models.NanosecondsPrecision:
+		return time.Unix(0, v).UTC()
+	default:
+		return time.Unix(v, 0).UTC()
+	}
+}
+
+// IsStorageTime returns true if the time can be saved with the precision, in
+// other words if `ToStorageTime` does not overflow. With nanoseconds only the
+// times between the years 1678 and 2262 fit.
+func IsStorageTime(t time.Time, precision string) bool {
+	var perSecond int64
+	switch precision {
+	case models.MillisecondsPrecision:
+		perSecond = 1e3
+	case models.MicrosecondsPrecision:
+		perSecond = 1e6
+	case models.NanosecondsPrecision:
+		perSecond = 1e9
+	default:
+		return true
+	}
+	sec := t.Unix()
+	if sec < 0 {
+		return sec > math.MinInt64/perSecond
+	}
+	return sec < math.MaxInt64/perSecond
+}
+
+// ToStorageDuration converts the duration into the number of units of the
+// precision.
+func ToStorageDuration(d time.Duration, precision string) int64 {
+	switch precision {
+	case models.MillisecondsPrecision:
+		return int64(d / time.Millisecond)
+	case models.MicrosecondsPrecision:
+		return int64(d / time.Microsecond)
+	case models.NanosecondsPrecision:
+		return int64(d)
+	default:
+		return int64(d / time.Second)
+	}
+}
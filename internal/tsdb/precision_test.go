@@ -0,0 +1,141 @@
+package tsdb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// The time the precision tests convert, which has every sub-second digit set.
+var precisionTestTime = time.Unix(1600000000, 123456789).UTC()
+
+func TestToStorageTime(t *testing.T) {
+	tests := []struct {
+		precision string
+		t         time.Time
+		want      int64
+	}{
+		{precision: models.SecondsPrecision, t: precisionTestTime, want: 1600000000},
+		{precision: models.MillisecondsPrecision, t: precisionTestTime, want: 1600000000123},
+		{precision: models.MicrosecondsPrecision, t: precisionTestTime, want: 1600000000123456},
+		{precision: models.NanosecondsPrecision, t: precisionTestTime, want: 1600000000123456789},
+		{precision: models.SecondsPrecision, t: time.Unix(0, 0), want: 0},
+		{precision: models.MillisecondsPrecision, t: time.Unix(0, 0), want: 0},
+		{precision: models.SecondsPrecision, t: time.Unix(-1, 0), want: -1},
+		{precision: models.MillisecondsPrecision, t: time.Unix(-1, 0), want: -1000},
+		{precision: models.MillisecondsPrecision, t: time.Unix(-1, 500000000), want: -500},
+
+		// Times which do not fit in Unix nanoseconds.
+		{precision: models.SecondsPrecision, t: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: 253402214400},
+		{precision: models.MillisecondsPrecision, t: time.Date(9999, 12, 31, 0, 0, 0, 1000000, time.UTC), want: 253402214400001},
+		{precision: models.MicrosecondsPrecision, t: time.Date(9999, 12, 31, 0, 0, 0, 1000, time.UTC), want: 253402214400000001},
+		{precision: models.MillisecondsPrecision, t: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), want: -62135596800000},
+		{precision: models.MicrosecondsPrecision, t: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), want: -62135596800000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.precision+" "+tt.t.String(), func(t *testing.T) {
+			if got := ToStorageTime(tt.t, tt.precision); got != tt.want {
+				t.Errorf("ToStorageTime(%v, %q) = %v, want %v", tt.t, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromStorageTime(t *testing.T) {
+	tests := []struct {
+		precision string
+		want      time.Time
+	}{
+		{precision: models.SecondsPrecision, want: time.Unix(1600000000, 0).UTC()},
+		{precision: models.MillisecondsPrecision, want: time.Unix(1600000000, 123000000).UTC()},
+		{precision: models.MicrosecondsPrecision, want: time.Unix(1600000000, 123456000).UTC()},
+		{precision: models.NanosecondsPrecision, want: precisionTestTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.precision, func(t *testing.T) {
+			// Converting back and forth truncates to the precision.
+			got := FromStorageTime(ToStorageTime(precisionTestTime, tt.precision), tt.precision)
+			if !got.Equal(tt.want) {
+				t.Errorf("FromStorageTime(ToStorageTime(%v, %q)) = %v, want %v", precisionTestTime, tt.precision, got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("FromStorageTime returned a time in %v, want UTC", got.Location())
+			}
+		})
+	}
+}
+
+func TestFromStorageTimeRange(t *testing.T) {
+	// Every time the precisions coarser than nanoseconds save, far outside
+	// the years 1678 to 2262, converts back to itself.
+	times := []time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1000, 6, 15, 12, 30, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(3000, 6, 15, 12, 30, 0, 0, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+	}
+	for _, precision := range []string{models.SecondsPrecision, models.MillisecondsPrecision, models.MicrosecondsPrecision} {
+		for _, want := range times {
+			if !IsStorageTime(want, precision) {
+				t.Errorf("IsStorageTime(%v, %q) = false, want true", want, precision)
+				continue
+			}
+			if got := FromStorageTime(ToStorageTime(want, precision), precision); !got.Equal(want) {
+				t.Errorf("FromStorageTime(ToStorageTime(%v, %q)) = %v", want, precision, got)
+			}
+		}
+	}
+}
+
+func TestIsStorageTime(t *testing.T) {
+	tests := []struct {
+		precision string
+		t         time.Time
+		want      bool
+	}{
+		{precision: models.NanosecondsPrecision, t: precisionTestTime, want: true},
+		{precision: models.NanosecondsPrecision, t: time.Date(1678, 1, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{precision: models.NanosecondsPrecision, t: time.Date(2262, 1, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{precision: models.NanosecondsPrecision, t: time.Date(1677, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+		{precision: models.NanosecondsPrecision, t: time.Date(2263, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+		{precision: models.NanosecondsPrecision, t: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+		{precision: models.NanosecondsPrecision, t: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: false},
+		{precision: models.MicrosecondsPrecision, t: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: true},
+		{precision: models.MicrosecondsPrecision, t: time.Unix(int64(math.MaxInt64)/1e6, 0), want: false},
+		{precision: models.MicrosecondsPrecision, t: time.Unix(int64(math.MinInt64)/1e6, 0), want: false},
+		{precision: models.MillisecondsPrecision, t: time.Unix(int64(math.MaxInt64)/1e3, 0), want: false},
+		{precision: models.MillisecondsPrecision, t: time.Unix(int64(math.MaxInt64)/1e3-1, 999999999), want: true},
+		{precision: models.SecondsPrecision, t: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		if got := IsStorageTime(tt.t, tt.precision); got != tt.want {
+			t.Errorf("IsStorageTime(%v, %q) = %v, want %v", tt.t, tt.precision, got, tt.want)
+		}
+		// Whatever fits converts without overflowing.
+		if tt.want {
+			if got := FromStorageTime(ToStorageTime(tt.t, tt.precision), tt.precision); got.After(tt.t) || tt.t.Sub(got) >= time.Second {
+				t.Errorf("FromStorageTime(ToStorageTime(%v, %q)) = %v", tt.t, tt.precision, got)
+			}
+		}
+	}
+}
+
+func TestToStorageDuration(t *testing.T) {
+	tests := []struct {
+		precision string
+		want      int64
+	}{
+		{precision: models.SecondsPrecision, want: 90},
+		{precision: models.MillisecondsPrecision, want: 90000},
+		{precision: models.MicrosecondsPrecision, want: 90000000},
+		{precision: models.NanosecondsPrecision, want: 90000000000},
+	}
+	for _, tt := range tests {
+		if got := ToStorageDuration(90*time.Second, tt.precision); got != tt.want {
+			t.Errorf("ToStorageDuration(90s, %q) = %v, want %v", tt.precision, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package tsdb
+
+import (
+	"time"
+)
+
+// ExpiredPartitions returns the partitions saved on disk in the directory
+// which only have data points older than their retention. Every metric is
+// kept for the `retention` unless it has its own in `metricRetentions`.
+//
+// DEVELOPERS NOTE:
+// A partition can only be removed as a whole, therefore a partition is kept
+// for as long as the metric in it with the longest retention needs it.
+func ExpiredPartitions(dataPath string, now time.Time, precision string, retention time.Duration, metricRetentions map[string]time.Duration) ([]*Partition, error) {
+	partitions, err := ListPartitions(dataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := []*Partition{}
+	for _, p := range partitions {
+		keep := retention
+		if len(p.Metrics) > 0 {
+			keep = 0
+			for _, metric := range p.Metrics {
+				r, ok := metricRetentions[metric]
+				if !ok {
+					r = retention
+				}
+				if r > keep {
+					keep = r
+				}
+			}
+		}
+		if p.MaxTimestamp < ToStorageTime(now.Add(-keep), precision) {
+			arr = append(arr, p)
+		}
+	}
+	return arr, nil
+}
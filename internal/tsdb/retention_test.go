@@ -0,0 +1,118 @@
+package tsdb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// Utility function which saves the meta data of a partition with the metrics
+// and range of timestamps, in seconds, the way `tstorage` does.
+func writeTestPartition(t *testing.T, dataPath string, name string, min int64, max int64, metrics ...string) {
+	meta := map[string]interface{}{
+		"minTimestamp": min,
+		"maxTimestamp": max,
+		"metrics":      map[string]interface{}{},
+	}
+	for _, metric := range metrics {
+		meta["metrics"].(map[string]interface{})[metric] = map[string]interface{}{"name": metric, "minTimestamp": min, "maxTimestamp": max}
+	}
+	b, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dataPath, name)
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(path, "meta.json"), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpiredPartitions(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	day := int64(24 * 60 * 60)
+	dataPath := t.TempDir()
+	writeTestPartition(t, dataPath, "p-1", 1600000000-10*day, 1600000000-9*day, "temperature")
+	writeTestPartition(t, dataPath, "p-2", 1600000000-9*day, 1600000000-8*day, "temperature", "humidity")
+	writeTestPartition(t, dataPath, "p-3", 1600000000-3*day, 1600000000-2*day, "temperature", "humidity")
+	writeTestPartition(t, dataPath, "p-4", 1600000000-day, 1600000000, "temperature")
+	writeTestPartition(t, dataPath, "p-5", 1600000000-12*day, 1600000000-11*day)
+	// Anything but partitions is left alone.
+	writeTestPartition(t, dataPath, "wal", 0, 0)
+
+	tests := []struct {
+		name             string
+		retention        time.Duration
+		metricRetentions map[string]time.Duration
+		want             []string
+	}{
+		{
+			name:      "tenant retention",
+			retention: 7 * 24 * time.Hour,
+			want:      []string{"p-5", "p-1", "p-2"},
+		},
+		{
+			name:      "everything is kept",
+			retention: 30 * 24 * time.Hour,
+			want:      []string{},
+		},
+		{
+			name:      "a partition is kept until its newest data point is older than the retention",
+			retention: 9 * 24 * time.Hour,
+			want:      []string{"p-5"},
+		},
+		{
+			name:      "a partition expires right after its newest data point does",
+			retention: 9*24*time.Hour - time.Second,
+			want:      []string{"p-5", "p-1"},
+		},
+		{
+			name:             "a shorter metric retention",
+			retention:        7 * 24 * time.Hour,
+			metricRetentions: map[string]time.Duration{"humidity": time.Hour},
+			want:             []string{"p-5", "p-1", "p-2"},
+		},
+		{
+			name:             "the partition is kept for the metric with the longest retention",
+			retention:        7 * 24 * time.Hour,
+			metricRetentions: map[string]time.Duration{"humidity": 30 * 24 * time.Hour},
+			want:             []string{"p-5", "p-1"},
+		},
+		{
+			name:             "every metric has a shorter retention",
+			retention:        30 * 24 * time.Hour,
+			metricRetentions: map[string]time.Duration{"temperature": 12 * time.Hour, "humidity": 12 * time.Hour},
+			want:             []string{"p-1", "p-2", "p-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partitions, err := ExpiredPartitions(dataPath, now, models.SecondsPrecision, tt.retention, tt.metricRetentions)
+			if err != nil {
+				t.Fatalf("ExpiredPartitions failed: %v", err)
+			}
+			got := []string{}
+			for _, p := range partitions {
+				got = append(got, filepath.Base(p.Path))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpiredPartitions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpiredPartitionsMissingDirectory(t *testing.T) {
+	partitions, err := ExpiredPartitions(filepath.Join(t.TempDir(), "missing"), time.Now(), models.SecondsPrecision, time.Hour, nil)
+	if err != nil || len(partitions) != 0 {
+		t.Errorf("ExpiredPartitions of a missing directory = %v, %v, want none", partitions, err)
+	}
+}
@@ -0,0 +1,21 @@
+package tsdb
+
+import (
+	"time"
+
+	"github.com/nakabonne/tstorage"
+)
+
+// NewRow returns the row to insert into a storage which saves its timestamps
+// with the precision. Every way we ingest data must use this so the
+// timestamps are always converted the same way.
+func NewRow(metric string, labels []tstorage.Label, t time.Time, value float64, precision string) tstorage.Row {
+	return tstorage.Row{
+		Metric: metric,
+		Labels: labels,
+		DataPoint: tstorage.DataPoint{
+			Timestamp: ToStorageTime(t, precision),
+			Value:     value,
+		},
+	}
+}
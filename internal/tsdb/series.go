@@ -0,0 +1,178 @@
+package tsdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nakabonne/tstorage"
+)
+
+// Series identifies a single time-series: the metric name and its labels.
+type Series struct {
+	Metric string
+	Labels []tstorage.Label
+}
+
+// Partition is what we know about a partition saved on disk.
+type Partition struct {
+	Path         string
+	MinTimestamp int64
+	MaxTimestamp int64
+	// The metrics which have data points in the partition.
+	Metrics []string
+}
+
+// partitionMeta is the part of the `meta.json` file `tstorage` saves in
+// every partition which we need.
+type partitionMeta struct {
+	MinTimestamp int64 `json:"minTimestamp"`
+	MaxTimestamp int64 `json:"maxTimestamp"`
+	Metrics      map[string]struct {
+		Name         string `json:"name"`
+		MinTimestamp int64  `json:"minTimestamp"`
+		MaxTimestamp int64  `json:"maxTimestamp"`
+	} `json:"metrics"`
+}
+
+// ListPartitions returns the partitions saved on disk in the directory,
+// oldest first. The partitions kept in memory by an open storage are not
+// included.
+func ListPartitions(dataPath string) ([]*Partition, error) {
+	arr := []*Partition{}
+	err := walkPartitions(dataPath, func(path string, meta *partitionMeta) {
+		metrics := map[string]bool{}
+		for name := range meta.Metrics {
+			metrics[ParseMetricName(name).Metric] = true
+		}
+		p := &Partition{Path: path, MinTimestamp: meta.MinTimestamp, MaxTimestamp: meta.MaxTimestamp, Metrics: []string{}}
+		for metric := range metrics {
+			p.Metrics = append(p.Metrics, metric)
+		}
+		sort.Strings(p.Metrics)
+		arr = append(arr, p)
+	})
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].MinTimestamp < arr[j].MinTimestamp
+	})
+	return arr, err
+}
+
+// ListSeries returns every series saved on disk in the directory. The
+// partitions kept in memory by an open storage are not included so the
+// storage should be closed first.
+func ListSeries(dataPath string) ([]*Series, error) {
+	names := map[string]bool{}
+	err := walkPartitions(dataPath, func(path string, meta *partitionMeta) {
+		for name := range meta.Metrics {
+			names[name] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	arr := make([]*Series, 0, len(names))
+	for name := range names {
+		arr = append(arr, ParseMetricName(name))
+	}
+	sort.Slice(arr, func(i, j int) bool {
+		return arr[i].String() < arr[j].String()
+	})
+	return arr, nil
+}
+
+// walkPartitions calls the function with the meta data of every partition
+// saved on disk in the directory.
+func walkPartitions(dataPath string, fn func(path string, meta *partitionMeta)) error {
+	files, err := ioutil.ReadDir(dataPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if !f.IsDir() || !strings.HasPrefix(f.Name(), "p-") {
+			continue
+		}
+		path := filepath.Join(dataPath, f.Name())
+		b, err := ioutil.ReadFile(filepath.Join(path, "meta.json"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		meta := &partitionMeta{}
+		if err := json.Unmarshal(b, meta); err != nil {
+			return err
+		}
+		fn(path, meta)
+	}
+	return nil
+}
+
+// ParseMetricName reverses how `tstorage` encodes the metric and labels into
+// a single name: a metric without labels is saved as is, otherwise the metric
+// and every label name and value are each prefixed with their length. A
+// metric name made of text cannot be mistaken for the encoded form as its
+// first two bytes would have to be its length.
+func ParseMetricName(name string) *Series {
+	if labels, metric, ok := parseEncodedMetricName(name); ok {
+		return &Series{Metric: metric, Labels: labels}
+	}
+	return &Series{Metric: name, Labels: []tstorage.Label{}}
+}
+
+func parseEncodedMetricName(name string) ([]tstorage.Label, string, bool) {
+	b := []byte(name)
+	next := func() (string, bool) {
+		if len(b) < 2 {
+			return "", false
+		}
+		n := int(binary.BigEndian.Uint16(b))
+		if len(b) < 2+n {
+			return "", false
+		}
+		s := string(b[2 : 2+n])
+		b = b[2+n:]
+		return s, true
+	}
+
+	metric, ok := next()
+	if !ok || metric == "" {
+		return nil, "", false
+	}
+	labels := []tstorage.Label{}
+	for len(b) > 0 {
+		labelName, ok := next()
+		if !ok {
+			return nil, "", false
+		}
+		labelValue, ok := next()
+		if !ok {
+			return nil, "", false
+		}
+		labels = append(labels, tstorage.Label{Name: labelName, Value: labelValue})
+	}
+	return labels, metric, true
+}
+
+// String returns the series in the same format Prometheus uses, for example
+// `temperature{room="kitchen"}`.
+func (s *Series) String() string {
+	if len(s.Labels) == 0 {
+		return s.Metric
+	}
+	parts := make([]string, 0, len(s.Labels))
+	for _, l := range s.Labels {
+		parts = append(parts, l.Name+"=\""+l.Value+"\"")
+	}
+	return s.Metric + "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,62 @@
+package tsdb
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/nakabonne/tstorage"
+
+	"github.com/bartmika/mothership-server/internal/models"
+)
+
+// DEVELOPERS NOTE:
+// `tstorage` expires partitions by when they were written to disk and not by
+// the age of their data points, and it knows nothing of our per metric
+// retention. We never want it to remove anything so the retention is
+// enforced by us instead (see `ExpiredPartitions`).
+const storageRetention = time.Duration(math.MaxInt64)
+
+// NewStorage opens the time-series storage saved in the directory using the
+// tenant's storage settings.
+func NewStorage(dataPath string, cfg *models.TenantStorageConfig) (tstorage.Storage, error) {
+	precision, err := ToPrecision(cfg.TimestampPrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	return tstorage.NewStorage(
+		tstorage.WithDataPath(dataPath),
+		tstorage.WithTimestampPrecision(precision),
+		tstorage.WithPartitionDuration(cfg.PartitionDuration),
+		tstorage.WithWriteTimeout(cfg.WriteTimeout),
+		tstorage.WithRetention(storageRetention),
+	)
+}
+
+// ToPrecision converts the precision we save in the database into the one
+// used by `tstorage`.
+func ToPrecision(precision string) (tstorage.TimestampPrecision, error) {
+	switch precision {
+	case models.SecondsPrecision:
+		return tstorage.Seconds, nil
+	case models.MillisecondsPrecision:
+		return tstorage.Milliseconds, nil
+	case models.MicrosecondsPrecision:
+		return tstorage.Microseconds, nil
+	case models.NanosecondsPrecision:
+		return tstorage.Nanoseconds, nil
+	default:
+		return "", fmt.Errorf("timestamp precision %q is not supported", precision)
+	}
+}
+
+// IsOverloaded returns true if the rows were not inserted because the storage
+// was busy with other writers for longer than its write timeout, in which
+// case inserting them again later may succeed.
+func IsOverloaded(err error) bool {
+	// DEVELOPERS NOTE:
+	// `tstorage` has no error value for this so we have to go by the message.
+	return err != nil && strings.Contains(err.Error(), "since it is overloaded with")
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// Every API key starts with this value so we can tell them apart from the JWT
+// `access tokens` sent in the same `authorization` header.
+const APIKeyPrefix = "msk_"
+
+const apiKeyIdLength = 12
+
+// Function generates a new API key and returns the key along with its public
+// id. The key has the format `msk_<id>_<secret>` where the id is stored in
+// plaintext for lookups and the secret is only ever stored hashed.
+func GenerateAPIKey() (string, string, error) {
+	b := make([]byte, apiKeyIdLength/2)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	id := hex.EncodeToString(b)
+
+	secret, err := NewSecureToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	return APIKeyPrefix + id + "_" + secret, id, nil
+}
+
+// Function returns the public id of the API key or false if the value is not
+// formatted like an API key.
+func ParseAPIKey(key string) (string, bool) {
+	if !IsAPIKey(key) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(key, APIKeyPrefix)
+	if len(rest) <= apiKeyIdLength+1 || rest[apiKeyIdLength] != '_' {
+		return "", false
+	}
+	return rest[:apiKeyIdLength], true
+}
+
+// Function returns true if the value looks like an API key.
+func IsAPIKey(value string) bool {
+	return strings.HasPrefix(value, APIKeyPrefix)
+}
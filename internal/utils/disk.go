@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Function returns the total size in bytes of the files inside the directory.
+// A directory which does not exist has a size of zero.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return size, err
+}
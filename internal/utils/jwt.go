@@ -1,20 +1,46 @@
 package utils
 
 import (
+	"errors"
+	"strconv"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
+const (
+	AccessTokenType         = "access"
+	RefreshTokenType        = "refresh"
+	ChallengeTokenType      = "challenge"
+	TenantDeletionTokenType = "tenant_deletion"
+
+	// The additional time the `refresh token` remains valid after the
+	// `access token` has expired.
+	RefreshTokenGracePeriod = time.Hour * 24 * 7
+)
+
+// TokenClaims are the claims we embed in every token we issue. The `Issuer`
+// holds the session uuid, the `TokenType` lets us tell `access tokens` and
+// `refresh tokens` apart and the `Family` links every `refresh token` issued
+// from the same login so reuse of an old token can be detected.
+type TokenClaims struct {
+	TokenType string `json:"typ"`
+	Family    string `json:"fam,omitempty"`
+	jwt.StandardClaims
+}
+
 // Generate the `access token` and `refresh token` for the secret key.
-func GenerateJWTTokenPair(hmacSecret []byte, sessionUuid string, d time.Duration) (string, string, error) {
+func GenerateJWTTokenPair(hmacSecret []byte, sessionUuid string, familyUuid string, d time.Duration) (string, string, error) {
 	//
 	// Generate token.
 	//
 
-	claims := &jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(d).Unix(),
-		Issuer:    sessionUuid,
+	claims := &TokenClaims{
+		TokenType: AccessTokenType,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(d).Unix(),
+			Issuer:    sessionUuid,
+		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -27,9 +53,13 @@ func GenerateJWTTokenPair(hmacSecret []byte, sessionUuid string, d time.Duration
 	// Generate refresh token.
 	//
 
-	claims = &jwt.StandardClaims{
-		ExpiresAt: time.Now().Add(d + time.Hour*24*7).Unix(), // Plus 7 days.
-		Issuer:    sessionUuid,
+	claims = &TokenClaims{
+		TokenType: RefreshTokenType,
+		Family:    familyUuid,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(d + RefreshTokenGracePeriod).Unix(),
+			Issuer:    sessionUuid,
+		},
 	}
 
 	token = jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -39,24 +69,93 @@ func GenerateJWTTokenPair(hmacSecret []byte, sessionUuid string, d time.Duration
 	return tokenString, refreshTokenString, err
 }
 
-// Validates either the `access token` or `refresh token` and returns either the
-// `uuid` if success or error on failure.
+// Validates the `access token` and returns either the session `uuid` if
+// success or error on failure.
 func ProcessBearerToken(hmacSecret []byte, tokenString string) (string, error) {
+	claims, err := parseToken(hmacSecret, tokenString, AccessTokenType)
+	if err != nil {
+		return "", err
+	}
+	return claims.Issuer, nil
+}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return hmacSecret, nil
-	})
+// Validates the `refresh token` and returns the session `uuid` and token
+// family `uuid` if success or error on failure.
+func ProcessRefreshToken(hmacSecret []byte, tokenString string) (string, string, error) {
+	claims, err := parseToken(hmacSecret, tokenString, RefreshTokenType)
+	if err != nil {
+		return "", "", err
+	}
+	if claims.Family == "" {
+		return "", "", errors.New("Token is missing the family")
+	}
+	return claims.Issuer, claims.Family, nil
+}
+
+// Generate the short lived `challenge token` which proves the user already
+// passed the first step of a login which requires a second factor.
+func GenerateChallengeToken(hmacSecret []byte, userId uint64, d time.Duration) (string, error) {
+	claims := &TokenClaims{
+		TokenType: ChallengeTokenType,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(d).Unix(),
+			Subject:   strconv.FormatUint(userId, 10),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(hmacSecret)
+}
+
+// Validates the `challenge token` and returns the user id if success or error
+// on failure.
+func ProcessChallengeToken(hmacSecret []byte, tokenString string) (uint64, error) {
+	claims, err := parseToken(hmacSecret, tokenString, ChallengeTokenType)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(claims.Subject, 10, 64)
+}
 
-	if err == nil && token.Valid {
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			issuer := claims["iss"].(string)
-			// m["exp"] := string(claims["exp"].(float64))
-			return issuer, nil
-		} else {
-			return "", err
+// Generate the short lived token which confirms the tenant is meant to be
+// deleted.
+func GenerateTenantDeletionToken(hmacSecret []byte, tenantId uint64, d time.Duration) (string, error) {
+	claims := &TokenClaims{
+		TokenType: TenantDeletionTokenType,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(d).Unix(),
+			Subject:   strconv.FormatUint(tenantId, 10),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(hmacSecret)
+}
+
+// Validates the tenant deletion token and returns the tenant id if success or
+// error on failure.
+func ProcessTenantDeletionToken(hmacSecret []byte, tokenString string) (uint64, error) {
+	claims, err := parseToken(hmacSecret, tokenString, TenantDeletionTokenType)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(claims.Subject, 10, 64)
+}
+
+func parseToken(hmacSecret []byte, tokenString string, tokenType string) (*TokenClaims, error) {
+	claims := &TokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("Unexpected signing method")
 		}
-	} else {
-		return "", err
+		return hmacSecret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("Token is invalid")
+	}
+	if claims.TokenType != tokenType {
+		return nil, errors.New("Token is not a " + tokenType + " token")
 	}
-	return "", nil
+	return claims, nil
 }
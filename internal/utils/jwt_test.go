@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+var testHmacSecret = []byte("secret")
+
+func TestJWTTokenPair(t *testing.T) {
+	accessToken, refreshToken, err := GenerateJWTTokenPair(testHmacSecret, "session", "family", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWTTokenPair failed: %v", err)
+	}
+
+	sessionUuid, err := ProcessBearerToken(testHmacSecret, accessToken)
+	if err != nil || sessionUuid != "session" {
+		t.Errorf("ProcessBearerToken = %q, %v, want %q", sessionUuid, err, "session")
+	}
+	sessionUuid, familyUuid, err := ProcessRefreshToken(testHmacSecret, refreshToken)
+	if err != nil || sessionUuid != "session" || familyUuid != "family" {
+		t.Errorf("ProcessRefreshToken = %q, %q, %v, want %q, %q", sessionUuid, familyUuid, err, "session", "family")
+	}
+}
+
+func TestJWTTokenPairErrors(t *testing.T) {
+	accessToken, refreshToken, err := GenerateJWTTokenPair(testHmacSecret, "session", "family", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWTTokenPair failed: %v", err)
+	}
+	expiredAccessToken, _, err := GenerateJWTTokenPair(testHmacSecret, "session", "family", -time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWTTokenPair failed: %v", err)
+	}
+	_, noFamilyRefreshToken, err := GenerateJWTTokenPair(testHmacSecret, "session", "", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateJWTTokenPair failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		process func(secret []byte, token string) error
+		secret  []byte
+		token   string
+	}{
+		{name: "refresh token as access token", process: processBearerToken, secret: testHmacSecret, token: refreshToken},
+		{name: "access token as refresh token", process: processRefreshToken, secret: testHmacSecret, token: accessToken},
+		{name: "access token of another secret", process: processBearerToken, secret: []byte("other"), token: accessToken},
+		{name: "refresh token of another secret", process: processRefreshToken, secret: []byte("other"), token: refreshToken},
+		{name: "expired access token", process: processBearerToken, secret: testHmacSecret, token: expiredAccessToken},
+		{name: "refresh token without a family", process: processRefreshToken, secret: testHmacSecret, token: noFamilyRefreshToken},
+		{name: "malformed token", process: processBearerToken, secret: testHmacSecret, token: "not-a-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.process(tt.secret, tt.token); err == nil {
+				t.Errorf("token was accepted")
+			}
+		})
+	}
+}
+
+func processBearerToken(secret []byte, token string) error {
+	_, err := ProcessBearerToken(secret, token)
+	return err
+}
+
+func processRefreshToken(secret []byte, token string) error {
+	_, _, err := ProcessRefreshToken(secret, token)
+	return err
+}
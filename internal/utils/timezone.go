@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// LoadLocation returns the location of the timezone. An empty timezone or
+// "utc" in any case is UTC.
+func LoadLocation(timezone string) (*time.Location, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" || strings.EqualFold(timezone, "utc") {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(timezone)
+}
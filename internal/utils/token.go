@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Function returns a URL safe string encoding `n` bytes read from the
+// cryptographically secure random number generator.
+func NewSecureToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Function returns the SHA-256 hex digest of the token. Use this for storing
+// high entropy secrets (which do not need a slow hash like `bcrypt`) at rest.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Function checks the plaintext token against the hash from `HashToken` in
+// constant time.
+func CheckTokenHash(token string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashToken(token)), []byte(hash)) == 1
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// The secret of the SHA1 test vectors in RFC 6238 appendix B, which is the
+// ASCII string "12345678901234567890", in base32.
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// The last six digits of the test vectors in RFC 6238 appendix B.
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{t: time.Unix(59, 0), want: "287082"},
+		{t: time.Unix(1111111109, 0), want: "081804"},
+		{t: time.Unix(1111111111, 0), want: "050471"},
+		{t: time.Unix(1234567890, 0), want: "005924"},
+		{t: time.Unix(2000000000, 0), want: "279037"},
+		{t: time.Unix(20000000000, 0), want: "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(rfcTOTPSecret, TOTPStep(tt.t))
+		if err != nil {
+			t.Fatalf("TOTPCode failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode at %v = %v, want %v", tt.t.Unix(), got, tt.want)
+		}
+	}
+
+	// Secrets are accepted in lower case as some apps show them that way.
+	if got, err := TOTPCode(strings.ToLower(rfcTOTPSecret), 1); err != nil || got != "287082" {
+		t.Errorf("TOTPCode of the lower case secret = %v, %v, want 287082", got, err)
+	}
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Errorf("TOTPCode of a malformed secret did not fail")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1600000015, 0)
+	current := TOTPStep(now)
+	code := func(step int64) string {
+		c, err := TOTPCode(rfcTOTPSecret, step)
+		if err != nil {
+			t.Fatalf("TOTPCode failed: %v", err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name         string
+		code         string
+		lastUsedStep int64
+		wantStep     int64
+		wantOk       bool
+	}{
+		{name: "current step", code: code(current), wantStep: current, wantOk: true},
+		{name: "previous step", code: code(current - 1), wantStep: current - 1, wantOk: true},
+		{name: "next step", code: code(current + 1), wantStep: current + 1, wantOk: true},
+		{name: "surrounding spaces", code: " " + code(current) + " ", wantStep: current, wantOk: true},
+		{name: "two steps ago", code: code(current - 2)},
+		{name: "two steps ahead", code: code(current + 2)},
+		{name: "already used", code: code(current), lastUsedStep: current},
+		{name: "step before the last used", code: code(current - 1), lastUsedStep: current - 1},
+		{name: "newer than the last used", code: code(current), lastUsedStep: current - 1, wantStep: current, wantOk: true},
+		{name: "too short", code: code(current)[1:]},
+		{name: "too long", code: code(current) + "0"},
+		{name: "empty", code: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfcTOTPSecret, tt.code, now, tt.lastUsedStep)
+			if ok != tt.wantOk || step != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q) = %v, %v, want %v, %v", tt.code, step, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret failed: %v", err)
+	}
+	if _, err := TOTPCode(secret, 1); err != nil {
+		t.Errorf("TOTPCode of the generated secret failed: %v", err)
+	}
+	if other, _ := GenerateTOTPSecret(); other == secret {
+		t.Errorf("GenerateTOTPSecret returned the same secret twice")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes failed: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("recovery code %q is not formatted as xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("recovery code %q was generated twice", code)
+		}
+		seen[code] = true
+
+		// The code is found no matter how the user typed it.
+		for _, typed := range []string{strings.ToUpper(code), " " + code + " ", strings.Replace(code, "-", "", 1)} {
+			if got := NormalizeRecoveryCode(typed); got != code {
+				t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", typed, got, code)
+			}
+		}
+	}
+	if len(codes) != 10 {
+		t.Errorf("GenerateRecoveryCodes returned %v codes, want 10", len(codes))
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	got := TOTPURI("Mothership", "alice@example.com", rfcTOTPSecret)
+	want := "otpauth://totp/Mothership:alice@example.com?algorithm=SHA1&digits=6&issuer=Mothership&period=30&secret=" + rfcTOTPSecret
+	if got != want {
+		t.Errorf("TOTPURI = %v, want %v", got, want)
+	}
+}
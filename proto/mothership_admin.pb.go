@@ -0,0 +1,913 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.9.1
+// source: proto/mothership_admin.proto
+
+package mothership_server
+
+import (
+	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AdminTenantRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              uint64               `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid            string               `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name            string               `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Active          bool                 `protobuf:"varint,4,opt,name=active,proto3" json:"active,omitempty"`
+	Timezone        string               `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	UsersCount      int64                `protobuf:"varint,6,opt,name=usersCount,proto3" json:"usersCount,omitempty"`
+	DataSize        int64                `protobuf:"varint,7,opt,name=dataSize,proto3" json:"dataSize,omitempty"`
+	PartitionsCount int64                `protobuf:"varint,8,opt,name=partitionsCount,proto3" json:"partitionsCount,omitempty"`
+	CreatedTime     *timestamp.Timestamp `protobuf:"bytes,9,opt,name=createdTime,proto3" json:"createdTime,omitempty"`
+}
+
+func (x *AdminTenantRes) Reset() {
+	*x = AdminTenantRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminTenantRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminTenantRes) ProtoMessage() {}
+
+func (x *AdminTenantRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminTenantRes.ProtoReflect.Descriptor instead.
+func (*AdminTenantRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AdminTenantRes) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AdminTenantRes) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AdminTenantRes) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AdminTenantRes) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *AdminTenantRes) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *AdminTenantRes) GetUsersCount() int64 {
+	if x != nil {
+		return x.UsersCount
+	}
+	return 0
+}
+
+func (x *AdminTenantRes) GetDataSize() int64 {
+	if x != nil {
+		return x.DataSize
+	}
+	return 0
+}
+
+func (x *AdminTenantRes) GetPartitionsCount() int64 {
+	if x != nil {
+		return x.PartitionsCount
+	}
+	return 0
+}
+
+func (x *AdminTenantRes) GetCreatedTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedTime
+	}
+	return nil
+}
+
+type AdminListTenantsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tenants []*AdminTenantRes `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+}
+
+func (x *AdminListTenantsRes) Reset() {
+	*x = AdminListTenantsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminListTenantsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListTenantsRes) ProtoMessage() {}
+
+func (x *AdminListTenantsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListTenantsRes.ProtoReflect.Descriptor instead.
+func (*AdminListTenantsRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AdminListTenantsRes) GetTenants() []*AdminTenantRes {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+type AdminImpersonateReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TenantId uint64 `protobuf:"varint,1,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+}
+
+func (x *AdminImpersonateReq) Reset() {
+	*x = AdminImpersonateReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminImpersonateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminImpersonateReq) ProtoMessage() {}
+
+func (x *AdminImpersonateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminImpersonateReq.ProtoReflect.Descriptor instead.
+func (*AdminImpersonateReq) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AdminImpersonateReq) GetTenantId() uint64 {
+	if x != nil {
+		return x.TenantId
+	}
+	return 0
+}
+
+type AdminImpersonateRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AccessToken string               `protobuf:"bytes,1,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	ExpiryTime  *timestamp.Timestamp `protobuf:"bytes,2,opt,name=expiryTime,proto3" json:"expiryTime,omitempty"`
+}
+
+func (x *AdminImpersonateRes) Reset() {
+	*x = AdminImpersonateRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminImpersonateRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminImpersonateRes) ProtoMessage() {}
+
+func (x *AdminImpersonateRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminImpersonateRes.ProtoReflect.Descriptor instead.
+func (*AdminImpersonateRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AdminImpersonateRes) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *AdminImpersonateRes) GetExpiryTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.ExpiryTime
+	}
+	return nil
+}
+
+type AdminForceLogoutReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserUuid string `protobuf:"bytes,1,opt,name=userUuid,proto3" json:"userUuid,omitempty"`
+	TenantId uint64 `protobuf:"varint,2,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+}
+
+func (x *AdminForceLogoutReq) Reset() {
+	*x = AdminForceLogoutReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminForceLogoutReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminForceLogoutReq) ProtoMessage() {}
+
+func (x *AdminForceLogoutReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminForceLogoutReq.ProtoReflect.Descriptor instead.
+func (*AdminForceLogoutReq) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AdminForceLogoutReq) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *AdminForceLogoutReq) GetTenantId() uint64 {
+	if x != nil {
+		return x.TenantId
+	}
+	return 0
+}
+
+type AdminTenantIngestionRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TenantId      uint64  `protobuf:"varint,1,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+	TotalRows     uint64  `protobuf:"varint,2,opt,name=totalRows,proto3" json:"totalRows,omitempty"`
+	RowsPerSecond float64 `protobuf:"fixed64,3,opt,name=rowsPerSecond,proto3" json:"rowsPerSecond,omitempty"`
+}
+
+func (x *AdminTenantIngestionRes) Reset() {
+	*x = AdminTenantIngestionRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminTenantIngestionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminTenantIngestionRes) ProtoMessage() {}
+
+func (x *AdminTenantIngestionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminTenantIngestionRes.ProtoReflect.Descriptor instead.
+func (*AdminTenantIngestionRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AdminTenantIngestionRes) GetTenantId() uint64 {
+	if x != nil {
+		return x.TenantId
+	}
+	return 0
+}
+
+func (x *AdminTenantIngestionRes) GetTotalRows() uint64 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *AdminTenantIngestionRes) GetRowsPerSecond() float64 {
+	if x != nil {
+		return x.RowsPerSecond
+	}
+	return 0
+}
+
+type AdminIngestionStatsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartTime     *timestamp.Timestamp       `protobuf:"bytes,1,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	TotalRows     uint64                     `protobuf:"varint,2,opt,name=totalRows,proto3" json:"totalRows,omitempty"`
+	RowsPerSecond float64                    `protobuf:"fixed64,3,opt,name=rowsPerSecond,proto3" json:"rowsPerSecond,omitempty"`
+	Tenants       []*AdminTenantIngestionRes `protobuf:"bytes,4,rep,name=tenants,proto3" json:"tenants,omitempty"`
+}
+
+func (x *AdminIngestionStatsRes) Reset() {
+	*x = AdminIngestionStatsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminIngestionStatsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminIngestionStatsRes) ProtoMessage() {}
+
+func (x *AdminIngestionStatsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminIngestionStatsRes.ProtoReflect.Descriptor instead.
+func (*AdminIngestionStatsRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AdminIngestionStatsRes) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *AdminIngestionStatsRes) GetTotalRows() uint64 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *AdminIngestionStatsRes) GetRowsPerSecond() float64 {
+	if x != nil {
+		return x.RowsPerSecond
+	}
+	return 0
+}
+
+func (x *AdminIngestionStatsRes) GetTenants() []*AdminTenantIngestionRes {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+type AdminTenantRetentionRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TenantId          uint64               `protobuf:"varint,1,opt,name=tenantId,proto3" json:"tenantId,omitempty"`
+	RemovedPartitions int64                `protobuf:"varint,2,opt,name=removedPartitions,proto3" json:"removedPartitions,omitempty"`
+	ReclaimedBytes    int64                `protobuf:"varint,3,opt,name=reclaimedBytes,proto3" json:"reclaimedBytes,omitempty"`
+	LastRunTime       *timestamp.Timestamp `protobuf:"bytes,4,opt,name=lastRunTime,proto3" json:"lastRunTime,omitempty"`
+}
+
+func (x *AdminTenantRetentionRes) Reset() {
+	*x = AdminTenantRetentionRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminTenantRetentionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminTenantRetentionRes) ProtoMessage() {}
+
+func (x *AdminTenantRetentionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminTenantRetentionRes.ProtoReflect.Descriptor instead.
+func (*AdminTenantRetentionRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AdminTenantRetentionRes) GetTenantId() uint64 {
+	if x != nil {
+		return x.TenantId
+	}
+	return 0
+}
+
+func (x *AdminTenantRetentionRes) GetRemovedPartitions() int64 {
+	if x != nil {
+		return x.RemovedPartitions
+	}
+	return 0
+}
+
+func (x *AdminTenantRetentionRes) GetReclaimedBytes() int64 {
+	if x != nil {
+		return x.ReclaimedBytes
+	}
+	return 0
+}
+
+func (x *AdminTenantRetentionRes) GetLastRunTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastRunTime
+	}
+	return nil
+}
+
+type AdminRetentionStatsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RemovedPartitions int64                      `protobuf:"varint,1,opt,name=removedPartitions,proto3" json:"removedPartitions,omitempty"`
+	ReclaimedBytes    int64                      `protobuf:"varint,2,opt,name=reclaimedBytes,proto3" json:"reclaimedBytes,omitempty"`
+	Tenants           []*AdminTenantRetentionRes `protobuf:"bytes,3,rep,name=tenants,proto3" json:"tenants,omitempty"`
+}
+
+func (x *AdminRetentionStatsRes) Reset() {
+	*x = AdminRetentionStatsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_mothership_admin_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminRetentionStatsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminRetentionStatsRes) ProtoMessage() {}
+
+func (x *AdminRetentionStatsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mothership_admin_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminRetentionStatsRes.ProtoReflect.Descriptor instead.
+func (*AdminRetentionStatsRes) Descriptor() ([]byte, []int) {
+	return file_proto_mothership_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AdminRetentionStatsRes) GetRemovedPartitions() int64 {
+	if x != nil {
+		return x.RemovedPartitions
+	}
+	return 0
+}
+
+func (x *AdminRetentionStatsRes) GetReclaimedBytes() int64 {
+	if x != nil {
+		return x.ReclaimedBytes
+	}
+	return 0
+}
+
+func (x *AdminRetentionStatsRes) GetTenants() []*AdminTenantRetentionRes {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+var File_proto_mothership_admin_proto protoreflect.FileDescriptor
+
+var file_proto_mothership_admin_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xa0, 0x02, 0x0a, 0x0e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x54, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f,
+	0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f,
+	0x6e, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x73, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x28,
+	0x0a, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x46, 0x0a, 0x13, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12, 0x2f, 0x0a,
+	0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x54, 0x65, 0x6e, 0x61,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x52, 0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x22, 0x31,
+	0x0a, 0x13, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x49, 0x6d, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49,
+	0x64, 0x22, 0x73, 0x0a, 0x13, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x49, 0x6d, 0x70, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x3a, 0x0a, 0x0a, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x4d, 0x0a, 0x13, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x46,
+	0x6f, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a,
+	0x08, 0x75, 0x73, 0x65, 0x72, 0x55, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x75, 0x73, 0x65, 0x72, 0x55, 0x75, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x79, 0x0a, 0x17, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x54, 0x65,
+	0x6e, 0x61, 0x6e, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x6f, 0x77, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x6f,
+	0x77, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0d, 0x72, 0x6f, 0x77, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x22, 0xd0, 0x01, 0x0a, 0x16, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12, 0x38, 0x0a, 0x09, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x6f,
+	0x77, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52,
+	0x6f, 0x77, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x6f, 0x77, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x72, 0x6f, 0x77, 0x73,
+	0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12, 0x38, 0x0a, 0x07, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x6e,
+	0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x52, 0x07, 0x74, 0x65, 0x6e, 0x61,
+	0x6e, 0x74, 0x73, 0x22, 0xc9, 0x01, 0x0a, 0x17, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x54, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x11, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x63,
+	0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0e, 0x72, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x3c, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x22,
+	0xa8, 0x01, 0x0a, 0x16, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50, 0x61,
+	0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x63, 0x6c,
+	0x61, 0x69, 0x6d, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0e, 0x72, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x38, 0x0a, 0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x54,
+	0x65, 0x6e, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x52, 0x07, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x32, 0x80, 0x03, 0x0a, 0x0f, 0x4d,
+	0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x43,
+	0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x0b, 0x49, 0x6d, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61,
+	0x74, 0x65, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x49, 0x6d, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x1a,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x49, 0x6d, 0x70, 0x65,
+	0x72, 0x73, 0x6f, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0b,
+	0x46, 0x6f, 0x72, 0x63, 0x65, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x4c, 0x6f,
+	0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x4c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x49, 0x6e, 0x67, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12,
+	0x4c, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x22, 0x00, 0x42, 0x27, 0x5a,
+	0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x72, 0x74,
+	0x6d, 0x69, 0x6b, 0x61, 0x2f, 0x6d, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2d,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_mothership_admin_proto_rawDescOnce sync.Once
+	file_proto_mothership_admin_proto_rawDescData = file_proto_mothership_admin_proto_rawDesc
+)
+
+func file_proto_mothership_admin_proto_rawDescGZIP() []byte {
+	file_proto_mothership_admin_proto_rawDescOnce.Do(func() {
+		file_proto_mothership_admin_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_mothership_admin_proto_rawDescData)
+	})
+	return file_proto_mothership_admin_proto_rawDescData
+}
+
+var file_proto_mothership_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_mothership_admin_proto_goTypes = []interface{}{
+	(*AdminTenantRes)(nil),          // 0: proto.AdminTenantRes
+	(*AdminListTenantsRes)(nil),     // 1: proto.AdminListTenantsRes
+	(*AdminImpersonateReq)(nil),     // 2: proto.AdminImpersonateReq
+	(*AdminImpersonateRes)(nil),     // 3: proto.AdminImpersonateRes
+	(*AdminForceLogoutReq)(nil),     // 4: proto.AdminForceLogoutReq
+	(*AdminTenantIngestionRes)(nil), // 5: proto.AdminTenantIngestionRes
+	(*AdminIngestionStatsRes)(nil),  // 6: proto.AdminIngestionStatsRes
+	(*AdminTenantRetentionRes)(nil), // 7: proto.AdminTenantRetentionRes
+	(*AdminRetentionStatsRes)(nil),  // 8: proto.AdminRetentionStatsRes
+	(*timestamp.Timestamp)(nil),     // 9: google.protobuf.Timestamp
+	(*empty.Empty)(nil),             // 10: google.protobuf.Empty
+}
+var file_proto_mothership_admin_proto_depIdxs = []int32{
+	9,  // 0: proto.AdminTenantRes.createdTime:type_name -> google.protobuf.Timestamp
+	0,  // 1: proto.AdminListTenantsRes.tenants:type_name -> proto.AdminTenantRes
+	9,  // 2: proto.AdminImpersonateRes.expiryTime:type_name -> google.protobuf.Timestamp
+	9,  // 3: proto.AdminIngestionStatsRes.startTime:type_name -> google.protobuf.Timestamp
+	5,  // 4: proto.AdminIngestionStatsRes.tenants:type_name -> proto.AdminTenantIngestionRes
+	9,  // 5: proto.AdminTenantRetentionRes.lastRunTime:type_name -> google.protobuf.Timestamp
+	7,  // 6: proto.AdminRetentionStatsRes.tenants:type_name -> proto.AdminTenantRetentionRes
+	10, // 7: proto.MothershipAdmin.ListTenants:input_type -> google.protobuf.Empty
+	2,  // 8: proto.MothershipAdmin.Impersonate:input_type -> proto.AdminImpersonateReq
+	4,  // 9: proto.MothershipAdmin.ForceLogout:input_type -> proto.AdminForceLogoutReq
+	10, // 10: proto.MothershipAdmin.GetIngestionStats:input_type -> google.protobuf.Empty
+	10, // 11: proto.MothershipAdmin.GetRetentionStats:input_type -> google.protobuf.Empty
+	1,  // 12: proto.MothershipAdmin.ListTenants:output_type -> proto.AdminListTenantsRes
+	3,  // 13: proto.MothershipAdmin.Impersonate:output_type -> proto.AdminImpersonateRes
+	10, // 14: proto.MothershipAdmin.ForceLogout:output_type -> google.protobuf.Empty
+	6,  // 15: proto.MothershipAdmin.GetIngestionStats:output_type -> proto.AdminIngestionStatsRes
+	8,  // 16: proto.MothershipAdmin.GetRetentionStats:output_type -> proto.AdminRetentionStatsRes
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_proto_mothership_admin_proto_init() }
+func file_proto_mothership_admin_proto_init() {
+	if File_proto_mothership_admin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_mothership_admin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminTenantRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminListTenantsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminImpersonateReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminImpersonateRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminForceLogoutReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminTenantIngestionRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminIngestionStatsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminTenantRetentionRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_mothership_admin_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminRetentionStatsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_mothership_admin_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_mothership_admin_proto_goTypes,
+		DependencyIndexes: file_proto_mothership_admin_proto_depIdxs,
+		MessageInfos:      file_proto_mothership_admin_proto_msgTypes,
+	}.Build()
+	File_proto_mothership_admin_proto = out.File
+	file_proto_mothership_admin_proto_rawDesc = nil
+	file_proto_mothership_admin_proto_goTypes = nil
+	file_proto_mothership_admin_proto_depIdxs = nil
+}
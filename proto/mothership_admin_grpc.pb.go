@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package mothership_server
+
+import (
+	context "context"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MothershipAdminClient is the client API for MothershipAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MothershipAdminClient interface {
+	ListTenants(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AdminListTenantsRes, error)
+	Impersonate(ctx context.Context, in *AdminImpersonateReq, opts ...grpc.CallOption) (*AdminImpersonateRes, error)
+	ForceLogout(ctx context.Context, in *AdminForceLogoutReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetIngestionStats(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AdminIngestionStatsRes, error)
+	GetRetentionStats(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AdminRetentionStatsRes, error)
+}
+
+type mothershipAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMothershipAdminClient(cc grpc.ClientConnInterface) MothershipAdminClient {
+	return &mothershipAdminClient{cc}
+}
+
+func (c *mothershipAdminClient) ListTenants(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AdminListTenantsRes, error) {
+	out := new(AdminListTenantsRes)
+	err := c.cc.Invoke(ctx, "/proto.MothershipAdmin/ListTenants", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipAdminClient) Impersonate(ctx context.Context, in *AdminImpersonateReq, opts ...grpc.CallOption) (*AdminImpersonateRes, error) {
+	out := new(AdminImpersonateRes)
+	err := c.cc.Invoke(ctx, "/proto.MothershipAdmin/Impersonate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipAdminClient) ForceLogout(ctx context.Context, in *AdminForceLogoutReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.MothershipAdmin/ForceLogout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipAdminClient) GetIngestionStats(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AdminIngestionStatsRes, error) {
+	out := new(AdminIngestionStatsRes)
+	err := c.cc.Invoke(ctx, "/proto.MothershipAdmin/GetIngestionStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipAdminClient) GetRetentionStats(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AdminRetentionStatsRes, error) {
+	out := new(AdminRetentionStatsRes)
+	err := c.cc.Invoke(ctx, "/proto.MothershipAdmin/GetRetentionStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MothershipAdminServer is the server API for MothershipAdmin service.
+// All implementations must embed UnimplementedMothershipAdminServer
+// for forward compatibility
+type MothershipAdminServer interface {
+	ListTenants(context.Context, *empty.Empty) (*AdminListTenantsRes, error)
+	Impersonate(context.Context, *AdminImpersonateReq) (*AdminImpersonateRes, error)
+	ForceLogout(context.Context, *AdminForceLogoutReq) (*empty.Empty, error)
+	GetIngestionStats(context.Context, *empty.Empty) (*AdminIngestionStatsRes, error)
+	GetRetentionStats(context.Context, *empty.Empty) (*AdminRetentionStatsRes, error)
+	mustEmbedUnimplementedMothershipAdminServer()
+}
+
+// UnimplementedMothershipAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedMothershipAdminServer struct {
+}
+
+func (UnimplementedMothershipAdminServer) ListTenants(context.Context, *empty.Empty) (*AdminListTenantsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenants not implemented")
+}
+func (UnimplementedMothershipAdminServer) Impersonate(context.Context, *AdminImpersonateReq) (*AdminImpersonateRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Impersonate not implemented")
+}
+func (UnimplementedMothershipAdminServer) ForceLogout(context.Context, *AdminForceLogoutReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceLogout not implemented")
+}
+func (UnimplementedMothershipAdminServer) GetIngestionStats(context.Context, *empty.Empty) (*AdminIngestionStatsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIngestionStats not implemented")
+}
+func (UnimplementedMothershipAdminServer) GetRetentionStats(context.Context, *empty.Empty) (*AdminRetentionStatsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionStats not implemented")
+}
+func (UnimplementedMothershipAdminServer) mustEmbedUnimplementedMothershipAdminServer() {}
+
+// UnsafeMothershipAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MothershipAdminServer will
+// result in compilation errors.
+type UnsafeMothershipAdminServer interface {
+	mustEmbedUnimplementedMothershipAdminServer()
+}
+
+func RegisterMothershipAdminServer(s grpc.ServiceRegistrar, srv MothershipAdminServer) {
+	s.RegisterService(&MothershipAdmin_ServiceDesc, srv)
+}
+
+func _MothershipAdmin_ListTenants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipAdminServer).ListTenants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MothershipAdmin/ListTenants",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipAdminServer).ListTenants(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MothershipAdmin_Impersonate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminImpersonateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipAdminServer).Impersonate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MothershipAdmin/Impersonate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipAdminServer).Impersonate(ctx, req.(*AdminImpersonateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MothershipAdmin_ForceLogout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminForceLogoutReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipAdminServer).ForceLogout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MothershipAdmin/ForceLogout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipAdminServer).ForceLogout(ctx, req.(*AdminForceLogoutReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MothershipAdmin_GetIngestionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipAdminServer).GetIngestionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MothershipAdmin/GetIngestionStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipAdminServer).GetIngestionStats(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MothershipAdmin_GetRetentionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipAdminServer).GetRetentionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.MothershipAdmin/GetRetentionStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipAdminServer).GetRetentionStats(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MothershipAdmin_ServiceDesc is the grpc.ServiceDesc for MothershipAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MothershipAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.MothershipAdmin",
+	HandlerType: (*MothershipAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTenants",
+			Handler:    _MothershipAdmin_ListTenants_Handler,
+		},
+		{
+			MethodName: "Impersonate",
+			Handler:    _MothershipAdmin_Impersonate_Handler,
+		},
+		{
+			MethodName: "ForceLogout",
+			Handler:    _MothershipAdmin_ForceLogout_Handler,
+		},
+		{
+			MethodName: "GetIngestionStats",
+			Handler:    _MothershipAdmin_GetIngestionStats_Handler,
+		},
+		{
+			MethodName: "GetRetentionStats",
+			Handler:    _MothershipAdmin_GetRetentionStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/mothership_admin.proto",
+}
@@ -22,10 +22,50 @@ type MothershipClient interface {
 	Register(ctx context.Context, in *RegistrationReq, opts ...grpc.CallOption) (*RegistrationRes, error)
 	Login(ctx context.Context, in *LoginReq, opts ...grpc.CallOption) (*LoginRes, error)
 	RefreshToken(ctx context.Context, in *RefreshTokenReq, opts ...grpc.CallOption) (*RefreshTokenRes, error)
+	CompleteLogin(ctx context.Context, in *CompleteLoginReq, opts ...grpc.CallOption) (*LoginRes, error)
 	InsertTimeSeriesDatum(ctx context.Context, in *TimeSeriesDatumReq, opts ...grpc.CallOption) (*empty.Empty, error)
 	InsertTimeSeriesData(ctx context.Context, opts ...grpc.CallOption) (Mothership_InsertTimeSeriesDataClient, error)
 	InsertBulkTimeSeriesData(ctx context.Context, in *BulkTimeSeriesDataReq, opts ...grpc.CallOption) (*empty.Empty, error)
 	SelectBulkTimeSeriesData(ctx context.Context, in *FilterReq, opts ...grpc.CallOption) (*SelectBulkRes, error)
+	StreamTimeSeriesData(ctx context.Context, in *StreamTimeSeriesDataReq, opts ...grpc.CallOption) (Mothership_StreamTimeSeriesDataClient, error)
+	SelectSeries(ctx context.Context, in *FilterReq, opts ...grpc.CallOption) (*SelectSeriesRes, error)
+	ListMetrics(ctx context.Context, in *ListMetricsReq, opts ...grpc.CallOption) (*ListMetricsRes, error)
+	ListLabelNames(ctx context.Context, in *ListLabelNamesReq, opts ...grpc.CallOption) (*ListLabelNamesRes, error)
+	ListLabelValues(ctx context.Context, in *ListLabelValuesReq, opts ...grpc.CallOption) (*ListLabelValuesRes, error)
+	Query(ctx context.Context, in *QueryReq, opts ...grpc.CallOption) (*QueryRes, error)
+	QueryRange(ctx context.Context, in *QueryRangeReq, opts ...grpc.CallOption) (*QueryRes, error)
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyReq, opts ...grpc.CallOption) (*CreateAPIKeyRes, error)
+	ListAPIKeys(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListAPIKeysRes, error)
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	Logout(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListSessions(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListSessionsRes, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	RevokeAllSessions(ctx context.Context, in *RevokeAllSessionsReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetReq, opts ...grpc.CallOption) (*RequestPasswordResetRes, error)
+	ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetReq, opts ...grpc.CallOption) (*ConfirmPasswordResetRes, error)
+	VerifyEmail(ctx context.Context, in *VerifyEmailReq, opts ...grpc.CallOption) (*VerifyEmailRes, error)
+	ResendVerification(ctx context.Context, in *ResendVerificationReq, opts ...grpc.CallOption) (*ResendVerificationRes, error)
+	EnrollTOTP(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*EnrollTOTPRes, error)
+	ConfirmTOTP(ctx context.Context, in *ConfirmTOTPReq, opts ...grpc.CallOption) (*ConfirmTOTPRes, error)
+	DisableTOTP(ctx context.Context, in *DisableTOTPReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	InviteUser(ctx context.Context, in *InviteUserReq, opts ...grpc.CallOption) (*InvitationRes, error)
+	ListInvitations(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListInvitationsRes, error)
+	RevokeInvitation(ctx context.Context, in *RevokeInvitationReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationReq, opts ...grpc.CallOption) (*AcceptInvitationRes, error)
+	ListUsers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListUsersRes, error)
+	UpdateUser(ctx context.Context, in *UpdateUserReq, opts ...grpc.CallOption) (*UserRes, error)
+	ChangeUserRole(ctx context.Context, in *ChangeUserRoleReq, opts ...grpc.CallOption) (*UserRes, error)
+	DeactivateUser(ctx context.Context, in *DeactivateUserReq, opts ...grpc.CallOption) (*UserRes, error)
+	ActivateUser(ctx context.Context, in *ActivateUserReq, opts ...grpc.CallOption) (*UserRes, error)
+	SuspendTenant(ctx context.Context, in *SuspendTenantReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	ReactivateTenant(ctx context.Context, in *ReactivateTenantReq, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetTenant(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*TenantRes, error)
+	UpdateTenant(ctx context.Context, in *UpdateTenantReq, opts ...grpc.CallOption) (*TenantRes, error)
+	DeleteTenant(ctx context.Context, in *DeleteTenantReq, opts ...grpc.CallOption) (*DeleteTenantRes, error)
+	GetStorageConfig(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StorageConfigRes, error)
+	UpdateStorageConfig(ctx context.Context, in *UpdateStorageConfigReq, opts ...grpc.CallOption) (*StorageConfigRes, error)
+	GetRetention(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RetentionRes, error)
+	UpdateRetention(ctx context.Context, in *UpdateRetentionReq, opts ...grpc.CallOption) (*RetentionRes, error)
 }
 
 type mothershipClient struct {
@@ -63,6 +103,15 @@ func (c *mothershipClient) RefreshToken(ctx context.Context, in *RefreshTokenReq
 	return out, nil
 }
 
+func (c *mothershipClient) CompleteLogin(ctx context.Context, in *CompleteLoginReq, opts ...grpc.CallOption) (*LoginRes, error) {
+	out := new(LoginRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/CompleteLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mothershipClient) InsertTimeSeriesDatum(ctx context.Context, in *TimeSeriesDatumReq, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/proto.Mothership/InsertTimeSeriesDatum", in, out, opts...)
@@ -95,217 +144,1474 @@ func (x *mothershipInsertTimeSeriesDataClient) Send(m *TimeSeriesDatumReq) error
 	return x.ClientStream.SendMsg(m)
 }
 
-func (x *mothershipInsertTimeSeriesDataClient) CloseAndRecv() (*empty.Empty, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
+func (x *mothershipInsertTimeSeriesDataClient) CloseAndRecv() (*empty.Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(empty.Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mothershipClient) InsertBulkTimeSeriesData(ctx context.Context, in *BulkTimeSeriesDataReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/InsertBulkTimeSeriesData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) SelectBulkTimeSeriesData(ctx context.Context, in *FilterReq, opts ...grpc.CallOption) (*SelectBulkRes, error) {
+	out := new(SelectBulkRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/SelectBulkTimeSeriesData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) StreamTimeSeriesData(ctx context.Context, in *StreamTimeSeriesDataReq, opts ...grpc.CallOption) (Mothership_StreamTimeSeriesDataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Mothership_ServiceDesc.Streams[1], "/proto.Mothership/StreamTimeSeriesData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mothershipStreamTimeSeriesDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Mothership_StreamTimeSeriesDataClient interface {
+	Recv() (*DataPointsChunkRes, error)
+	grpc.ClientStream
+}
+
+type mothershipStreamTimeSeriesDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *mothershipStreamTimeSeriesDataClient) Recv() (*DataPointsChunkRes, error) {
+	m := new(DataPointsChunkRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mothershipClient) SelectSeries(ctx context.Context, in *FilterReq, opts ...grpc.CallOption) (*SelectSeriesRes, error) {
+	out := new(SelectSeriesRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/SelectSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListMetrics(ctx context.Context, in *ListMetricsReq, opts ...grpc.CallOption) (*ListMetricsRes, error) {
+	out := new(ListMetricsRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListLabelNames(ctx context.Context, in *ListLabelNamesReq, opts ...grpc.CallOption) (*ListLabelNamesRes, error) {
+	out := new(ListLabelNamesRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListLabelNames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListLabelValues(ctx context.Context, in *ListLabelValuesReq, opts ...grpc.CallOption) (*ListLabelValuesRes, error) {
+	out := new(ListLabelValuesRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListLabelValues", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) Query(ctx context.Context, in *QueryReq, opts ...grpc.CallOption) (*QueryRes, error) {
+	out := new(QueryRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) QueryRange(ctx context.Context, in *QueryRangeReq, opts ...grpc.CallOption) (*QueryRes, error) {
+	out := new(QueryRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/QueryRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyReq, opts ...grpc.CallOption) (*CreateAPIKeyRes, error) {
+	out := new(CreateAPIKeyRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/CreateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListAPIKeys(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListAPIKeysRes, error) {
+	out := new(ListAPIKeysRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListAPIKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/RevokeAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) Logout(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListSessions(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListSessionsRes, error) {
+	out := new(ListSessionsRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) RevokeSession(ctx context.Context, in *RevokeSessionReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/RevokeSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) RevokeAllSessions(ctx context.Context, in *RevokeAllSessionsReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/RevokeAllSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetReq, opts ...grpc.CallOption) (*RequestPasswordResetRes, error) {
+	out := new(RequestPasswordResetRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/RequestPasswordReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetReq, opts ...grpc.CallOption) (*ConfirmPasswordResetRes, error) {
+	out := new(ConfirmPasswordResetRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ConfirmPasswordReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) VerifyEmail(ctx context.Context, in *VerifyEmailReq, opts ...grpc.CallOption) (*VerifyEmailRes, error) {
+	out := new(VerifyEmailRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/VerifyEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ResendVerification(ctx context.Context, in *ResendVerificationReq, opts ...grpc.CallOption) (*ResendVerificationRes, error) {
+	out := new(ResendVerificationRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ResendVerification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) EnrollTOTP(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*EnrollTOTPRes, error) {
+	out := new(EnrollTOTPRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/EnrollTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPReq, opts ...grpc.CallOption) (*ConfirmTOTPRes, error) {
+	out := new(ConfirmTOTPRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ConfirmTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) DisableTOTP(ctx context.Context, in *DisableTOTPReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/DisableTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) InviteUser(ctx context.Context, in *InviteUserReq, opts ...grpc.CallOption) (*InvitationRes, error) {
+	out := new(InvitationRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/InviteUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListInvitations(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListInvitationsRes, error) {
+	out := new(ListInvitationsRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListInvitations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) RevokeInvitation(ctx context.Context, in *RevokeInvitationReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/RevokeInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationReq, opts ...grpc.CallOption) (*AcceptInvitationRes, error) {
+	out := new(AcceptInvitationRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ListUsers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListUsersRes, error) {
+	out := new(ListUsersRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) UpdateUser(ctx context.Context, in *UpdateUserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/UpdateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ChangeUserRole(ctx context.Context, in *ChangeUserRoleReq, opts ...grpc.CallOption) (*UserRes, error) {
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ChangeUserRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) DeactivateUser(ctx context.Context, in *DeactivateUserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/DeactivateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ActivateUser(ctx context.Context, in *ActivateUserReq, opts ...grpc.CallOption) (*UserRes, error) {
+	out := new(UserRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ActivateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) SuspendTenant(ctx context.Context, in *SuspendTenantReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/SuspendTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) ReactivateTenant(ctx context.Context, in *ReactivateTenantReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/ReactivateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) GetTenant(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*TenantRes, error) {
+	out := new(TenantRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/GetTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) UpdateTenant(ctx context.Context, in *UpdateTenantReq, opts ...grpc.CallOption) (*TenantRes, error) {
+	out := new(TenantRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/UpdateTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) DeleteTenant(ctx context.Context, in *DeleteTenantReq, opts ...grpc.CallOption) (*DeleteTenantRes, error) {
+	out := new(DeleteTenantRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/DeleteTenant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) GetStorageConfig(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StorageConfigRes, error) {
+	out := new(StorageConfigRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/GetStorageConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) UpdateStorageConfig(ctx context.Context, in *UpdateStorageConfigReq, opts ...grpc.CallOption) (*StorageConfigRes, error) {
+	out := new(StorageConfigRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/UpdateStorageConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) GetRetention(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*RetentionRes, error) {
+	out := new(RetentionRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/GetRetention", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mothershipClient) UpdateRetention(ctx context.Context, in *UpdateRetentionReq, opts ...grpc.CallOption) (*RetentionRes, error) {
+	out := new(RetentionRes)
+	err := c.cc.Invoke(ctx, "/proto.Mothership/UpdateRetention", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MothershipServer is the server API for Mothership service.
+// All implementations must embed UnimplementedMothershipServer
+// for forward compatibility
+type MothershipServer interface {
+	Register(context.Context, *RegistrationReq) (*RegistrationRes, error)
+	Login(context.Context, *LoginReq) (*LoginRes, error)
+	RefreshToken(context.Context, *RefreshTokenReq) (*RefreshTokenRes, error)
+	CompleteLogin(context.Context, *CompleteLoginReq) (*LoginRes, error)
+	InsertTimeSeriesDatum(context.Context, *TimeSeriesDatumReq) (*empty.Empty, error)
+	InsertTimeSeriesData(Mothership_InsertTimeSeriesDataServer) error
+	InsertBulkTimeSeriesData(context.Context, *BulkTimeSeriesDataReq) (*empty.Empty, error)
+	SelectBulkTimeSeriesData(context.Context, *FilterReq) (*SelectBulkRes, error)
+	StreamTimeSeriesData(*StreamTimeSeriesDataReq, Mothership_StreamTimeSeriesDataServer) error
+	SelectSeries(context.Context, *FilterReq) (*SelectSeriesRes, error)
+	ListMetrics(context.Context, *ListMetricsReq) (*ListMetricsRes, error)
+	ListLabelNames(context.Context, *ListLabelNamesReq) (*ListLabelNamesRes, error)
+	ListLabelValues(context.Context, *ListLabelValuesReq) (*ListLabelValuesRes, error)
+	Query(context.Context, *QueryReq) (*QueryRes, error)
+	QueryRange(context.Context, *QueryRangeReq) (*QueryRes, error)
+	CreateAPIKey(context.Context, *CreateAPIKeyReq) (*CreateAPIKeyRes, error)
+	ListAPIKeys(context.Context, *empty.Empty) (*ListAPIKeysRes, error)
+	RevokeAPIKey(context.Context, *RevokeAPIKeyReq) (*empty.Empty, error)
+	Logout(context.Context, *empty.Empty) (*empty.Empty, error)
+	ListSessions(context.Context, *empty.Empty) (*ListSessionsRes, error)
+	RevokeSession(context.Context, *RevokeSessionReq) (*empty.Empty, error)
+	RevokeAllSessions(context.Context, *RevokeAllSessionsReq) (*empty.Empty, error)
+	RequestPasswordReset(context.Context, *RequestPasswordResetReq) (*RequestPasswordResetRes, error)
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetReq) (*ConfirmPasswordResetRes, error)
+	VerifyEmail(context.Context, *VerifyEmailReq) (*VerifyEmailRes, error)
+	ResendVerification(context.Context, *ResendVerificationReq) (*ResendVerificationRes, error)
+	EnrollTOTP(context.Context, *empty.Empty) (*EnrollTOTPRes, error)
+	ConfirmTOTP(context.Context, *ConfirmTOTPReq) (*ConfirmTOTPRes, error)
+	DisableTOTP(context.Context, *DisableTOTPReq) (*empty.Empty, error)
+	InviteUser(context.Context, *InviteUserReq) (*InvitationRes, error)
+	ListInvitations(context.Context, *empty.Empty) (*ListInvitationsRes, error)
+	RevokeInvitation(context.Context, *RevokeInvitationReq) (*empty.Empty, error)
+	AcceptInvitation(context.Context, *AcceptInvitationReq) (*AcceptInvitationRes, error)
+	ListUsers(context.Context, *empty.Empty) (*ListUsersRes, error)
+	UpdateUser(context.Context, *UpdateUserReq) (*UserRes, error)
+	ChangeUserRole(context.Context, *ChangeUserRoleReq) (*UserRes, error)
+	DeactivateUser(context.Context, *DeactivateUserReq) (*UserRes, error)
+	ActivateUser(context.Context, *ActivateUserReq) (*UserRes, error)
+	SuspendTenant(context.Context, *SuspendTenantReq) (*empty.Empty, error)
+	ReactivateTenant(context.Context, *ReactivateTenantReq) (*empty.Empty, error)
+	GetTenant(context.Context, *empty.Empty) (*TenantRes, error)
+	UpdateTenant(context.Context, *UpdateTenantReq) (*TenantRes, error)
+	DeleteTenant(context.Context, *DeleteTenantReq) (*DeleteTenantRes, error)
+	GetStorageConfig(context.Context, *empty.Empty) (*StorageConfigRes, error)
+	UpdateStorageConfig(context.Context, *UpdateStorageConfigReq) (*StorageConfigRes, error)
+	GetRetention(context.Context, *empty.Empty) (*RetentionRes, error)
+	UpdateRetention(context.Context, *UpdateRetentionReq) (*RetentionRes, error)
+	mustEmbedUnimplementedMothershipServer()
+}
+
+// UnimplementedMothershipServer must be embedded to have forward compatible implementations.
+type UnimplementedMothershipServer struct {
+}
+
+func (UnimplementedMothershipServer) Register(context.Context, *RegistrationReq) (*RegistrationRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedMothershipServer) Login(context.Context, *LoginReq) (*LoginRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedMothershipServer) RefreshToken(context.Context, *RefreshTokenReq) (*RefreshTokenRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedMothershipServer) CompleteLogin(context.Context, *CompleteLoginReq) (*LoginRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteLogin not implemented")
+}
+func (UnimplementedMothershipServer) InsertTimeSeriesDatum(context.Context, *TimeSeriesDatumReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertTimeSeriesDatum not implemented")
+}
+func (UnimplementedMothershipServer) InsertTimeSeriesData(Mothership_InsertTimeSeriesDataServer) error {
+	return status.Errorf(codes.Unimplemented, "method InsertTimeSeriesData not implemented")
+}
+func (UnimplementedMothershipServer) InsertBulkTimeSeriesData(context.Context, *BulkTimeSeriesDataReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertBulkTimeSeriesData not implemented")
+}
+func (UnimplementedMothershipServer) SelectBulkTimeSeriesData(context.Context, *FilterReq) (*SelectBulkRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectBulkTimeSeriesData not implemented")
+}
+func (UnimplementedMothershipServer) StreamTimeSeriesData(*StreamTimeSeriesDataReq, Mothership_StreamTimeSeriesDataServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTimeSeriesData not implemented")
+}
+func (UnimplementedMothershipServer) SelectSeries(context.Context, *FilterReq) (*SelectSeriesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectSeries not implemented")
+}
+func (UnimplementedMothershipServer) ListMetrics(context.Context, *ListMetricsReq) (*ListMetricsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetrics not implemented")
+}
+func (UnimplementedMothershipServer) ListLabelNames(context.Context, *ListLabelNamesReq) (*ListLabelNamesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLabelNames not implemented")
+}
+func (UnimplementedMothershipServer) ListLabelValues(context.Context, *ListLabelValuesReq) (*ListLabelValuesRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLabelValues not implemented")
+}
+func (UnimplementedMothershipServer) Query(context.Context, *QueryReq) (*QueryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedMothershipServer) QueryRange(context.Context, *QueryRangeReq) (*QueryRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRange not implemented")
+}
+func (UnimplementedMothershipServer) CreateAPIKey(context.Context, *CreateAPIKeyReq) (*CreateAPIKeyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedMothershipServer) ListAPIKeys(context.Context, *empty.Empty) (*ListAPIKeysRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedMothershipServer) RevokeAPIKey(context.Context, *RevokeAPIKeyReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedMothershipServer) Logout(context.Context, *empty.Empty) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedMothershipServer) ListSessions(context.Context, *empty.Empty) (*ListSessionsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedMothershipServer) RevokeSession(context.Context, *RevokeSessionReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedMothershipServer) RevokeAllSessions(context.Context, *RevokeAllSessionsReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAllSessions not implemented")
+}
+func (UnimplementedMothershipServer) RequestPasswordReset(context.Context, *RequestPasswordResetReq) (*RequestPasswordResetRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedMothershipServer) ConfirmPasswordReset(context.Context, *ConfirmPasswordResetReq) (*ConfirmPasswordResetRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPasswordReset not implemented")
+}
+func (UnimplementedMothershipServer) VerifyEmail(context.Context, *VerifyEmailReq) (*VerifyEmailRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedMothershipServer) ResendVerification(context.Context, *ResendVerificationReq) (*ResendVerificationRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResendVerification not implemented")
+}
+func (UnimplementedMothershipServer) EnrollTOTP(context.Context, *empty.Empty) (*EnrollTOTPRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedMothershipServer) ConfirmTOTP(context.Context, *ConfirmTOTPReq) (*ConfirmTOTPRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (UnimplementedMothershipServer) DisableTOTP(context.Context, *DisableTOTPReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (UnimplementedMothershipServer) InviteUser(context.Context, *InviteUserReq) (*InvitationRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteUser not implemented")
+}
+func (UnimplementedMothershipServer) ListInvitations(context.Context, *empty.Empty) (*ListInvitationsRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvitations not implemented")
+}
+func (UnimplementedMothershipServer) RevokeInvitation(context.Context, *RevokeInvitationReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeInvitation not implemented")
+}
+func (UnimplementedMothershipServer) AcceptInvitation(context.Context, *AcceptInvitationReq) (*AcceptInvitationRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvitation not implemented")
+}
+func (UnimplementedMothershipServer) ListUsers(context.Context, *empty.Empty) (*ListUsersRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedMothershipServer) UpdateUser(context.Context, *UpdateUserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedMothershipServer) ChangeUserRole(context.Context, *ChangeUserRoleReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeUserRole not implemented")
+}
+func (UnimplementedMothershipServer) DeactivateUser(context.Context, *DeactivateUserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateUser not implemented")
+}
+func (UnimplementedMothershipServer) ActivateUser(context.Context, *ActivateUserReq) (*UserRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateUser not implemented")
+}
+func (UnimplementedMothershipServer) SuspendTenant(context.Context, *SuspendTenantReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuspendTenant not implemented")
+}
+func (UnimplementedMothershipServer) ReactivateTenant(context.Context, *ReactivateTenantReq) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReactivateTenant not implemented")
+}
+func (UnimplementedMothershipServer) GetTenant(context.Context, *empty.Empty) (*TenantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenant not implemented")
+}
+func (UnimplementedMothershipServer) UpdateTenant(context.Context, *UpdateTenantReq) (*TenantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTenant not implemented")
+}
+func (UnimplementedMothershipServer) DeleteTenant(context.Context, *DeleteTenantReq) (*DeleteTenantRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTenant not implemented")
+}
+func (UnimplementedMothershipServer) GetStorageConfig(context.Context, *empty.Empty) (*StorageConfigRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageConfig not implemented")
+}
+func (UnimplementedMothershipServer) UpdateStorageConfig(context.Context, *UpdateStorageConfigReq) (*StorageConfigRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStorageConfig not implemented")
+}
+func (UnimplementedMothershipServer) GetRetention(context.Context, *empty.Empty) (*RetentionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetention not implemented")
+}
+func (UnimplementedMothershipServer) UpdateRetention(context.Context, *UpdateRetentionReq) (*RetentionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRetention not implemented")
+}
+func (UnimplementedMothershipServer) mustEmbedUnimplementedMothershipServer() {}
+
+// UnsafeMothershipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MothershipServer will
+// result in compilation errors.
+type UnsafeMothershipServer interface {
+	mustEmbedUnimplementedMothershipServer()
+}
+
+func RegisterMothershipServer(s grpc.ServiceRegistrar, srv MothershipServer) {
+	s.RegisterService(&Mothership_ServiceDesc, srv)
+}
+
+func _Mothership_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/Register",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).Register(ctx, req.(*RegistrationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).Login(ctx, req.(*LoginReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/RefreshToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).RefreshToken(ctx, req.(*RefreshTokenReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_CompleteLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteLoginReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).CompleteLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/CompleteLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).CompleteLogin(ctx, req.(*CompleteLoginReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_InsertTimeSeriesDatum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSeriesDatumReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).InsertTimeSeriesDatum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/InsertTimeSeriesDatum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).InsertTimeSeriesDatum(ctx, req.(*TimeSeriesDatumReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_InsertTimeSeriesData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MothershipServer).InsertTimeSeriesData(&mothershipInsertTimeSeriesDataServer{stream})
+}
+
+type Mothership_InsertTimeSeriesDataServer interface {
+	SendAndClose(*empty.Empty) error
+	Recv() (*TimeSeriesDatumReq, error)
+	grpc.ServerStream
+}
+
+type mothershipInsertTimeSeriesDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *mothershipInsertTimeSeriesDataServer) SendAndClose(m *empty.Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mothershipInsertTimeSeriesDataServer) Recv() (*TimeSeriesDatumReq, error) {
+	m := new(TimeSeriesDatumReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Mothership_InsertBulkTimeSeriesData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkTimeSeriesDataReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).InsertBulkTimeSeriesData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/InsertBulkTimeSeriesData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).InsertBulkTimeSeriesData(ctx, req.(*BulkTimeSeriesDataReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_SelectBulkTimeSeriesData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilterReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).SelectBulkTimeSeriesData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/SelectBulkTimeSeriesData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).SelectBulkTimeSeriesData(ctx, req.(*FilterReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_StreamTimeSeriesData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTimeSeriesDataReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MothershipServer).StreamTimeSeriesData(m, &mothershipStreamTimeSeriesDataServer{stream})
+}
+
+type Mothership_StreamTimeSeriesDataServer interface {
+	Send(*DataPointsChunkRes) error
+	grpc.ServerStream
+}
+
+type mothershipStreamTimeSeriesDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *mothershipStreamTimeSeriesDataServer) Send(m *DataPointsChunkRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Mothership_SelectSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilterReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).SelectSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/SelectSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).SelectSeries(ctx, req.(*FilterReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetricsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListMetrics(ctx, req.(*ListMetricsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListLabelNames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelNamesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListLabelNames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListLabelNames",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListLabelNames(ctx, req.(*ListLabelNamesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListLabelValues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelValuesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListLabelValues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListLabelValues",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListLabelValues(ctx, req.(*ListLabelValuesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).Query(ctx, req.(*QueryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_QueryRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRangeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).QueryRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/QueryRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).QueryRange(ctx, req.(*QueryRangeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/CreateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).CreateAPIKey(ctx, req.(*CreateAPIKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListAPIKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListAPIKeys(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/RevokeAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).Logout(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListSessions(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/RevokeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).RevokeSession(ctx, req.(*RevokeSessionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_RevokeAllSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllSessionsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).RevokeAllSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/RevokeAllSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).RevokeAllSessions(ctx, req.(*RevokeAllSessionsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/RequestPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ConfirmPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPasswordResetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ConfirmPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ConfirmPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ConfirmPasswordReset(ctx, req.(*ConfirmPasswordResetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/VerifyEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).VerifyEmail(ctx, req.(*VerifyEmailReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ResendVerification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendVerificationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ResendVerification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ResendVerification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ResendVerification(ctx, req.(*ResendVerificationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/EnrollTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).EnrollTOTP(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmTOTPReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ConfirmTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ConfirmTOTP(ctx, req.(*ConfirmTOTPReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableTOTPReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/DisableTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).DisableTOTP(ctx, req.(*DisableTOTPReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_InviteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteUserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).InviteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/InviteUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).InviteUser(ctx, req.(*InviteUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListInvitations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListInvitations(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_RevokeInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeInvitationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).RevokeInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/RevokeInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).RevokeInvitation(ctx, req.(*RevokeInvitationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_AcceptInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInvitationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).AcceptInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/AcceptInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).AcceptInvitation(ctx, req.(*AcceptInvitationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ListUsers(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/UpdateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).UpdateUser(ctx, req.(*UpdateUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mothership_ChangeUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeUserRoleReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	m := new(empty.Empty)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(MothershipServer).ChangeUserRole(ctx, in)
 	}
-	return m, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ChangeUserRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ChangeUserRole(ctx, req.(*ChangeUserRoleReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *mothershipClient) InsertBulkTimeSeriesData(ctx context.Context, in *BulkTimeSeriesDataReq, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/proto.Mothership/InsertBulkTimeSeriesData", in, out, opts...)
-	if err != nil {
+func _Mothership_DeactivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateUserReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(MothershipServer).DeactivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/DeactivateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).DeactivateUser(ctx, req.(*DeactivateUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *mothershipClient) SelectBulkTimeSeriesData(ctx context.Context, in *FilterReq, opts ...grpc.CallOption) (*SelectBulkRes, error) {
-	out := new(SelectBulkRes)
-	err := c.cc.Invoke(ctx, "/proto.Mothership/SelectBulkTimeSeriesData", in, out, opts...)
-	if err != nil {
+func _Mothership_ActivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActivateUserReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// MothershipServer is the server API for Mothership service.
-// All implementations must embed UnimplementedMothershipServer
-// for forward compatibility
-type MothershipServer interface {
-	Register(context.Context, *RegistrationReq) (*RegistrationRes, error)
-	Login(context.Context, *LoginReq) (*LoginRes, error)
-	RefreshToken(context.Context, *RefreshTokenReq) (*RefreshTokenRes, error)
-	InsertTimeSeriesDatum(context.Context, *TimeSeriesDatumReq) (*empty.Empty, error)
-	InsertTimeSeriesData(Mothership_InsertTimeSeriesDataServer) error
-	InsertBulkTimeSeriesData(context.Context, *BulkTimeSeriesDataReq) (*empty.Empty, error)
-	SelectBulkTimeSeriesData(context.Context, *FilterReq) (*SelectBulkRes, error)
-	mustEmbedUnimplementedMothershipServer()
-}
-
-// UnimplementedMothershipServer must be embedded to have forward compatible implementations.
-type UnimplementedMothershipServer struct {
-}
-
-func (UnimplementedMothershipServer) Register(context.Context, *RegistrationReq) (*RegistrationRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
-}
-func (UnimplementedMothershipServer) Login(context.Context, *LoginReq) (*LoginRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
-}
-func (UnimplementedMothershipServer) RefreshToken(context.Context, *RefreshTokenReq) (*RefreshTokenRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
-}
-func (UnimplementedMothershipServer) InsertTimeSeriesDatum(context.Context, *TimeSeriesDatumReq) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method InsertTimeSeriesDatum not implemented")
-}
-func (UnimplementedMothershipServer) InsertTimeSeriesData(Mothership_InsertTimeSeriesDataServer) error {
-	return status.Errorf(codes.Unimplemented, "method InsertTimeSeriesData not implemented")
-}
-func (UnimplementedMothershipServer) InsertBulkTimeSeriesData(context.Context, *BulkTimeSeriesDataReq) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method InsertBulkTimeSeriesData not implemented")
-}
-func (UnimplementedMothershipServer) SelectBulkTimeSeriesData(context.Context, *FilterReq) (*SelectBulkRes, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SelectBulkTimeSeriesData not implemented")
-}
-func (UnimplementedMothershipServer) mustEmbedUnimplementedMothershipServer() {}
-
-// UnsafeMothershipServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MothershipServer will
-// result in compilation errors.
-type UnsafeMothershipServer interface {
-	mustEmbedUnimplementedMothershipServer()
-}
-
-func RegisterMothershipServer(s grpc.ServiceRegistrar, srv MothershipServer) {
-	s.RegisterService(&Mothership_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(MothershipServer).ActivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/ActivateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).ActivateUser(ctx, req.(*ActivateUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegistrationReq)
+func _Mothership_SuspendTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendTenantReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MothershipServer).Register(ctx, in)
+		return srv.(MothershipServer).SuspendTenant(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Mothership/Register",
+		FullMethod: "/proto.Mothership/SuspendTenant",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MothershipServer).Register(ctx, req.(*RegistrationReq))
+		return srv.(MothershipServer).SuspendTenant(ctx, req.(*SuspendTenantReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginReq)
+func _Mothership_ReactivateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReactivateTenantReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MothershipServer).Login(ctx, in)
+		return srv.(MothershipServer).ReactivateTenant(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Mothership/Login",
+		FullMethod: "/proto.Mothership/ReactivateTenant",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MothershipServer).Login(ctx, req.(*LoginReq))
+		return srv.(MothershipServer).ReactivateTenant(ctx, req.(*ReactivateTenantReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RefreshTokenReq)
+func _Mothership_GetTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MothershipServer).RefreshToken(ctx, in)
+		return srv.(MothershipServer).GetTenant(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Mothership/RefreshToken",
+		FullMethod: "/proto.Mothership/GetTenant",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MothershipServer).RefreshToken(ctx, req.(*RefreshTokenReq))
+		return srv.(MothershipServer).GetTenant(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_InsertTimeSeriesDatum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TimeSeriesDatumReq)
+func _Mothership_UpdateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTenantReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MothershipServer).InsertTimeSeriesDatum(ctx, in)
+		return srv.(MothershipServer).UpdateTenant(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Mothership/InsertTimeSeriesDatum",
+		FullMethod: "/proto.Mothership/UpdateTenant",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MothershipServer).InsertTimeSeriesDatum(ctx, req.(*TimeSeriesDatumReq))
+		return srv.(MothershipServer).UpdateTenant(ctx, req.(*UpdateTenantReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_InsertTimeSeriesData_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(MothershipServer).InsertTimeSeriesData(&mothershipInsertTimeSeriesDataServer{stream})
-}
-
-type Mothership_InsertTimeSeriesDataServer interface {
-	SendAndClose(*empty.Empty) error
-	Recv() (*TimeSeriesDatumReq, error)
-	grpc.ServerStream
-}
-
-type mothershipInsertTimeSeriesDataServer struct {
-	grpc.ServerStream
+func _Mothership_DeleteTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTenantReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).DeleteTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/DeleteTenant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).DeleteTenant(ctx, req.(*DeleteTenantReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *mothershipInsertTimeSeriesDataServer) SendAndClose(m *empty.Empty) error {
-	return x.ServerStream.SendMsg(m)
+func _Mothership_GetStorageConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MothershipServer).GetStorageConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/GetStorageConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).GetStorageConfig(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *mothershipInsertTimeSeriesDataServer) Recv() (*TimeSeriesDatumReq, error) {
-	m := new(TimeSeriesDatumReq)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _Mothership_UpdateStorageConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStorageConfigReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(MothershipServer).UpdateStorageConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Mothership/UpdateStorageConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MothershipServer).UpdateStorageConfig(ctx, req.(*UpdateStorageConfigReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_InsertBulkTimeSeriesData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BulkTimeSeriesDataReq)
+func _Mothership_GetRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MothershipServer).InsertBulkTimeSeriesData(ctx, in)
+		return srv.(MothershipServer).GetRetention(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Mothership/InsertBulkTimeSeriesData",
+		FullMethod: "/proto.Mothership/GetRetention",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MothershipServer).InsertBulkTimeSeriesData(ctx, req.(*BulkTimeSeriesDataReq))
+		return srv.(MothershipServer).GetRetention(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mothership_SelectBulkTimeSeriesData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FilterReq)
+func _Mothership_UpdateRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRetentionReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MothershipServer).SelectBulkTimeSeriesData(ctx, in)
+		return srv.(MothershipServer).UpdateRetention(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.Mothership/SelectBulkTimeSeriesData",
+		FullMethod: "/proto.Mothership/UpdateRetention",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MothershipServer).SelectBulkTimeSeriesData(ctx, req.(*FilterReq))
+		return srv.(MothershipServer).UpdateRetention(ctx, req.(*UpdateRetentionReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -329,6 +1635,10 @@ var Mothership_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RefreshToken",
 			Handler:    _Mothership_RefreshToken_Handler,
 		},
+		{
+			MethodName: "CompleteLogin",
+			Handler:    _Mothership_CompleteLogin_Handler,
+		},
 		{
 			MethodName: "InsertTimeSeriesDatum",
 			Handler:    _Mothership_InsertTimeSeriesDatum_Handler,
@@ -341,6 +1651,158 @@ var Mothership_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SelectBulkTimeSeriesData",
 			Handler:    _Mothership_SelectBulkTimeSeriesData_Handler,
 		},
+		{
+			MethodName: "SelectSeries",
+			Handler:    _Mothership_SelectSeries_Handler,
+		},
+		{
+			MethodName: "ListMetrics",
+			Handler:    _Mothership_ListMetrics_Handler,
+		},
+		{
+			MethodName: "ListLabelNames",
+			Handler:    _Mothership_ListLabelNames_Handler,
+		},
+		{
+			MethodName: "ListLabelValues",
+			Handler:    _Mothership_ListLabelValues_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _Mothership_Query_Handler,
+		},
+		{
+			MethodName: "QueryRange",
+			Handler:    _Mothership_QueryRange_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _Mothership_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _Mothership_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _Mothership_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Mothership_Logout_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Mothership_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Mothership_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllSessions",
+			Handler:    _Mothership_RevokeAllSessions_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _Mothership_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ConfirmPasswordReset",
+			Handler:    _Mothership_ConfirmPasswordReset_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _Mothership_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "ResendVerification",
+			Handler:    _Mothership_ResendVerification_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _Mothership_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _Mothership_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _Mothership_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "InviteUser",
+			Handler:    _Mothership_InviteUser_Handler,
+		},
+		{
+			MethodName: "ListInvitations",
+			Handler:    _Mothership_ListInvitations_Handler,
+		},
+		{
+			MethodName: "RevokeInvitation",
+			Handler:    _Mothership_RevokeInvitation_Handler,
+		},
+		{
+			MethodName: "AcceptInvitation",
+			Handler:    _Mothership_AcceptInvitation_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _Mothership_ListUsers_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _Mothership_UpdateUser_Handler,
+		},
+		{
+			MethodName: "ChangeUserRole",
+			Handler:    _Mothership_ChangeUserRole_Handler,
+		},
+		{
+			MethodName: "DeactivateUser",
+			Handler:    _Mothership_DeactivateUser_Handler,
+		},
+		{
+			MethodName: "ActivateUser",
+			Handler:    _Mothership_ActivateUser_Handler,
+		},
+		{
+			MethodName: "SuspendTenant",
+			Handler:    _Mothership_SuspendTenant_Handler,
+		},
+		{
+			MethodName: "ReactivateTenant",
+			Handler:    _Mothership_ReactivateTenant_Handler,
+		},
+		{
+			MethodName: "GetTenant",
+			Handler:    _Mothership_GetTenant_Handler,
+		},
+		{
+			MethodName: "UpdateTenant",
+			Handler:    _Mothership_UpdateTenant_Handler,
+		},
+		{
+			MethodName: "DeleteTenant",
+			Handler:    _Mothership_DeleteTenant_Handler,
+		},
+		{
+			MethodName: "GetStorageConfig",
+			Handler:    _Mothership_GetStorageConfig_Handler,
+		},
+		{
+			MethodName: "UpdateStorageConfig",
+			Handler:    _Mothership_UpdateStorageConfig_Handler,
+		},
+		{
+			MethodName: "GetRetention",
+			Handler:    _Mothership_GetRetention_Handler,
+		},
+		{
+			MethodName: "UpdateRetention",
+			Handler:    _Mothership_UpdateRetention_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -348,6 +1810,11 @@ var Mothership_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Mothership_InsertTimeSeriesData_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "StreamTimeSeriesData",
+			Handler:       _Mothership_StreamTimeSeriesData_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/mothership.proto",
 }
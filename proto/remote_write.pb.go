@@ -0,0 +1,305 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.9.1
+// source: proto/remote_write.proto
+
+package mothership_server
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The messages Prometheus sends to the `/api/v1/write` endpoint. Only the
+// fields we use are declared; their numbers must match `prompb.WriteRequest`
+// (see https://github.com/prometheus/prometheus/blob/main/prompb/remote.proto).
+type RemoteWriteReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timeseries []*RemoteTimeSeriesReq `protobuf:"bytes,1,rep,name=timeseries,proto3" json:"timeseries,omitempty"`
+}
+
+func (x *RemoteWriteReq) Reset() {
+	*x = RemoteWriteReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_remote_write_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteWriteReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteWriteReq) ProtoMessage() {}
+
+func (x *RemoteWriteReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_write_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteWriteReq.ProtoReflect.Descriptor instead.
+func (*RemoteWriteReq) Descriptor() ([]byte, []int) {
+	return file_proto_remote_write_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RemoteWriteReq) GetTimeseries() []*RemoteTimeSeriesReq {
+	if x != nil {
+		return x.Timeseries
+	}
+	return nil
+}
+
+type RemoteTimeSeriesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Labels  []*LabelReq        `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	Samples []*RemoteSampleReq `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (x *RemoteTimeSeriesReq) Reset() {
+	*x = RemoteTimeSeriesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_remote_write_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteTimeSeriesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteTimeSeriesReq) ProtoMessage() {}
+
+func (x *RemoteTimeSeriesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_write_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteTimeSeriesReq.ProtoReflect.Descriptor instead.
+func (*RemoteTimeSeriesReq) Descriptor() ([]byte, []int) {
+	return file_proto_remote_write_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RemoteTimeSeriesReq) GetLabels() []*LabelReq {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *RemoteTimeSeriesReq) GetSamples() []*RemoteSampleReq {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type RemoteSampleReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	// Unix timestamp in milliseconds.
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *RemoteSampleReq) Reset() {
+	*x = RemoteSampleReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_remote_write_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteSampleReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteSampleReq) ProtoMessage() {}
+
+func (x *RemoteSampleReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_remote_write_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteSampleReq.ProtoReflect.Descriptor instead.
+func (*RemoteSampleReq) Descriptor() ([]byte, []int) {
+	return file_proto_remote_write_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RemoteSampleReq) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *RemoteSampleReq) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+var File_proto_remote_write_proto protoreflect.FileDescriptor
+
+var file_proto_remote_write_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x77,
+	0x72, 0x69, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73,
+	0x68, 0x69, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4c, 0x0a, 0x0e, 0x52, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x57, 0x72, 0x69, 0x74, 0x65, 0x52, 0x65, 0x71, 0x12, 0x3a, 0x0a, 0x0a, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x52, 0x0a, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x27,
+	0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x52,
+	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0x45, 0x0a, 0x0f, 0x52, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62,
+	0x61, 0x72, 0x74, 0x6d, 0x69, 0x6b, 0x61, 0x2f, 0x6d, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_proto_remote_write_proto_rawDescOnce sync.Once
+	file_proto_remote_write_proto_rawDescData = file_proto_remote_write_proto_rawDesc
+)
+
+func file_proto_remote_write_proto_rawDescGZIP() []byte {
+	file_proto_remote_write_proto_rawDescOnce.Do(func() {
+		file_proto_remote_write_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_remote_write_proto_rawDescData)
+	})
+	return file_proto_remote_write_proto_rawDescData
+}
+
+var file_proto_remote_write_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_remote_write_proto_goTypes = []interface{}{
+	(*RemoteWriteReq)(nil),      // 0: proto.RemoteWriteReq
+	(*RemoteTimeSeriesReq)(nil), // 1: proto.RemoteTimeSeriesReq
+	(*RemoteSampleReq)(nil),     // 2: proto.RemoteSampleReq
+	(*LabelReq)(nil),            // 3: proto.LabelReq
+}
+var file_proto_remote_write_proto_depIdxs = []int32{
+	1, // 0: proto.RemoteWriteReq.timeseries:type_name -> proto.RemoteTimeSeriesReq
+	3, // 1: proto.RemoteTimeSeriesReq.labels:type_name -> proto.LabelReq
+	2, // 2: proto.RemoteTimeSeriesReq.samples:type_name -> proto.RemoteSampleReq
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_proto_remote_write_proto_init() }
+func file_proto_remote_write_proto_init() {
+	if File_proto_remote_write_proto != nil {
+		return
+	}
+	file_proto_mothership_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_proto_remote_write_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoteWriteReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_remote_write_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoteTimeSeriesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_remote_write_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoteSampleReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_remote_write_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_remote_write_proto_goTypes,
+		DependencyIndexes: file_proto_remote_write_proto_depIdxs,
+		MessageInfos:      file_proto_remote_write_proto_msgTypes,
+	}.Build()
+	File_proto_remote_write_proto = out.File
+	file_proto_remote_write_proto_rawDesc = nil
+	file_proto_remote_write_proto_goTypes = nil
+	file_proto_remote_write_proto_depIdxs = nil
+}
@@ -0,0 +1,23 @@
+CREATE TABLE api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    uuid VARCHAR (36) NOT NULL,
+    tenant_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    name VARCHAR (255) NOT NULL DEFAULT '',
+    prefix VARCHAR (31) NOT NULL,
+    key_hash VARCHAR (127) NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    state SMALLINT NOT NULL DEFAULT 0,
+    expiry_time TIMESTAMPTZ NULL,
+    last_used_time TIMESTAMPTZ NULL,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    modified_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+CREATE UNIQUE INDEX idx_api_key_uuid
+ON api_keys (uuid);
+CREATE UNIQUE INDEX idx_api_key_prefix
+ON api_keys (prefix);
+CREATE INDEX idx_api_key_tenant_id
+ON api_keys (tenant_id);
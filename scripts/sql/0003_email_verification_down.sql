@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN email_verification_code;
+ALTER TABLE users DROP COLUMN email_verification_expiry_time;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN email_verification_code VARCHAR (127) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN email_verification_expiry_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc');
+
+-- Accounts created before email verification existed are trusted as is.
+UPDATE users SET was_email_activated = TRUE;
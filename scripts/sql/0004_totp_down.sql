@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN totp_secret;
+ALTER TABLE users DROP COLUMN totp_enabled;
+ALTER TABLE users DROP COLUMN totp_recovery_codes;
+ALTER TABLE users DROP COLUMN totp_last_used_step;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR (127) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN totp_recovery_codes TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN totp_last_used_step BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,21 @@
+CREATE TABLE invitations (
+    id BIGSERIAL PRIMARY KEY,
+    uuid VARCHAR (36) NOT NULL,
+    tenant_id BIGINT NOT NULL,
+    invited_by_user_id BIGINT NOT NULL,
+    email VARCHAR (255) NOT NULL,
+    role_id SMALLINT NOT NULL DEFAULT 0,
+    token_hash VARCHAR (127) NOT NULL,
+    state SMALLINT NOT NULL DEFAULT 0,
+    expiry_time TIMESTAMPTZ NOT NULL,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    modified_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id),
+    FOREIGN KEY (invited_by_user_id) REFERENCES users(id)
+);
+CREATE UNIQUE INDEX idx_invitation_uuid
+ON invitations (uuid);
+CREATE UNIQUE INDEX idx_invitation_token_hash
+ON invitations (token_hash);
+CREATE INDEX idx_invitation_tenant_id
+ON invitations (tenant_id);
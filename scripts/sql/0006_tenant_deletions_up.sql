@@ -0,0 +1,16 @@
+-- DEVELOPERS NOTE:
+-- There is no foreign key to `tenants` because the tenant is gone by the time
+-- we write the record.
+CREATE TABLE tenant_deletions (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    tenant_uuid VARCHAR (36) NOT NULL,
+    tenant_name VARCHAR (255) NULL,
+    requested_by VARCHAR (255) NOT NULL,
+    users_count INTEGER NOT NULL DEFAULT 0,
+    api_keys_count INTEGER NOT NULL DEFAULT 0,
+    data_size BIGINT NOT NULL DEFAULT 0,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+CREATE INDEX idx_tenant_deletion_tenant_id
+ON tenant_deletions (tenant_id);
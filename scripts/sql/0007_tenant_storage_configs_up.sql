@@ -0,0 +1,12 @@
+-- DEVELOPERS NOTE:
+-- Tenants without a row use the defaults from `models.DefaultTenantStorageConfig`.
+CREATE TABLE tenant_storage_configs (
+    tenant_id BIGINT PRIMARY KEY,
+    partition_duration BIGINT NOT NULL,
+    timestamp_precision VARCHAR (2) NOT NULL DEFAULT 's',
+    write_timeout BIGINT NOT NULL,
+    retention BIGINT NOT NULL,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    modified_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id)
+);
@@ -0,0 +1,12 @@
+-- DEVELOPERS NOTE:
+-- Metrics without a row are kept for the retention of the tenant from
+-- `tenant_storage_configs`.
+CREATE TABLE metric_retention_policies (
+    tenant_id BIGINT NOT NULL,
+    metric VARCHAR (255) NOT NULL,
+    retention BIGINT NOT NULL,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    modified_time TIMESTAMPTZ NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    PRIMARY KEY (tenant_id, metric),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id)
+);
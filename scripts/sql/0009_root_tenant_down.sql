@@ -0,0 +1,2 @@
+DROP INDEX idx_tenant_is_root;
+ALTER TABLE tenants DROP COLUMN is_root;
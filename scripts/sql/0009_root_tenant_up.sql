@@ -0,0 +1,9 @@
+-- DEVELOPERS NOTE:
+-- The root tenant used to be found by its name, so mark the tenant our root
+-- users belong to.
+ALTER TABLE tenants ADD COLUMN is_root BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE tenants SET is_root = TRUE WHERE id = (
+    SELECT MIN(tenant_id) FROM users WHERE role_id = 1
+);
+CREATE UNIQUE INDEX idx_tenant_is_root
+ON tenants (is_root) WHERE is_root;